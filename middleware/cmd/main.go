@@ -1,48 +1,119 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"imperm-middleware/internal/api"
+	"imperm-middleware/internal/config"
 )
 
+// shutdownTimeout bounds how long requests in flight get to finish on shutdown
+const shutdownTimeout = 10 * time.Second
+
 func main() {
-	port := flag.String("port", "8080", "Port to run the server on")
-	mockMode := flag.Bool("mock", false, "Run in mock mode (simulated K8s data)")
-	k8sMode := flag.Bool("k8s", false, "Use direct Kubernetes API (instead of Terraform)")
+	configPath := flag.String("config", os.Getenv("IMPERM_CONFIG"), "Path to a YAML config file (default $IMPERM_CONFIG)")
+	printConfig := flag.Bool("print-config", false, "Print the effective configuration and exit")
+	flags := config.RegisterFlags(flag.CommandLine)
 	flag.Parse()
 
-	// Determine mode - Terraform is now the default
-	var mode api.HandlerMode
-	if *mockMode {
-		mode = api.ModeMock
-	} else if *k8sMode {
-		mode = api.ModeK8s
-	} else {
-		mode = api.ModeTerraform
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := flags.Apply(cfg); err != nil {
+		log.Fatal(err)
+	}
+	cfg.Resolve()
+	invalid := cfg.Validate()
+
+	if *printConfig {
+		out, err := cfg.YAML()
+		if err != nil {
+			log.Fatalf("Failed to render config: %v", err)
+		}
+		os.Stdout.Write(out)
+		if invalid != nil {
+			fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", invalid)
+			os.Exit(1)
+		}
+		return
+	}
+	if invalid != nil {
+		log.Fatalf("Invalid configuration:\n%v", invalid)
+	}
+
+	clusters, err := cfg.ClusterConfigs()
+	if err != nil {
+		log.Fatalf("Invalid clusters: %v", err)
 	}
 
-	// Create API handler
-	handler := api.NewHandler(mode)
+	// Closed on shutdown, stopping the background work below
+	stop := make(chan struct{})
+
+	// Create an API handler per cluster
+	handlers := api.NewClusters(cfg, clusters)
+	if cfg.Metrics.Interval > 0 {
+		handlers.Each(func(handler *api.Handler) {
+			handler.StartMetricsSampler(time.Duration(cfg.Metrics.Interval), time.Duration(cfg.Metrics.Retention))
+		})
+	}
+	handlers.Each(func(handler *api.Handler) {
+		handler.RecoverOperations()
+	})
+	if cfg.Drift.Interval > 0 {
+		handlers.Each(func(handler *api.Handler) {
+			handler.StartDriftChecks(time.Duration(cfg.Drift.Interval), cfg.Drift.Concurrency, stop)
+		})
+	}
 
 	// Setup routes
 	mux := http.NewServeMux()
-	handler.RegisterRoutes(mux)
+	handlers.RegisterRoutes(mux)
 
-	addr := fmt.Sprintf(":%s", *port)
-	fmt.Printf("Starting Imperm server on %s\n", addr)
+	fmt.Printf("Starting Imperm server on %s\n", cfg.Listen)
 
-	switch mode {
-	case api.ModeMock:
+	switch cfg.Mode {
+	case config.ModeMock:
 		fmt.Println("Running in MOCK mode - using simulated data")
-	case api.ModeTerraform:
+	case config.ModeTerraform:
 		fmt.Println("Running in TERRAFORM mode - provisioning with Terraform")
-	case api.ModeK8s:
+	case config.ModeK8s:
 		fmt.Println("Running in K8S mode - direct Kubernetes connection")
 	}
 
-	log.Fatal(http.ListenAndServe(addr, mux))
+	server := &http.Server{Addr: cfg.Listen, Handler: mux}
+	shutDown := make(chan struct{})
+	go func() {
+		defer close(shutDown)
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		<-signals
+		log.Println("Shutting down...")
+		close(stop)
+
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			log.Printf("Shutdown: %v", err)
+		}
+	}()
+
+	if cfg.TLS.CertFile != "" {
+		err = server.ListenAndServeTLS(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+	} else {
+		err = server.ListenAndServe()
+	}
+	if !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+	<-shutDown
 }
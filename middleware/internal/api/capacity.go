@@ -0,0 +1,40 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"imperm-middleware/internal/capacity"
+	"imperm-middleware/pkg/models"
+)
+
+// handleEstimateEnvironment reports whether an environment created with the
+// given options would fit in the cluster, and what it would cost
+func (h *Handler) handleEstimateEnvironment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Options *models.DeploymentOptions `json:"options"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	cluster, err := h.client.GetClusterCapacity()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	estimate, err := capacity.Estimate(req.Options, cluster, h.pricing)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, estimate)
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"net/http"
+
+	"imperm-middleware/internal/config"
+	"imperm-middleware/internal/k8s"
+	"imperm-middleware/pkg/models"
+)
+
+// Clusters routes every API request to the handler of the cluster named by
+// its cluster query parameter, or the first cluster when there is none
+type Clusters struct {
+	clusters []models.Cluster
+	handlers map[string]*Handler
+	muxes    map[string]*http.ServeMux
+}
+
+// NewClusters creates a handler for each configured cluster, in order
+func NewClusters(cfg *config.Config, configs []k8s.ClusterConfig) *Clusters {
+	c := &Clusters{
+		handlers: make(map[string]*Handler, len(configs)),
+		muxes:    make(map[string]*http.ServeMux, len(configs)),
+	}
+	for i, cluster := range configs {
+		handler := NewHandler(cfg, cluster, i == 0)
+		mux := http.NewServeMux()
+		handler.RegisterRoutes(mux)
+
+		c.clusters = append(c.clusters, models.Cluster{Name: cluster.Name, Context: cluster.Context, Default: i == 0})
+		c.handlers[cluster.Name] = handler
+		c.muxes[cluster.Name] = mux
+	}
+	return c
+}
+
+// Each calls fn with the handler of every cluster, e.g. to configure them all alike
+func (c *Clusters) Each(fn func(h *Handler)) {
+	for _, cluster := range c.clusters {
+		fn(c.handlers[cluster.Name])
+	}
+}
+
+// RegisterRoutes routes all API requests through the cluster handlers
+func (c *Clusters) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/clusters", c.handleClusters)
+	mux.HandleFunc("/", c.route)
+}
+
+func (c *Clusters) handleClusters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	respondJSON(w, c.clusters)
+}
+
+func (c *Clusters) route(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("cluster")
+	if name == "" {
+		name = c.clusters[0].Name
+	}
+
+	mux, ok := c.muxes[name]
+	if !ok {
+		http.Error(w, "Unknown cluster: "+name, http.StatusNotFound)
+		return
+	}
+	mux.ServeHTTP(w, r)
+}
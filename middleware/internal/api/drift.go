@@ -0,0 +1,95 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"imperm-middleware/pkg/models"
+)
+
+// driftDetector is implemented by clients that keep environments in Terraform
+// state, which can drift from what is actually running
+type driftDetector interface {
+	StartDriftChecks(interval time.Duration, concurrency int, stop <-chan struct{})
+	DriftResults() ([]models.DriftStatus, error)
+	CheckDrift(name string) (*models.DriftStatus, error)
+	ReconcileEnvironment(name string) error
+}
+
+// StartDriftChecks begins checking environments for drift in the background,
+// if the client keeps them in Terraform state, until stop is closed
+func (h *Handler) StartDriftChecks(interval time.Duration, concurrency int, stop <-chan struct{}) {
+	detector, ok := h.client.(driftDetector)
+	if !ok {
+		return
+	}
+	detector.StartDriftChecks(interval, concurrency, stop)
+	log.Printf("Checking environments of cluster %s for drift every %s, %d at a time", h.cluster, interval, concurrency)
+}
+
+// driftDetector returns the client's drift detection, answering 501 when it has none
+func (h *Handler) driftDetector(w http.ResponseWriter) (driftDetector, bool) {
+	detector, ok := h.client.(driftDetector)
+	if !ok {
+		http.Error(w, "Drift detection needs Terraform mode", http.StatusNotImplemented)
+	}
+	return detector, ok
+}
+
+// handleDrift returns the latest drift check of every environment
+func (h *Handler) handleDrift(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	detector, ok := h.driftDetector(w)
+	if !ok {
+		return
+	}
+
+	results, err := detector.DriftResults()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	respondJSON(w, results)
+}
+
+// handleCheckDrift checks one environment for drift right away
+func (h *Handler) handleCheckDrift(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	detector, ok := h.driftDetector(w)
+	if !ok {
+		return
+	}
+
+	status, err := detector.CheckDrift(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	respondJSON(w, status)
+}
+
+// handleReconcileEnvironment re-applies an environment's Terraform
+// configuration, undoing changes made outside it
+func (h *Handler) handleReconcileEnvironment(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	detector, ok := h.driftDetector(w)
+	if !ok {
+		return
+	}
+
+	if err := detector.ReconcileEnvironment(name); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	respondJSON(w, map[string]string{"status": "reconciled"})
+}
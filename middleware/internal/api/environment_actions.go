@@ -0,0 +1,172 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"imperm-middleware/internal/k8s"
+	"imperm-middleware/pkg/models"
+)
+
+// maxManifestSize bounds the body of an apply request
+const maxManifestSize = 8 << 20
+
+// handleEnvironmentAction routes /api/environments/{name}/{action}
+func (h *Handler) handleEnvironmentAction(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/environments/"), "/")
+	if len(parts) != 2 || parts[0] == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	name, action := parts[0], parts[1]
+	switch action {
+	case "adopt":
+		h.handleAdoptEnvironment(w, r, name)
+	case "apply":
+		h.handleApplyManifest(w, r, name)
+	case "clone":
+		h.handleCloneEnvironment(w, r, name)
+	case "drift":
+		h.handleCheckDrift(w, r, name)
+	case "events":
+		h.handleEnvironmentEvents(w, r, name)
+	case "reconcile":
+		h.handleReconcileEnvironment(w, r, name)
+	case "resume":
+		h.handleResumeEnvironment(w, r, name)
+	case "rollback":
+		h.handleRollbackEnvironment(w, r, name)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handleCloneEnvironment creates a new environment from the stored options of
+// an existing one, with the request's variables overriding the copied ones
+func (h *Handler) handleCloneEnvironment(w http.ResponseWriter, r *http.Request, sourceName string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.CloneEnvironmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Name == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return
+	}
+	if req.Name == sourceName {
+		http.Error(w, "name must differ from the source environment", http.StatusBadRequest)
+		return
+	}
+
+	options, err := h.client.CloneEnvironment(sourceName, req)
+	if err != nil {
+		http.Error(w, err.Error(), createErrorStatus(err))
+		return
+	}
+
+	respondJSON(w, options)
+}
+
+// handleAdoptEnvironment brings an existing namespace under imperm
+// management and returns the options detected from its workloads
+func (h *Handler) handleAdoptEnvironment(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	options, err := h.client.AdoptEnvironment(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, options)
+}
+
+// handleEnvironmentEvents returns an environment's event timeline, optionally
+// filtered by ?type=Warning|Normal and ?kind=Pod etc.
+func (h *Handler) handleEnvironmentEvents(w http.ResponseWriter, r *http.Request, envName string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	filter := models.EventFilter{
+		Type: r.URL.Query().Get("type"),
+		Kind: r.URL.Query().Get("kind"),
+	}
+	if filter.Type != "" && !strings.EqualFold(filter.Type, "Normal") && !strings.EqualFold(filter.Type, "Warning") {
+		http.Error(w, "type must be Normal or Warning", http.StatusBadRequest)
+		return
+	}
+
+	events, err := h.client.GetEnvironmentEvents(envName, filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, events)
+}
+
+// handleApplyManifest applies YAML into an environment. The body is either a
+// JSON models.ApplyManifestRequest or raw YAML with ?dryRun=true for a dry run.
+func (h *Handler) handleApplyManifest(w http.ResponseWriter, r *http.Request, envName string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxManifestSize))
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ApplyManifestRequest
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	} else {
+		req.Manifest = string(body)
+		req.DryRun = r.URL.Query().Get("dryRun") == "true"
+	}
+
+	if strings.TrimSpace(req.Manifest) == "" {
+		http.Error(w, "manifest is required", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.client.ApplyManifest(envName, req)
+	if err != nil {
+		http.Error(w, err.Error(), applyErrorStatus(err))
+		return
+	}
+
+	respondJSON(w, result)
+}
+
+// applyErrorStatus maps a failed apply to its status code: environments
+// discovery doesn't find are not found, unmanaged ones are forbidden
+func applyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, k8s.ErrUnknownEnvironment):
+		return http.StatusNotFound
+	case errors.Is(err, k8s.ErrUnmanagedEnvironment):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+
+	"imperm-middleware/pkg/client"
+	"imperm-middleware/pkg/models"
+
+	"github.com/gorilla/websocket"
+)
+
+// defaultExecCommand starts bash when the image has it and falls back to sh
+var defaultExecCommand = []string{"/bin/sh", "-c", "command -v bash >/dev/null 2>&1 && exec bash || exec sh"}
+
+// wsUpgrader upgrades the streaming endpoints (exec, port-forward) to WebSockets
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+}
+
+// handleExecPod upgrades the request to a WebSocket and attaches it to a
+// command running in a pod container. Frames are prefixed with a channel byte
+// (see models.ExecChannelStdin and friends).
+func (h *Handler) handleExecPod(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	req := models.ExecRequest{
+		Namespace: query.Get("namespace"),
+		Pod:       query.Get("pod"),
+		Container: query.Get("container"),
+		Command:   query["command"],
+		TTY:       query.Get("tty") != "false",
+	}
+
+	if req.Namespace == "" || req.Pod == "" {
+		http.Error(w, "namespace and pod parameters are required", http.StatusBadRequest)
+		return
+	}
+	if len(req.Command) == 0 {
+		req.Command = defaultExecCommand
+	}
+
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already written an error response
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	stdinReader, stdinWriter := io.Pipe()
+	resize := make(chan models.TerminalSize, 4)
+	var writeMu sync.Mutex
+
+	// Read client frames until the connection closes
+	go func() {
+		defer cancel()
+		defer close(resize)
+		defer stdinWriter.Close()
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil || len(data) == 0 {
+				return
+			}
+			switch data[0] {
+			case models.ExecChannelStdin:
+				if _, err := stdinWriter.Write(data[1:]); err != nil {
+					return
+				}
+			case models.ExecChannelResize:
+				var size models.TerminalSize
+				if json.Unmarshal(data[1:], &size) != nil {
+					continue
+				}
+				select {
+				case resize <- size:
+				default: // Drop sizes the session hasn't caught up with
+				}
+			}
+		}
+	}()
+
+	streams := client.ExecStreams{
+		Stdin:  stdinReader,
+		Stdout: &execFrameWriter{conn: conn, mu: &writeMu, channel: models.ExecChannelStdout},
+		Stderr: &execFrameWriter{conn: conn, mu: &writeMu, channel: models.ExecChannelStderr},
+		Resize: resize,
+	}
+
+	status := ""
+	if err := h.client.ExecPod(ctx, req, streams); err != nil && ctx.Err() == nil {
+		log.Printf("Exec in %s/%s failed: %v", req.Namespace, req.Pod, err)
+		status = err.Error()
+	}
+	stdinReader.Close()
+
+	writeMu.Lock()
+	conn.WriteMessage(websocket.BinaryMessage, append([]byte{models.ExecChannelStatus}, status...))
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	writeMu.Unlock()
+}
+
+// execFrameWriter writes output to the WebSocket as frames on one channel
+type execFrameWriter struct {
+	conn    *websocket.Conn
+	mu      *sync.Mutex
+	channel byte
+}
+
+func (w *execFrameWriter) Write(p []byte) (int, error) {
+	frame := make([]byte, len(p)+1)
+	frame[0] = w.channel
+	copy(frame[1:], p)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.conn.WriteMessage(websocket.BinaryMessage, frame); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
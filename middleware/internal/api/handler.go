@@ -4,45 +4,63 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
-	"os"
 	"path/filepath"
+	"time"
 
+	"imperm-middleware/internal/capacity"
+	"imperm-middleware/internal/config"
 	"imperm-middleware/internal/k8s"
+	"imperm-middleware/internal/metrics"
 	"imperm-middleware/internal/terraform"
 	"imperm-middleware/pkg/client"
 	"imperm-middleware/pkg/models"
 )
 
 type Handler struct {
-	client client.Client
-}
-
-type HandlerMode string
+	client  client.Client
+	cluster string           // Name of the cluster the client talks to
+	metrics *metrics.Sampler // Usage history, nil until StartMetricsSampler is called
 
-const (
-	ModeMock      HandlerMode = "mock"
-	ModeK8s       HandlerMode = "k8s"
-	ModeTerraform HandlerMode = "terraform"
-)
+	quotaDefaults map[string]string // Quota variables of environments that set none
+	pricing       capacity.Pricing  // Rates capacity estimates are priced at
+}
 
-func NewHandler(mode HandlerMode) *Handler {
+// NewHandler creates the handler of one cluster. The primary cluster keeps its
+// Terraform state in terraform/environments, any other under
+// terraform/clusters/<name>/environments so environment names can repeat
+// across clusters.
+func NewHandler(cfg *config.Config, cluster k8s.ClusterConfig, primary bool) *Handler {
 	var c client.Client
 
-	switch mode {
-	case ModeMock:
-		log.Println("Initializing mock client...")
+	switch cfg.Mode {
+	case config.ModeMock:
+		log.Printf("Initializing mock client for cluster %s...", cluster.Name)
 		c = client.NewMockClient()
 
-	case ModeTerraform:
-		log.Println("Initializing Terraform client...")
+	case config.ModeTerraform:
+		log.Printf("Initializing Terraform client for cluster %s...", cluster.Name)
 
 		// Get paths
-		projectRoot := getProjectRoot()
-		terraformDir := filepath.Join(projectRoot, "terraform", "environments")
-		modulePath := filepath.Join(projectRoot, "terraform", "modules", "k8s-namespace")
-		kubeconfig := getKubeconfig()
+		terraformDir := filepath.Join(cfg.Terraform.WorkingDir, "environments")
+		if !primary {
+			terraformDir = filepath.Join(cfg.Terraform.WorkingDir, "clusters", cluster.Name, "environments")
+		}
+		kubeconfig := cluster.Kubeconfig
+		if kubeconfig == "" {
+			kubeconfig = config.DefaultKubeconfig()
+		}
 
-		tfClient, err := terraform.NewClient(terraformDir, modulePath, kubeconfig)
+		tfClient, err := terraform.NewClient(terraform.Options{
+			Binary:     cfg.Terraform.Binary,
+			BaseDir:    terraformDir,
+			ModulePath: cfg.Terraform.ModulePath,
+			Kubeconfig: kubeconfig,
+			Backend:    cfg.TerraformBackend(cluster.Name, primary),
+			Providers:  cfg.TerraformProviders(),
+
+			LogRetention: time.Duration(cfg.Logs.Retention),
+			LogMaxLines:  cfg.Logs.MaxLines,
+		}, cluster)
 		if err != nil {
 			log.Fatalf("Failed to create Terraform client: %v", err)
 		}
@@ -50,8 +68,8 @@ func NewHandler(mode HandlerMode) *Handler {
 		log.Println("Successfully initialized Terraform client")
 
 	default: // ModeK8s
-		log.Println("Initializing Kubernetes client...")
-		k8sClient, err := k8s.NewClient()
+		log.Printf("Initializing Kubernetes client for cluster %s...", cluster.Name)
+		k8sClient, err := k8s.NewClientForCluster(cluster)
 		if err != nil {
 			log.Fatalf("Failed to create Kubernetes client: %v", err)
 		}
@@ -60,56 +78,11 @@ func NewHandler(mode HandlerMode) *Handler {
 	}
 
 	return &Handler{
-		client: c,
-	}
-}
-
-// getProjectRoot returns the project root directory
-func getProjectRoot() string {
-	// Try to get from environment variable first
-	if root := os.Getenv("IMPERM_PROJECT_ROOT"); root != "" {
-		return root
-	}
-
-	// Otherwise use current working directory and go up until we find terraform/
-	cwd, err := os.Getwd()
-	if err != nil {
-		log.Printf("Warning: failed to get working directory: %v", err)
-		return "."
-	}
-
-	// Walk up the directory tree looking for the terraform directory
-	dir := cwd
-	for {
-		tfDir := filepath.Join(dir, "terraform")
-		if _, err := os.Stat(tfDir); err == nil {
-			return dir
-		}
-
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			// Reached root, use cwd
-			return cwd
-		}
-		dir = parent
-	}
-}
-
-// getKubeconfig returns the path to the kubeconfig file
-func getKubeconfig() string {
-	// Try KUBECONFIG environment variable first
-	if kubeconfig := os.Getenv("KUBECONFIG"); kubeconfig != "" {
-		return kubeconfig
-	}
-
-	// Default to ~/.kube/config
-	home, err := os.UserHomeDir()
-	if err != nil {
-		log.Printf("Warning: failed to get home directory: %v", err)
-		return ""
+		client:        c,
+		cluster:       cluster.Name,
+		quotaDefaults: cfg.QuotaDefaults,
+		pricing:       cfg.Pricing(),
 	}
-
-	return filepath.Join(home, ".kube", "config")
 }
 
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
@@ -118,21 +91,52 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/environments/create", h.handleCreateEnvironment)
 	mux.HandleFunc("/api/environments/destroy", h.handleDestroyEnvironment)
 	mux.HandleFunc("/api/environments/history", h.handleEnvironmentHistory)
+	mux.HandleFunc("/api/environments/estimate", h.handleEstimateEnvironment)
+	mux.HandleFunc("/api/environments/", h.handleEnvironmentAction)
+	mux.HandleFunc("/api/drift", h.handleDrift)
 
 	// Pod endpoints
 	mux.HandleFunc("/api/pods", h.handlePods)
 	mux.HandleFunc("/api/pods/logs", h.handlePodLogs)
 	mux.HandleFunc("/api/pods/events", h.handlePodEvents)
+	mux.HandleFunc("/api/pods/exec", h.handleExecPod)
 
 	// Deployment endpoints
 	mux.HandleFunc("/api/deployments", h.handleDeployments)
 	mux.HandleFunc("/api/deployments/events", h.handleDeploymentEvents)
+	mux.HandleFunc("/api/deployments/scale", h.handleScaleDeployment)
+	mux.HandleFunc("/api/deployments/restart", h.handleRestartDeployment)
+	mux.HandleFunc("/api/deployments/pause", h.handlePauseDeployment)
+	mux.HandleFunc("/api/deployments/resume", h.handleResumeDeployment)
+	mux.HandleFunc("/api/deployments/history", h.handleDeploymentHistory)
+	mux.HandleFunc("/api/deployments/rollback", h.handleRollbackDeployment)
+
+	// Service, config and workload endpoints
+	mux.HandleFunc("/api/services", listHandler(h.client.ListServices))
+	mux.HandleFunc("/api/services/get", getHandler(h.client.GetService))
+	mux.HandleFunc("/api/configmaps", listHandler(h.client.ListConfigMaps))
+	mux.HandleFunc("/api/configmaps/get", getHandler(h.client.GetConfigMap))
+	mux.HandleFunc("/api/secrets", listHandler(h.client.ListSecrets))
+	mux.HandleFunc("/api/secrets/get", h.handleGetSecret)
+	mux.HandleFunc("/api/ingresses", listHandler(h.client.ListIngresses))
+	mux.HandleFunc("/api/ingresses/get", getHandler(h.client.GetIngress))
+	mux.HandleFunc("/api/jobs", listHandler(h.client.ListJobs))
+	mux.HandleFunc("/api/jobs/get", getHandler(h.client.GetJob))
+	mux.HandleFunc("/api/statefulsets", listHandler(h.client.ListStatefulSets))
+	mux.HandleFunc("/api/statefulsets/get", getHandler(h.client.GetStatefulSet))
+	mux.HandleFunc("/api/resources/", h.handleResourceManifest)
+
+	// Port forwarding
+	mux.HandleFunc("/api/portforwards", h.handlePortForwards)
+	mux.HandleFunc("/api/portforwards/connect", h.handlePortForwardConnect)
 
 	// Stats endpoints
 	mux.HandleFunc("/api/stats", h.handleStats)
+	mux.HandleFunc("/api/metrics/history", h.handleMetricsHistory)
 
 	// Terraform operation logs
 	mux.HandleFunc("/api/operations/logs", h.handleOperationLogs)
+	mux.HandleFunc("/api/operations/interrupted", h.handleInterruptedOperations)
 
 	// Health check
 	mux.HandleFunc("/health", h.handleHealth)
@@ -144,11 +148,16 @@ func (h *Handler) handleEnvironments(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	envs, err := h.client.ListEnvironments()
+	// Unmanaged namespaces are only listed when asked for with unmanaged=true
+	filter := models.EnvironmentFilter{Unmanaged: r.URL.Query().Get("unmanaged") == "true"}
+	envs, err := h.client.ListEnvironments(filter)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	for i := range envs {
+		envs[i].Cluster = h.cluster
+	}
 
 	respondJSON(w, envs)
 }
@@ -178,9 +187,11 @@ func (h *Handler) handleCreateEnvironment(w http.ResponseWriter, r *http.Request
 		}
 	}
 
+	req.Options = h.withQuotaDefaults(req.Name, req.Options)
+
 	err := h.client.CreateEnvironment(req.Name, req.Options)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), createErrorStatus(err))
 		return
 	}
 
@@ -362,6 +373,172 @@ func (h *Handler) handleDeploymentEvents(w http.ResponseWriter, r *http.Request)
 	respondJSON(w, events)
 }
 
+func (h *Handler) handleScaleDeployment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Namespace  string `json:"namespace"`
+		Deployment string `json:"deployment"`
+		Replicas   *int   `json:"replicas"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Namespace == "" || req.Deployment == "" || req.Replicas == nil {
+		http.Error(w, "namespace, deployment and replicas are required", http.StatusBadRequest)
+		return
+	}
+
+	if *req.Replicas < 0 {
+		http.Error(w, "replicas must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	err := h.client.ScaleDeployment(req.Namespace, req.Deployment, *req.Replicas)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, map[string]interface{}{
+		"status":   "scaled",
+		"replicas": *req.Replicas,
+	})
+}
+
+// deploymentRequest is the body accepted by the deployment rollout endpoints
+type deploymentRequest struct {
+	Namespace  string `json:"namespace"`
+	Deployment string `json:"deployment"`
+}
+
+// decodeDeploymentRequest reads and validates a deploymentRequest, writing an error response on failure
+func decodeDeploymentRequest(w http.ResponseWriter, r *http.Request) (*deploymentRequest, bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return nil, false
+	}
+
+	var req deploymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return nil, false
+	}
+
+	if req.Namespace == "" || req.Deployment == "" {
+		http.Error(w, "namespace and deployment are required", http.StatusBadRequest)
+		return nil, false
+	}
+
+	return &req, true
+}
+
+func (h *Handler) handleRestartDeployment(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeDeploymentRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.client.RestartDeployment(req.Namespace, req.Deployment); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, map[string]string{"status": "restarted"})
+}
+
+func (h *Handler) handlePauseDeployment(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeDeploymentRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.client.PauseDeployment(req.Namespace, req.Deployment); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, map[string]string{"status": "paused"})
+}
+
+func (h *Handler) handleResumeDeployment(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeDeploymentRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.client.ResumeDeployment(req.Namespace, req.Deployment); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, map[string]string{"status": "resumed"})
+}
+
+func (h *Handler) handleDeploymentHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	namespace := r.URL.Query().Get("namespace")
+	deploymentName := r.URL.Query().Get("deployment")
+
+	if namespace == "" || deploymentName == "" {
+		http.Error(w, "namespace and deployment parameters are required", http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.client.GetDeploymentHistory(namespace, deploymentName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, history)
+}
+
+func (h *Handler) handleRollbackDeployment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Namespace  string `json:"namespace"`
+		Deployment string `json:"deployment"`
+		Revision   int64  `json:"revision"` // 0 rolls back to the previous revision
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Namespace == "" || req.Deployment == "" {
+		http.Error(w, "namespace and deployment are required", http.StatusBadRequest)
+		return
+	}
+
+	if req.Revision < 0 {
+		http.Error(w, "revision must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.client.RollbackDeployment(req.Namespace, req.Deployment, req.Revision); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, map[string]string{"status": "rolled back"})
+}
+
 func (h *Handler) handleStats(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -391,6 +568,12 @@ func (h *Handler) handleHealth(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// operationLogger is implemented by clients that run Terraform operations,
+// keeping the logs of those on their cluster's environments
+type operationLogger interface {
+	Logs() *terraform.LogStore
+}
+
 func (h *Handler) handleOperationLogs(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -403,8 +586,10 @@ func (h *Handler) handleOperationLogs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	logStore := terraform.GetLogStore()
-	opLog := logStore.GetOperation(envName)
+	var opLog *terraform.OperationLog
+	if logger, ok := h.client.(operationLogger); ok {
+		opLog = logger.Logs().GetOperation(envName)
+	}
 
 	if opLog == nil {
 		respondJSON(w, map[string]interface{}{
@@ -429,6 +614,8 @@ func (h *Handler) handleOperationLogs(w http.ResponseWriter, r *http.Request) {
 		"end_time":    opLog.EndTime,
 		"error":       opLog.Error,
 		"logs":        logStrings,
+		"events":      opLog.GetEvents(),
+		"progress":    opLog.GetProgress(),
 	})
 }
 
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"imperm-middleware/internal/metrics"
+)
+
+// StartMetricsSampler begins sampling pod usage in the background, keeping
+// retention worth of samples taken every interval
+func (h *Handler) StartMetricsSampler(interval, retention time.Duration) {
+	h.metrics = metrics.NewSampler(h.client.GetPodMetrics, interval, retention)
+	go h.metrics.Run(nil)
+	log.Printf("Sampling pod metrics every %s, keeping %s of history", interval, retention)
+}
+
+// handleMetricsHistory returns the sampled usage of a pod, or of a whole
+// namespace when no pod is given
+func (h *Handler) handleMetricsHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	namespace := r.URL.Query().Get("namespace")
+	if namespace == "" {
+		http.Error(w, "namespace parameter is required", http.StatusBadRequest)
+		return
+	}
+	if h.metrics == nil {
+		http.Error(w, "metrics sampling is disabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	respondJSON(w, h.metrics.History(namespace, r.URL.Query().Get("pod")))
+}
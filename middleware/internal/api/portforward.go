@@ -0,0 +1,121 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"imperm-middleware/pkg/models"
+
+	"github.com/gorilla/websocket"
+)
+
+// handlePortForwards lists (GET), opens (POST) and closes (DELETE) port-forward sessions
+func (h *Handler) handlePortForwards(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		forwards, err := h.client.ListPortForwards()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		respondJSON(w, forwards)
+
+	case http.MethodPost:
+		var req models.PortForwardRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.Namespace == "" || req.Name == "" {
+			http.Error(w, "namespace and name are required", http.StatusBadRequest)
+			return
+		}
+		// Only a service has a first port for 0 to select
+		if req.Port < 0 || req.Port > 65535 || (req.Port == 0 && req.Kind != models.PortForwardKindService) {
+			http.Error(w, "port must be between 1 and 65535, or 0 for a service's first port", http.StatusBadRequest)
+			return
+		}
+
+		forward, err := h.client.StartPortForward(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		log.Printf("Port forward %s opened to %s %s/%s:%d", forward.ID, forward.Kind, forward.Namespace, forward.Name, forward.Port)
+		respondJSON(w, forward)
+
+	case http.MethodDelete:
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			http.Error(w, "id parameter is required", http.StatusBadRequest)
+			return
+		}
+		if err := h.client.StopPortForward(id); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		respondJSON(w, map[string]string{"status": "stopped", "id": id})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handlePortForwardConnect upgrades to a WebSocket carrying one TCP connection
+// through a port-forward session. Each binary frame is raw stream data.
+func (h *Handler) handlePortForwardConnect(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "id parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	// Dial before upgrading so failures reach the client as a normal HTTP error
+	target, err := h.client.DialPortForward(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer target.Close()
+
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	// Client -> target
+	go func() {
+		defer target.Close()
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if _, err := target.Write(data); err != nil {
+				return
+			}
+		}
+	}()
+
+	// Target -> client
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := target.Read(buf)
+		if n > 0 {
+			if werr := conn.WriteMessage(websocket.BinaryMessage, buf[:n]); werr != nil {
+				return
+			}
+		}
+		if err != nil {
+			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			return
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"imperm-middleware/internal/capacity"
+	"imperm-middleware/pkg/models"
+)
+
+// withQuotaDefaults fills in the server's quota defaults, creating options if there are none
+func (h *Handler) withQuotaDefaults(name string, options *models.DeploymentOptions) *models.DeploymentOptions {
+	if len(h.quotaDefaults) == 0 {
+		return options
+	}
+	if options == nil {
+		options = &models.DeploymentOptions{Name: name}
+	}
+	options.ApplyDefaults(h.quotaDefaults)
+	return options
+}
+
+// createErrorStatus maps a failed create to its status code: like the API
+// server, a request over quota is forbidden rather than a server error
+func createErrorStatus(err error) int {
+	var exceeded *capacity.QuotaExceededError
+	if errors.As(err, &exceeded) {
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
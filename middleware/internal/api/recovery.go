@@ -0,0 +1,99 @@
+package api
+
+import (
+	"log"
+	"net/http"
+
+	"imperm-middleware/pkg/models"
+)
+
+// operationRecoverer is implemented by clients whose operations outlive the
+// request that started them and can be cut short by a server restart
+type operationRecoverer interface {
+	RecoverOperations() ([]models.InterruptedOperation, error)
+	InterruptedOperations() ([]models.InterruptedOperation, error)
+	ResumeEnvironment(name string) error
+	RollbackEnvironment(name string) error
+}
+
+// RecoverOperations looks for operations the last run of the server didn't
+// finish, if the client keeps any, and reports them
+func (h *Handler) RecoverOperations() {
+	recoverer, ok := h.client.(operationRecoverer)
+	if !ok {
+		return
+	}
+	interrupted, err := recoverer.RecoverOperations()
+	if err != nil {
+		log.Printf("Failed to recover operations of cluster %s: %v", h.cluster, err)
+		return
+	}
+	for _, op := range interrupted {
+		log.Printf("Found interrupted %s of environment %s in cluster %s; resume or roll it back", op.Operation, op.Environment, h.cluster)
+	}
+}
+
+// operationRecoverer returns the client's operation recovery, answering 501 when it has none
+func (h *Handler) operationRecoverer(w http.ResponseWriter) (operationRecoverer, bool) {
+	recoverer, ok := h.client.(operationRecoverer)
+	if !ok {
+		http.Error(w, "Operation recovery needs Terraform mode", http.StatusNotImplemented)
+	}
+	return recoverer, ok
+}
+
+// handleInterruptedOperations returns the operations found interrupted on
+// startup that haven't been resumed or rolled back yet
+func (h *Handler) handleInterruptedOperations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	recoverer, ok := h.operationRecoverer(w)
+	if !ok {
+		return
+	}
+
+	interrupted, err := recoverer.InterruptedOperations()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	respondJSON(w, interrupted)
+}
+
+// handleResumeEnvironment finishes an environment's interrupted operation
+func (h *Handler) handleResumeEnvironment(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	recoverer, ok := h.operationRecoverer(w)
+	if !ok {
+		return
+	}
+
+	if err := recoverer.ResumeEnvironment(name); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	respondJSON(w, map[string]string{"status": "resumed"})
+}
+
+// handleRollbackEnvironment undoes an environment's interrupted operation
+func (h *Handler) handleRollbackEnvironment(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	recoverer, ok := h.operationRecoverer(w)
+	if !ok {
+		return
+	}
+
+	if err := recoverer.RollbackEnvironment(name); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	respondJSON(w, map[string]string{"status": "rolled back"})
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"imperm-middleware/pkg/models"
+)
+
+// listHandler serves GET ?namespace=X (empty for all namespaces) with a client list method
+func listHandler[T any](list func(namespace string) ([]T, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		items, err := list(r.URL.Query().Get("namespace"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Handle null response
+		if items == nil {
+			items = []T{}
+		}
+
+		respondJSON(w, items)
+	}
+}
+
+// getHandler serves GET ?namespace=X&name=Y with a client get method
+func getHandler[T any](get func(namespace, name string) (*T, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		namespace := r.URL.Query().Get("namespace")
+		name := r.URL.Query().Get("name")
+		if namespace == "" || name == "" {
+			http.Error(w, "namespace and name parameters are required", http.StatusBadRequest)
+			return
+		}
+
+		item, err := get(namespace, name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		respondJSON(w, item)
+	}
+}
+
+// handleGetSecret serves a single secret. Values stay masked unless reveal=true is passed.
+func (h *Handler) handleGetSecret(w http.ResponseWriter, r *http.Request) {
+	reveal := r.URL.Query().Get("reveal") == "true"
+	getHandler(func(namespace, name string) (*models.Secret, error) {
+		return h.client.GetSecret(namespace, name, reveal)
+	})(w, r)
+}
+
+// handleResourceManifest serves GET /api/resources/{kind}/{namespace}/{name}.
+// Secret values are masked unless reveal=true is passed.
+func (h *Handler) handleResourceManifest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/resources/"), "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		http.Error(w, "expected /api/resources/{kind}/{namespace}/{name}", http.StatusBadRequest)
+		return
+	}
+
+	manifest, err := h.client.GetResourceManifest(parts[0], parts[1], parts[2], r.URL.Query().Get("reveal") == "true")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, manifest)
+}
@@ -0,0 +1,218 @@
+// Package capacity works out what an environment will reserve from the
+// cluster before it is created, and checks that against its resource quota
+package capacity
+
+import (
+	"fmt"
+	"imperm-middleware/pkg/models"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// replicaResources is what one replica of a deployable component reserves
+type replicaResources struct {
+	cpuRequest    string
+	memoryRequest string
+	cpuLimit      string
+	memoryLimit   string
+}
+
+// components maps the namespace module's replica count variables to the
+// resources of one replica, as declared in terraform/modules/k8s-namespace/loggers.tf
+var components = map[string]replicaResources{
+	"constant_logger": {cpuRequest: "50m", memoryRequest: "32Mi", cpuLimit: "100m", memoryLimit: "64Mi"},
+	"fast_logger":     {cpuRequest: "50m", memoryRequest: "32Mi", cpuLimit: "100m", memoryLimit: "64Mi"},
+	"error_logger":    {cpuRequest: "50m", memoryRequest: "32Mi", cpuLimit: "100m", memoryLimit: "64Mi"},
+	"json_logger":     {cpuRequest: "50m", memoryRequest: "32Mi", cpuLimit: "100m", memoryLimit: "64Mi"},
+}
+
+// Requested sums what the replicas the options ask for will reserve, keyed
+// by the quota resource names (requests.cpu, limits.memory, pods, ...)
+func Requested(options *models.DeploymentOptions) (corev1.ResourceList, error) {
+	total := corev1.ResourceList{
+		corev1.ResourceRequestsCPU:    resource.Quantity{},
+		corev1.ResourceRequestsMemory: resource.Quantity{},
+		corev1.ResourceLimitsCPU:      resource.Quantity{},
+		corev1.ResourceLimitsMemory:   resource.Quantity{},
+		corev1.ResourcePods:           resource.Quantity{},
+	}
+	if options == nil {
+		return total, nil
+	}
+
+	counts, err := replicaCounts(options)
+	if err != nil {
+		return nil, err
+	}
+	for variable, replicas := range counts {
+		perReplica := components[variable]
+		add := func(name corev1.ResourceName, quantity string) {
+			q := resource.MustParse(quantity)
+			q.Mul(int64(replicas))
+			sum := total[name]
+			sum.Add(q)
+			total[name] = sum
+		}
+		add(corev1.ResourceRequestsCPU, perReplica.cpuRequest)
+		add(corev1.ResourceRequestsMemory, perReplica.memoryRequest)
+		add(corev1.ResourceLimitsCPU, perReplica.cpuLimit)
+		add(corev1.ResourceLimitsMemory, perReplica.memoryLimit)
+		add(corev1.ResourcePods, "1")
+	}
+	return total, nil
+}
+
+// replicaCounts reads how many replicas of each component the options ask for,
+// leaving out components the options don't mention
+func replicaCounts(options *models.DeploymentOptions) (map[string]int, error) {
+	counts := make(map[string]int)
+	if options == nil {
+		return counts, nil
+	}
+	for variable := range components {
+		value := options.Variable(variable)
+		if value == "" {
+			continue
+		}
+		replicas, err := strconv.Atoi(value)
+		if err != nil || replicas < 0 {
+			return nil, fmt.Errorf("%s must be a replica count, got %q", variable, value)
+		}
+		counts[variable] = replicas
+	}
+	return counts, nil
+}
+
+// QuotaHard converts a quota to the hard limits of a Kubernetes ResourceQuota
+func QuotaHard(quota models.ResourceQuota) (corev1.ResourceList, error) {
+	hard := corev1.ResourceList{}
+	for name, value := range map[corev1.ResourceName]string{
+		corev1.ResourceRequestsCPU:    quota.CPURequests,
+		corev1.ResourceRequestsMemory: quota.MemoryRequests,
+		corev1.ResourceLimitsCPU:      quota.CPULimits,
+		corev1.ResourceLimitsMemory:   quota.MemoryLimits,
+		corev1.ResourcePods:           quota.Pods,
+	} {
+		if value == "" {
+			continue
+		}
+		q, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quota for %s: %q", name, value)
+		}
+		hard[name] = q
+	}
+	return hard, nil
+}
+
+// CheckQuota rejects options whose replicas would not fit in their own quota
+func CheckQuota(options *models.DeploymentOptions) error {
+	if options == nil {
+		return nil
+	}
+	hard, err := QuotaHard(options.Quota())
+	if err != nil {
+		return err
+	}
+	if len(hard) == 0 {
+		return nil
+	}
+
+	requested, err := Requested(options)
+	if err != nil {
+		return err
+	}
+
+	var exceeded []string
+	for _, name := range []corev1.ResourceName{
+		corev1.ResourceRequestsCPU,
+		corev1.ResourceRequestsMemory,
+		corev1.ResourceLimitsCPU,
+		corev1.ResourceLimitsMemory,
+		corev1.ResourcePods,
+	} {
+		limit, ok := hard[name]
+		if !ok {
+			continue
+		}
+		want := requested[name]
+		if want.Cmp(limit) > 0 {
+			exceeded = append(exceeded, fmt.Sprintf("%s: requested %s, quota %s", name, want.String(), limit.String()))
+		}
+	}
+	if len(exceeded) > 0 {
+		return &QuotaExceededError{Exceeded: exceeded}
+	}
+	return nil
+}
+
+// QuotaExceededError is returned when an environment's replicas would not fit in its quota
+type QuotaExceededError struct {
+	Exceeded []string // One entry per exceeded resource, e.g. "pods: requested 40, quota 30"
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("requested replicas exceed the environment quota (%s)", strings.Join(e.Exceeded, "; "))
+}
+
+// DefaultQuota is the team default applied to environments that don't size their own quota and limit range
+const DefaultQuota = "quota_cpu_requests=2,quota_memory_requests=4Gi,quota_cpu_limits=4,quota_memory_limits=8Gi,quota_pods=30," +
+	"limit_default_cpu_request=50m,limit_default_memory_request=64Mi,limit_default_cpu=250m,limit_default_memory=256Mi"
+
+// quotaVariables are the variables ParseDefaults accepts
+var quotaVariables = map[string]bool{
+	models.VarQuotaCPURequests:          true,
+	models.VarQuotaMemoryRequests:       true,
+	models.VarQuotaCPULimits:            true,
+	models.VarQuotaMemoryLimits:         true,
+	models.VarQuotaPods:                 true,
+	models.VarLimitDefaultCPURequest:    true,
+	models.VarLimitDefaultMemoryRequest: true,
+	models.VarLimitDefaultCPU:           true,
+	models.VarLimitDefaultMemory:        true,
+}
+
+// ParseDefaults reads comma separated variable=quantity pairs, e.g.
+// "quota_pods=20,limit_default_cpu=200m"; an empty spec means no defaults
+func ParseDefaults(spec string) (map[string]string, error) {
+	defaults := make(map[string]string)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid quota default %q, expected variable=quantity", pair)
+		}
+		defaults[key] = value
+	}
+	if err := CheckDefaults(defaults); err != nil {
+		return nil, err
+	}
+	return defaults, nil
+}
+
+// CheckDefaults makes sure quota defaults only set known variables to valid quantities
+func CheckDefaults(defaults map[string]string) error {
+	keys := make([]string, 0, len(defaults))
+	for key := range defaults {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !quotaVariables[key] {
+			return fmt.Errorf("unknown quota variable %q", key)
+		}
+		if _, err := resource.ParseQuantity(defaults[key]); err != nil {
+			return fmt.Errorf("invalid quantity for %s: %q", key, defaults[key])
+		}
+	}
+	return nil
+}
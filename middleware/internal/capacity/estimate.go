@@ -0,0 +1,140 @@
+package capacity
+
+import (
+	"fmt"
+	"imperm-middleware/pkg/models"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// hoursPerMonth is the average number of hours in a month, used to turn monthly rates into hourly ones
+const hoursPerMonth = 730
+
+// Pricing is what a month of requested CPU and memory costs
+type Pricing struct {
+	Currency    string
+	PerCPUMonth float64
+	PerGiBMonth float64
+}
+
+// DefaultPricing roughly matches on-demand prices of general purpose cloud instances
+var DefaultPricing = Pricing{Currency: "USD", PerCPUMonth: 25, PerGiBMonth: 3.5}
+
+// nodeRoom is what is left on a node while replicas are placed on it
+type nodeRoom struct {
+	cpuMillis   int64
+	memoryBytes int64
+	pods        int64
+}
+
+// Estimate works out whether the replicas the options ask for fit in what
+// the cluster's nodes have left after the requests of the pods already
+// running there, and prices them. Every replica has to fit on a single node,
+// so replicas are placed the way a scheduler spreading by free CPU would.
+func Estimate(options *models.DeploymentOptions, cluster *models.ClusterCapacity, pricing Pricing) (*models.CapacityEstimate, error) {
+	counts, err := replicaCounts(options)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate := &models.CapacityEstimate{Reasons: []string{}}
+
+	rooms := make([]nodeRoom, len(cluster.Nodes))
+	for i, node := range cluster.Nodes {
+		rooms[i] = nodeRoom{
+			cpuMillis:   max(node.AllocatableCPUMillis-node.RequestedCPUMillis, 0),
+			memoryBytes: max(node.AllocatableMemoryBytes-node.RequestedMemoryBytes, 0),
+			pods:        max(node.AllocatablePods-node.RequestedPods, 0),
+		}
+		estimate.AvailableCPUMillis += rooms[i].cpuMillis
+		estimate.AvailableMemoryBytes += rooms[i].memoryBytes
+		estimate.AvailablePods += rooms[i].pods
+	}
+	estimate.Nodes = len(rooms)
+
+	// Place components in a fixed order so the verdict doesn't depend on map iteration
+	variables := make([]string, 0, len(counts))
+	for variable := range counts {
+		variables = append(variables, variable)
+	}
+	sort.Strings(variables)
+
+	var unplaced []string
+	for _, variable := range variables {
+		replicas := counts[variable]
+		perReplica := components[variable]
+		cpu := milliValue(perReplica.cpuRequest)
+		memory := value(perReplica.memoryRequest)
+
+		estimate.RequestedCPUMillis += cpu * int64(replicas)
+		estimate.RequestedMemoryBytes += memory * int64(replicas)
+		estimate.RequestedPods += int64(replicas)
+		estimate.LimitCPUMillis += milliValue(perReplica.cpuLimit) * int64(replicas)
+		estimate.LimitMemoryBytes += value(perReplica.memoryLimit) * int64(replicas)
+
+		placed := 0
+		for ; placed < replicas; placed++ {
+			best := -1
+			for i, room := range rooms {
+				if room.cpuMillis < cpu || room.memoryBytes < memory || room.pods < 1 {
+					continue
+				}
+				if best < 0 || room.cpuMillis > rooms[best].cpuMillis {
+					best = i
+				}
+			}
+			if best < 0 {
+				break
+			}
+			rooms[best].cpuMillis -= cpu
+			rooms[best].memoryBytes -= memory
+			rooms[best].pods--
+		}
+		if placed < replicas {
+			unplaced = append(unplaced, fmt.Sprintf("%d of %d %s replicas fit on no node", replicas-placed, replicas, variable))
+		}
+	}
+
+	switch {
+	case estimate.RequestedPods == 0:
+	case len(rooms) == 0:
+		estimate.Reasons = append(estimate.Reasons, "the cluster has no schedulable nodes")
+	default:
+		if estimate.RequestedCPUMillis > estimate.AvailableCPUMillis {
+			estimate.Reasons = append(estimate.Reasons, fmt.Sprintf("cpu: requested %dm, available %dm",
+				estimate.RequestedCPUMillis, estimate.AvailableCPUMillis))
+		}
+		if estimate.RequestedMemoryBytes > estimate.AvailableMemoryBytes {
+			estimate.Reasons = append(estimate.Reasons, fmt.Sprintf("memory: requested %dMi, available %dMi",
+				estimate.RequestedMemoryBytes/(1024*1024), estimate.AvailableMemoryBytes/(1024*1024)))
+		}
+		if estimate.RequestedPods > estimate.AvailablePods {
+			estimate.Reasons = append(estimate.Reasons, fmt.Sprintf("pods: requested %d, available %d",
+				estimate.RequestedPods, estimate.AvailablePods))
+		}
+		estimate.Reasons = append(estimate.Reasons, unplaced...)
+	}
+	estimate.Fits = len(estimate.Reasons) == 0
+
+	monthly := float64(estimate.RequestedCPUMillis)/1000*pricing.PerCPUMonth +
+		float64(estimate.RequestedMemoryBytes)/(1024*1024*1024)*pricing.PerGiBMonth
+	estimate.Cost = models.CostEstimate{
+		Currency:    pricing.Currency,
+		PerCPUMonth: pricing.PerCPUMonth,
+		PerGiBMonth: pricing.PerGiBMonth,
+		Monthly:     monthly,
+		Hourly:      monthly / hoursPerMonth,
+	}
+	return estimate, nil
+}
+
+func milliValue(quantity string) int64 {
+	q := resource.MustParse(quantity)
+	return q.MilliValue()
+}
+
+func value(quantity string) int64 {
+	q := resource.MustParse(quantity)
+	return q.Value()
+}
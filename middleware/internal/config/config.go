@@ -0,0 +1,478 @@
+// Package config loads the imperm server configuration: defaults, then a
+// YAML file, then IMPERM_* environment variables, then command line flags
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"imperm-middleware/internal/capacity"
+	"imperm-middleware/internal/k8s"
+	"imperm-middleware/internal/metrics"
+	"imperm-middleware/internal/terraform"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Server modes
+const (
+	ModeTerraform = "terraform"
+	ModeK8s       = "k8s"
+	ModeMock      = "mock"
+)
+
+// Config is everything the server can be configured with
+type Config struct {
+	Listen string `json:"listen"` // Address to listen on, e.g. ":8080"
+	TLS    TLS    `json:"tls"`
+	Mode   string `json:"mode"` // terraform, k8s or mock
+
+	// ProjectRoot holds the terraform/ directory; empty means $IMPERM_PROJECT_ROOT
+	// or the nearest parent of the working directory that has one
+	ProjectRoot string    `json:"projectRoot"`
+	Terraform   Terraform `json:"terraform"`
+
+	// Kubeconfig and Context apply to the single cluster managed when Clusters
+	// is empty, and Kubeconfig to clusters that don't name their own
+	Kubeconfig  string    `json:"kubeconfig"`
+	Context     string    `json:"context"`
+	Clusters    []Cluster `json:"clusters"`
+	AllContexts bool      `json:"allContexts"` // Manage one cluster per kubeconfig context instead
+
+	Discovery Discovery `json:"discovery"`
+	Logs      Logs      `json:"logs"`
+	RateLimit RateLimit `json:"rateLimit"`
+	Metrics   Metrics   `json:"metrics"`
+	Drift     Drift     `json:"drift"`
+
+	QuotaDefaults map[string]string `json:"quotaDefaults"` // Quota variables of environments that set none
+	Cost          Cost              `json:"cost"`
+}
+
+// TLS serves HTTPS when both files are set
+type TLS struct {
+	CertFile string `json:"certFile"`
+	KeyFile  string `json:"keyFile"`
+}
+
+// Terraform says where terraform and the namespace module are, and where
+// environments' working directories go
+type Terraform struct {
+	Binary     string  `json:"binary"`
+	ModulePath string  `json:"modulePath"` // Defaults to <projectRoot>/terraform/modules/k8s-namespace
+	WorkingDir string  `json:"workingDir"` // Defaults to <projectRoot>/terraform
+	Backend    Backend `json:"backend"`
+
+	// Provider installation shared by every environment's terraform init
+	PluginCacheDir string `json:"pluginCacheDir"` // Defaults to <workingDir>/plugin-cache
+	ProviderMirror string `json:"providerMirror"` // Offline provider mirror directory, empty to use the registry
+	LockFile       string `json:"lockFile"`       // Lock file template, defaults to <workingDir>/.terraform.lock.hcl
+}
+
+// Backend is where environments keep their Terraform state, each under its
+// own key, see terraform.Backend. Credentials come from terraform's usual
+// environment variables, such as AWS_ACCESS_KEY_ID or TF_HTTP_PASSWORD.
+type Backend struct {
+	Type      string `json:"type"`      // local, s3, kubernetes or http
+	Path      string `json:"path"`      // local: directory of state files, empty for each working directory's own
+	Bucket    string `json:"bucket"`    // s3
+	Region    string `json:"region"`    // s3
+	Endpoint  string `json:"endpoint"`  // s3: S3-compatible endpoint such as MinIO
+	PathStyle bool   `json:"pathStyle"` // s3: needed by MinIO
+	Namespace string `json:"namespace"` // kubernetes: namespace of the state secrets
+	Address   string `json:"address"`   // http: base URL
+	Username  string `json:"username"`  // http
+}
+
+// Cluster is one cluster to manage, see k8s.ClusterConfig
+type Cluster struct {
+	Name       string `json:"name"`
+	Kubeconfig string `json:"kubeconfig"`
+	Context    string `json:"context"`
+}
+
+// Discovery decides which namespaces are environments, see k8s.Discovery
+type Discovery struct {
+	Selector string   `json:"selector"` // Label selector of managed namespaces, empty for every namespace
+	Include  []string `json:"include"`  // Name patterns a namespace must match one of, empty for any name
+	Exclude  []string `json:"exclude"`  // Name patterns never listed, even as unmanaged
+}
+
+// Logs bounds the Terraform operation logs kept in memory
+type Logs struct {
+	Retention Duration `json:"retention"` // How long a finished operation's log is kept
+	MaxLines  int      `json:"maxLines"`  // Lines kept per operation, 0 for all
+}
+
+// RateLimit is the client-side rate limit of the Kubernetes clients
+type RateLimit struct {
+	QPS   float32 `json:"qps"`
+	Burst int     `json:"burst"`
+}
+
+// Metrics configures pod metrics sampling
+type Metrics struct {
+	Interval  Duration `json:"interval"` // 0 disables sampling
+	Retention Duration `json:"retention"`
+}
+
+// Drift configures periodic Terraform drift checks of the environments
+type Drift struct {
+	Interval    Duration `json:"interval"`    // 0 disables periodic checks
+	Concurrency int      `json:"concurrency"` // Plans run at once
+}
+
+// Cost is what capacity estimates are priced at
+type Cost struct {
+	Currency    string  `json:"currency"`
+	PerCPUMonth float64 `json:"perCpuMonth"`
+	PerGiBMonth float64 `json:"perGibMonth"`
+}
+
+// Duration is a time.Duration written as a string such as "15s" or "1h30m"
+type Duration time.Duration
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("durations are strings such as \"15s\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Default returns the configuration used where nothing else is set
+func Default() *Config {
+	quotaDefaults, err := capacity.ParseDefaults(capacity.DefaultQuota)
+	if err != nil {
+		panic(err) // The built-in defaults are always valid
+	}
+
+	return &Config{
+		Listen: ":8080",
+		Mode:   ModeTerraform,
+		Terraform: Terraform{
+			Binary:  terraform.DefaultBinary,
+			Backend: Backend{Type: terraform.BackendLocal},
+		},
+		Discovery: Discovery{
+			Selector: k8s.DefaultSelector,
+			Exclude:  append([]string{}, k8s.DefaultExclude...),
+		},
+		Logs: Logs{
+			Retention: Duration(terraform.DefaultLogRetention),
+			MaxLines:  terraform.DefaultLogMaxLines,
+		},
+		RateLimit: RateLimit{QPS: k8s.DefaultQPS, Burst: k8s.DefaultBurst},
+		Metrics: Metrics{
+			Interval:  Duration(metrics.DefaultInterval),
+			Retention: Duration(metrics.DefaultRetention),
+		},
+		Drift: Drift{
+			Interval:    Duration(terraform.DefaultDriftInterval),
+			Concurrency: terraform.DefaultDriftConcurrency,
+		},
+		QuotaDefaults: quotaDefaults,
+		Cost: Cost{
+			Currency:    capacity.DefaultPricing.Currency,
+			PerCPUMonth: capacity.DefaultPricing.PerCPUMonth,
+			PerGiBMonth: capacity.DefaultPricing.PerGiBMonth,
+		},
+	}
+}
+
+// Load reads the defaults overridden by the file at path, if any, and then
+// by IMPERM_* environment variables. Unknown keys in the file are errors.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+			return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+	}
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Resolve fills in the paths left to be derived from the project root
+func (c *Config) Resolve() {
+	if c.ProjectRoot == "" {
+		c.ProjectRoot = projectRoot()
+	}
+	if c.Terraform.ModulePath == "" {
+		c.Terraform.ModulePath = filepath.Join(c.ProjectRoot, "terraform", "modules", "k8s-namespace")
+	}
+	if c.Terraform.WorkingDir == "" {
+		c.Terraform.WorkingDir = filepath.Join(c.ProjectRoot, "terraform")
+	}
+	if c.Terraform.PluginCacheDir == "" {
+		c.Terraform.PluginCacheDir = filepath.Join(c.Terraform.WorkingDir, "plugin-cache")
+	}
+	if c.Terraform.LockFile == "" {
+		c.Terraform.LockFile = filepath.Join(c.Terraform.WorkingDir, ".terraform.lock.hcl")
+	}
+}
+
+// Validate reports every problem with the configuration at once
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Listen == "" {
+		fail("listen: must not be empty")
+	}
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		fail("tls: certFile and keyFile must be set together")
+	}
+	for _, file := range []string{c.TLS.CertFile, c.TLS.KeyFile} {
+		if file == "" {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			fail("tls: %v", err)
+		}
+	}
+
+	switch c.Mode {
+	case ModeTerraform:
+		if _, err := exec.LookPath(c.Terraform.Binary); err != nil {
+			fail("terraform.binary: %v", err)
+		}
+		if info, err := os.Stat(c.Terraform.ModulePath); err != nil || !info.IsDir() {
+			fail("terraform.modulePath: %s is not a directory", c.Terraform.ModulePath)
+		}
+		if c.Terraform.ProviderMirror != "" {
+			if info, err := os.Stat(c.Terraform.ProviderMirror); err != nil || !info.IsDir() {
+				fail("terraform.providerMirror: %s is not a directory", c.Terraform.ProviderMirror)
+			}
+		}
+	case ModeK8s, ModeMock:
+	default:
+		fail("mode: must be %s, %s or %s, got %q", ModeTerraform, ModeK8s, ModeMock, c.Mode)
+	}
+
+	backend := c.Terraform.Backend
+	switch backend.Type {
+	case terraform.BackendLocal, terraform.BackendKubernetes:
+	case terraform.BackendS3:
+		if backend.Bucket == "" {
+			fail("terraform.backend.bucket: must be set for the s3 backend")
+		}
+	case terraform.BackendHTTP:
+		if backend.Address == "" {
+			fail("terraform.backend.address: must be set for the http backend")
+		}
+	default:
+		fail("terraform.backend.type: must be %s, %s, %s or %s, got %q",
+			terraform.BackendLocal, terraform.BackendS3, terraform.BackendKubernetes, terraform.BackendHTTP, backend.Type)
+	}
+
+	if len(c.Clusters) > 0 && c.AllContexts {
+		fail("clusters: cannot be combined with allContexts")
+	}
+	seen := make(map[string]bool)
+	for i, cluster := range c.Clusters {
+		switch {
+		case cluster.Name == "":
+			fail("clusters[%d].name: must not be empty", i)
+		case seen[cluster.Name]:
+			fail("clusters[%d].name: %q is configured twice", i, cluster.Name)
+		default:
+			if err := k8s.CheckClusterName(cluster.Name); err != nil {
+				fail("clusters[%d].name: %v", i, err)
+			}
+		}
+		seen[cluster.Name] = true
+	}
+
+	if err := c.k8sDiscovery().Check(); err != nil {
+		fail("discovery: %v", err)
+	}
+	if c.Logs.Retention <= 0 {
+		fail("logs.retention: must be positive")
+	}
+	if c.Logs.MaxLines < 0 {
+		fail("logs.maxLines: must not be negative")
+	}
+	if c.RateLimit.QPS <= 0 {
+		fail("rateLimit.qps: must be positive")
+	}
+	if c.RateLimit.Burst < 1 {
+		fail("rateLimit.burst: must be at least 1")
+	}
+	if c.Metrics.Interval < 0 {
+		fail("metrics.interval: must not be negative")
+	}
+	if c.Metrics.Interval > 0 && c.Metrics.Retention < c.Metrics.Interval {
+		fail("metrics.retention: must be at least the interval")
+	}
+	if c.Drift.Interval < 0 {
+		fail("drift.interval: must not be negative")
+	}
+	if c.Drift.Concurrency < 1 {
+		fail("drift.concurrency: must be at least 1")
+	}
+	if err := capacity.CheckDefaults(c.QuotaDefaults); err != nil {
+		fail("quotaDefaults: %v", err)
+	}
+	if c.Cost.PerCPUMonth < 0 || c.Cost.PerGiBMonth < 0 {
+		fail("cost: rates must not be negative")
+	}
+
+	return errors.Join(errs...)
+}
+
+// YAML renders the configuration in the format Load reads
+func (c *Config) YAML() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
+// ClusterConfigs lists the clusters to manage, the default one first
+func (c *Config) ClusterConfigs() ([]k8s.ClusterConfig, error) {
+	var clusters []k8s.ClusterConfig
+	switch {
+	case c.AllContexts:
+		contexts, err := k8s.ContextClusters(c.Kubeconfig)
+		if err != nil {
+			return nil, err
+		}
+		clusters = contexts
+	case len(c.Clusters) > 0:
+		for _, cluster := range c.Clusters {
+			kubeconfig := cluster.Kubeconfig
+			if kubeconfig == "" {
+				kubeconfig = c.Kubeconfig
+			}
+			clusters = append(clusters, k8s.ClusterConfig{Name: cluster.Name, Kubeconfig: kubeconfig, Context: cluster.Context})
+		}
+	default:
+		clusters = []k8s.ClusterConfig{{Name: k8s.DefaultClusterName, Kubeconfig: c.Kubeconfig, Context: c.Context}}
+	}
+
+	for i := range clusters {
+		if err := k8s.CheckClusterName(clusters[i].Name); err != nil {
+			if c.AllContexts {
+				return nil, fmt.Errorf("context %q can't name a cluster, list it under clusters with a name of its own: %w", clusters[i].Context, err)
+			}
+			return nil, err
+		}
+		clusters[i].QPS = c.RateLimit.QPS
+		clusters[i].Burst = c.RateLimit.Burst
+		clusters[i].Discovery = c.k8sDiscovery()
+	}
+	return clusters, nil
+}
+
+func (c *Config) k8sDiscovery() *k8s.Discovery {
+	return &k8s.Discovery{Selector: c.Discovery.Selector, Include: c.Discovery.Include, Exclude: c.Discovery.Exclude}
+}
+
+// TerraformBackend returns where the environments of a cluster keep their
+// state; keys of other clusters than the primary one are prefixed with
+// clusters/<name>/ so environment names can repeat across clusters
+func (c *Config) TerraformBackend(cluster string, primary bool) terraform.Backend {
+	b := c.Terraform.Backend
+	backend := terraform.Backend{
+		Type:      b.Type,
+		Path:      b.Path,
+		Bucket:    b.Bucket,
+		Region:    b.Region,
+		Endpoint:  b.Endpoint,
+		PathStyle: b.PathStyle,
+		Namespace: b.Namespace,
+		Address:   b.Address,
+		Username:  b.Username,
+	}
+	if !primary {
+		backend.Prefix = "clusters/" + cluster + "/"
+	}
+	return backend
+}
+
+// TerraformProviders returns how environments' providers are installed
+func (c *Config) TerraformProviders() terraform.Providers {
+	return terraform.Providers{
+		PluginCacheDir: c.Terraform.PluginCacheDir,
+		Mirror:         c.Terraform.ProviderMirror,
+		LockFile:       c.Terraform.LockFile,
+	}
+}
+
+// Pricing returns the rates capacity estimates are priced at
+func (c *Config) Pricing() capacity.Pricing {
+	return capacity.Pricing{Currency: c.Cost.Currency, PerCPUMonth: c.Cost.PerCPUMonth, PerGiBMonth: c.Cost.PerGiBMonth}
+}
+
+// projectRoot returns the project root directory
+func projectRoot() string {
+	// Try to get from environment variable first
+	if root := os.Getenv("IMPERM_PROJECT_ROOT"); root != "" {
+		return root
+	}
+
+	// Otherwise use current working directory and go up until we find terraform/
+	cwd, err := os.Getwd()
+	if err != nil {
+		log.Printf("Warning: failed to get working directory: %v", err)
+		return "."
+	}
+
+	// Walk up the directory tree looking for the terraform directory
+	dir := cwd
+	for {
+		tfDir := filepath.Join(dir, "terraform")
+		if _, err := os.Stat(tfDir); err == nil {
+			return dir
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			// Reached root, use cwd
+			return cwd
+		}
+		dir = parent
+	}
+}
+
+// DefaultKubeconfig returns the path to the kubeconfig file used when none is configured
+func DefaultKubeconfig() string {
+	// Try KUBECONFIG environment variable first
+	if kubeconfig := os.Getenv("KUBECONFIG"); kubeconfig != "" {
+		return kubeconfig
+	}
+
+	// Default to ~/.kube/config
+	home, err := os.UserHomeDir()
+	if err != nil {
+		log.Printf("Warning: failed to get home directory: %v", err)
+		return ""
+	}
+
+	return filepath.Join(home, ".kube", "config")
+}
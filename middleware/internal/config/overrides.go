@@ -0,0 +1,236 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"imperm-middleware/internal/capacity"
+	"imperm-middleware/internal/k8s"
+)
+
+// setter applies one override given as text, from the environment or a flag
+type setter func(c *Config, value string) error
+
+func setString(field func(c *Config) *string) setter {
+	return func(c *Config, value string) error {
+		*field(c) = value
+		return nil
+	}
+}
+
+func setDuration(field func(c *Config) *Duration) setter {
+	return func(c *Config, value string) error {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = Duration(d)
+		return nil
+	}
+}
+
+func setInt(field func(c *Config) *int) setter {
+	return func(c *Config, value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = n
+		return nil
+	}
+}
+
+func setFloat(field func(c *Config) *float64) setter {
+	return func(c *Config, value string) error {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		*field(c) = f
+		return nil
+	}
+}
+
+// setList reads a comma separated list, ignoring blanks
+func setList(field func(c *Config) *[]string) setter {
+	return func(c *Config, value string) error {
+		*field(c) = splitList(value)
+		return nil
+	}
+}
+
+// setClusters reads clusters in the name=kubeconfig@context syntax of k8s.ParseClusters
+func setClusters(c *Config, value string) error {
+	configs, err := k8s.ParseClusters(value)
+	if err != nil {
+		return err
+	}
+	c.Clusters = nil
+	if value == "" {
+		return nil
+	}
+	for _, config := range configs {
+		c.Clusters = append(c.Clusters, Cluster{Name: config.Name, Kubeconfig: config.Kubeconfig, Context: config.Context})
+	}
+	return nil
+}
+
+// setQuotaDefaults reads variable=quantity pairs, see capacity.ParseDefaults
+func setQuotaDefaults(c *Config, value string) error {
+	defaults, err := capacity.ParseDefaults(value)
+	if err != nil {
+		return err
+	}
+	c.QuotaDefaults = defaults
+	return nil
+}
+
+// envOverrides maps each IMPERM_* environment variable to the setting it overrides
+var envOverrides = []struct {
+	name string
+	set  setter
+}{
+	{"IMPERM_LISTEN", setString(func(c *Config) *string { return &c.Listen })},
+	{"IMPERM_TLS_CERT_FILE", setString(func(c *Config) *string { return &c.TLS.CertFile })},
+	{"IMPERM_TLS_KEY_FILE", setString(func(c *Config) *string { return &c.TLS.KeyFile })},
+	{"IMPERM_MODE", setString(func(c *Config) *string { return &c.Mode })},
+	{"IMPERM_PROJECT_ROOT", setString(func(c *Config) *string { return &c.ProjectRoot })},
+	{"IMPERM_TERRAFORM_BINARY", setString(func(c *Config) *string { return &c.Terraform.Binary })},
+	{"IMPERM_MODULE_PATH", setString(func(c *Config) *string { return &c.Terraform.ModulePath })},
+	{"IMPERM_WORKING_DIR", setString(func(c *Config) *string { return &c.Terraform.WorkingDir })},
+	{"IMPERM_PLUGIN_CACHE_DIR", setString(func(c *Config) *string { return &c.Terraform.PluginCacheDir })},
+	{"IMPERM_PROVIDER_MIRROR", setString(func(c *Config) *string { return &c.Terraform.ProviderMirror })},
+	{"IMPERM_LOCK_FILE", setString(func(c *Config) *string { return &c.Terraform.LockFile })},
+	{"IMPERM_BACKEND_TYPE", setString(func(c *Config) *string { return &c.Terraform.Backend.Type })},
+	{"IMPERM_BACKEND_PATH", setString(func(c *Config) *string { return &c.Terraform.Backend.Path })},
+	{"IMPERM_BACKEND_BUCKET", setString(func(c *Config) *string { return &c.Terraform.Backend.Bucket })},
+	{"IMPERM_BACKEND_REGION", setString(func(c *Config) *string { return &c.Terraform.Backend.Region })},
+	{"IMPERM_BACKEND_ENDPOINT", setString(func(c *Config) *string { return &c.Terraform.Backend.Endpoint })},
+	{"IMPERM_BACKEND_PATH_STYLE", func(c *Config, value string) (err error) {
+		c.Terraform.Backend.PathStyle, err = strconv.ParseBool(value)
+		return err
+	}},
+	{"IMPERM_BACKEND_NAMESPACE", setString(func(c *Config) *string { return &c.Terraform.Backend.Namespace })},
+	{"IMPERM_BACKEND_ADDRESS", setString(func(c *Config) *string { return &c.Terraform.Backend.Address })},
+	{"IMPERM_BACKEND_USERNAME", setString(func(c *Config) *string { return &c.Terraform.Backend.Username })},
+	{"IMPERM_KUBECONFIG", setString(func(c *Config) *string { return &c.Kubeconfig })},
+	{"IMPERM_CONTEXT", setString(func(c *Config) *string { return &c.Context })},
+	{"IMPERM_CLUSTERS", setClusters},
+	{"IMPERM_ALL_CONTEXTS", func(c *Config, value string) (err error) {
+		c.AllContexts, err = strconv.ParseBool(value)
+		return err
+	}},
+	{"IMPERM_DISCOVERY_SELECTOR", setString(func(c *Config) *string { return &c.Discovery.Selector })},
+	{"IMPERM_DISCOVERY_INCLUDE", setList(func(c *Config) *[]string { return &c.Discovery.Include })},
+	{"IMPERM_DISCOVERY_EXCLUDE", setList(func(c *Config) *[]string { return &c.Discovery.Exclude })},
+	{"IMPERM_LOG_RETENTION", setDuration(func(c *Config) *Duration { return &c.Logs.Retention })},
+	{"IMPERM_LOG_MAX_LINES", setInt(func(c *Config) *int { return &c.Logs.MaxLines })},
+	{"IMPERM_RATE_LIMIT_QPS", func(c *Config, value string) error {
+		qps, err := strconv.ParseFloat(value, 32)
+		c.RateLimit.QPS = float32(qps)
+		return err
+	}},
+	{"IMPERM_RATE_LIMIT_BURST", setInt(func(c *Config) *int { return &c.RateLimit.Burst })},
+	{"IMPERM_METRICS_INTERVAL", setDuration(func(c *Config) *Duration { return &c.Metrics.Interval })},
+	{"IMPERM_METRICS_RETENTION", setDuration(func(c *Config) *Duration { return &c.Metrics.Retention })},
+	{"IMPERM_DRIFT_INTERVAL", setDuration(func(c *Config) *Duration { return &c.Drift.Interval })},
+	{"IMPERM_DRIFT_CONCURRENCY", setInt(func(c *Config) *int { return &c.Drift.Concurrency })},
+	{"IMPERM_QUOTA_DEFAULTS", setQuotaDefaults},
+	{"IMPERM_COST_CURRENCY", setString(func(c *Config) *string { return &c.Cost.Currency })},
+	{"IMPERM_COST_PER_CPU", setFloat(func(c *Config) *float64 { return &c.Cost.PerCPUMonth })},
+	{"IMPERM_COST_PER_GIB", setFloat(func(c *Config) *float64 { return &c.Cost.PerGiBMonth })},
+}
+
+func (c *Config) applyEnv() error {
+	for _, override := range envOverrides {
+		value, ok := os.LookupEnv(override.name)
+		if !ok {
+			continue
+		}
+		if err := override.set(c, value); err != nil {
+			return fmt.Errorf("invalid %s: %w", override.name, err)
+		}
+	}
+	return nil
+}
+
+// splitList reads a comma separated list, ignoring blanks
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Flags are the command line flags that override the configuration; only
+// flags given explicitly override anything
+type Flags struct {
+	fs      *flag.FlagSet
+	setters map[string]setter
+}
+
+// RegisterFlags defines the overriding flags on fs
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	defaults := Default()
+	f := &Flags{fs: fs, setters: make(map[string]setter)}
+
+	f.string("port", "8080", "Port to run the server on (overrides listen)", func(c *Config, value string) error {
+		c.Listen = ":" + value
+		return nil
+	})
+	f.bool("mock", "Run in mock mode (simulated K8s data)", func(c *Config) { c.Mode = ModeMock })
+	f.bool("k8s", "Use direct Kubernetes API (instead of Terraform)", func(c *Config) { c.Mode = ModeK8s })
+	f.string("metrics-interval", defaults.Metrics.Interval.String(), "How often to sample pod metrics (0 disables sampling)",
+		setDuration(func(c *Config) *Duration { return &c.Metrics.Interval }))
+	f.string("metrics-retention", defaults.Metrics.Retention.String(), "How much pod metrics history to keep",
+		setDuration(func(c *Config) *Duration { return &c.Metrics.Retention }))
+	f.string("quota-defaults", capacity.DefaultQuota, "Quota and limit range variables for environments that set none, as variable=quantity pairs (empty for none)",
+		setQuotaDefaults)
+	f.string("cost-per-cpu", fmt.Sprint(defaults.Cost.PerCPUMonth), "Monthly cost of one requested CPU, for capacity estimates",
+		setFloat(func(c *Config) *float64 { return &c.Cost.PerCPUMonth }))
+	f.string("cost-per-gib", fmt.Sprint(defaults.Cost.PerGiBMonth), "Monthly cost of one requested GiB of memory, for capacity estimates",
+		setFloat(func(c *Config) *float64 { return &c.Cost.PerGiBMonth }))
+	f.string("cost-currency", defaults.Cost.Currency, "Currency capacity estimates are priced in",
+		setString(func(c *Config) *string { return &c.Cost.Currency }))
+	f.string("clusters", "", "Clusters to manage as name=kubeconfig@context pairs, either side optional; the first is the default (empty for the current context)",
+		setClusters)
+	f.bool("all-contexts", "Manage one cluster per context of the kubeconfig instead of --clusters", func(c *Config) { c.AllContexts = true })
+	return f
+}
+
+func (f *Flags) string(name, value, usage string, set setter) {
+	f.fs.String(name, value, usage)
+	f.setters[name] = set
+}
+
+func (f *Flags) bool(name, usage string, set func(c *Config)) {
+	f.fs.Bool(name, false, usage)
+	f.setters[name] = func(c *Config, value string) error {
+		if enabled, _ := strconv.ParseBool(value); enabled {
+			set(c)
+		}
+		return nil
+	}
+}
+
+// Apply overrides the configuration with the flags given on the command line
+func (f *Flags) Apply(c *Config) error {
+	var err error
+	f.fs.Visit(func(fl *flag.Flag) {
+		set, ok := f.setters[fl.Name]
+		if !ok || err != nil {
+			return
+		}
+		if setErr := set(c, fl.Value.String()); setErr != nil {
+			err = fmt.Errorf("invalid --%s: %w", fl.Name, setErr)
+		}
+	})
+	return err
+}
@@ -0,0 +1,182 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"imperm-middleware/pkg/models"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// LoggerVariables are the module variables whose deployments adoption detects
+var LoggerVariables = []string{"constant_logger", "fast_logger", "error_logger", "json_logger"}
+
+// LoggerDeployment names the deployment a logger variable creates, e.g. constant-logger
+func LoggerDeployment(variable string) string {
+	return strings.ReplaceAll(variable, "_", "-")
+}
+
+// AdoptEnvironment brings an existing namespace under imperm management by
+// labelling it like the namespaces imperm creates
+func (c *K8sClient) AdoptEnvironment(name string) (*models.DeploymentOptions, error) {
+	if err := c.CheckAdoptable(name); err != nil {
+		return nil, err
+	}
+
+	options, err := c.DetectOptions(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.LabelManaged(name, options); err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+// CheckAdoptable rejects namespaces that don't exist or are already managed by
+// imperm, and any adoption when the discovery selector can't be applied as labels
+func (c *K8sClient) CheckAdoptable(name string) error {
+	ns, err := c.clientset.CoreV1().Namespaces().Get(c.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to find namespace %s: %w", name, err)
+	}
+	if c.namespaces.managed(ns.Labels) {
+		return fmt.Errorf("namespace %s is already managed by imperm", name)
+	}
+	if _, err := c.namespaces.managedLabels(); err != nil {
+		return fmt.Errorf("namespace %s can't be adopted: %w", name, err)
+	}
+	return nil
+}
+
+// DetectOptions works out the options an existing namespace corresponds to
+// from the logger deployments found in it
+func (c *K8sClient) DetectOptions(name string) (*models.DeploymentOptions, error) {
+	if !c.namespaces.listed(name) {
+		return nil, fmt.Errorf("namespace %s is excluded from environment discovery", name)
+	}
+
+	deployments, err := c.clientset.AppsV1().Deployments(name).List(c.ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	replicas := make(map[string]int32, len(deployments.Items))
+	for _, deployment := range deployments.Items {
+		replicas[deployment.Name] = 1
+		if deployment.Spec.Replicas != nil {
+			replicas[deployment.Name] = *deployment.Spec.Replicas
+		}
+	}
+
+	options := &models.DeploymentOptions{Name: name, Variables: map[string]string{}}
+	for _, variable := range LoggerVariables {
+		if count, ok := replicas[LoggerDeployment(variable)]; ok {
+			options.Variables[variable] = strconv.Itoa(int(count))
+		}
+	}
+	return options, nil
+}
+
+// previousLabelsAnnotation keeps the values an adoption overwrote, so
+// UnlabelManaged can put them back
+const previousLabelsAnnotation = "previous-labels"
+
+// LabelManaged labels a namespace so the discovery selector finds it managed
+// and stores the options it is managed with, so it can be cloned. The labels
+// it replaces are recorded for UnlabelManaged.
+func (c *K8sClient) LabelManaged(name string, options *models.DeploymentOptions) error {
+	managedLabels, err := c.namespaces.managedLabels()
+	if err != nil {
+		return err
+	}
+	if _, ok := managedLabels["environment"]; !ok {
+		managedLabels["environment"] = name
+	}
+
+	ns, err := c.clientset.CoreV1().Namespaces().Get(c.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to find namespace %s: %w", name, err)
+	}
+	// A nil value records a label the namespace didn't have
+	previous := make(map[string]*string, len(managedLabels))
+	for key := range managedLabels {
+		previous[key] = nil
+		if value, ok := ns.Labels[key]; ok {
+			previous[key] = &value
+		}
+	}
+
+	data, err := json.Marshal(options)
+	if err != nil {
+		return fmt.Errorf("failed to encode deployment options: %w", err)
+	}
+	previousData, err := json.Marshal(previous)
+	if err != nil {
+		return fmt.Errorf("failed to encode previous labels: %w", err)
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": managedLabels,
+			"annotations": map[string]string{
+				"adopted-at":             time.Now().Format(time.RFC3339),
+				optionsAnnotation:        string(data),
+				previousLabelsAnnotation: string(previousData),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode namespace patch: %w", err)
+	}
+	if _, err := c.clientset.CoreV1().Namespaces().Patch(c.ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to label namespace %s: %w", name, err)
+	}
+	return nil
+}
+
+// UnlabelManaged removes what LabelManaged added and restores the labels it
+// replaced, handing a namespace whose adoption didn't go through back as it was
+func (c *K8sClient) UnlabelManaged(name string) error {
+	ns, err := c.clientset.CoreV1().Namespaces().Get(c.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to find namespace %s: %w", name, err)
+	}
+
+	var previous map[string]*string
+	if data, ok := ns.Annotations[previousLabelsAnnotation]; ok {
+		if err := json.Unmarshal([]byte(data), &previous); err != nil {
+			return fmt.Errorf("failed to decode previous labels of namespace %s: %w", name, err)
+		}
+	} else {
+		// Labelled without a record of what was there, so the labels are dropped
+		previous = map[string]*string{"environment": nil}
+		if managedLabels, err := c.namespaces.managedLabels(); err == nil {
+			for key := range managedLabels {
+				previous[key] = nil
+			}
+		}
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": previous,
+			"annotations": map[string]interface{}{
+				"adopted-at":             nil,
+				optionsAnnotation:        nil,
+				previousLabelsAnnotation: nil,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode namespace patch: %w", err)
+	}
+	if _, err := c.clientset.CoreV1().Namespaces().Patch(c.ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to unlabel namespace %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"imperm-middleware/pkg/models"
+	"io"
+	"reflect"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// FieldManager owns the fields imperm sets with server-side apply
+const FieldManager = "imperm"
+
+var (
+	// ErrUnknownEnvironment is returned for namespaces that don't exist or discovery doesn't list
+	ErrUnknownEnvironment = errors.New("unknown environment")
+	// ErrUnmanagedEnvironment is returned for namespaces imperm doesn't manage
+	ErrUnmanagedEnvironment = errors.New("environment is not managed by imperm")
+)
+
+// ApplyManifest applies multi-document YAML into an environment's namespace
+// with server-side apply. Each document is applied on its own, so one bad
+// object doesn't stop the rest. With DryRun nothing is persisted and the
+// result only carries the diffs.
+func (c *K8sClient) ApplyManifest(envName string, req models.ApplyManifestRequest) (*models.ApplyResult, error) {
+	if c.dynamicClient == nil {
+		return nil, fmt.Errorf("dynamic client not available")
+	}
+
+	// Environments are namespaces of the same name. Only managed ones take
+	// manifests, so imperm's credentials never write into system namespaces.
+	namespace := envName
+	if !c.namespaces.listed(namespace) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEnvironment, envName)
+	}
+	ns, err := c.clientset.CoreV1().Namespaces().Get(c.ctx, namespace, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEnvironment, envName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find environment %s: %w", envName, err)
+	}
+	if !c.namespaces.managed(ns.Labels) {
+		return nil, fmt.Errorf("%w: %s", ErrUnmanagedEnvironment, envName)
+	}
+
+	objects, err := decodeManifest(req.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ApplyResult{
+		Environment: envName,
+		Namespace:   namespace,
+		DryRun:      req.DryRun,
+	}
+	for _, obj := range objects {
+		result.Objects = append(result.Objects, c.applyObject(namespace, obj, req.DryRun))
+	}
+	return result, nil
+}
+
+// applyObject applies a single object and diffs it against the live version
+func (c *K8sClient) applyObject(namespace string, obj *unstructured.Unstructured, dryRun bool) models.AppliedObject {
+	applied := models.AppliedObject{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Name:       obj.GetName(),
+	}
+	fail := func(err error) models.AppliedObject {
+		applied.Action = models.ApplyFailed
+		applied.Error = err.Error()
+		return applied
+	}
+
+	if obj.GetName() == "" {
+		return fail(errors.New("metadata.name is required for server-side apply"))
+	}
+
+	mapping, err := c.restMapping(obj.GroupVersionKind())
+	if err != nil {
+		return fail(err)
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return fail(fmt.Errorf("%s is cluster-scoped and can't be applied into an environment", obj.GetKind()))
+	}
+	if ns := obj.GetNamespace(); ns != "" && ns != namespace {
+		return fail(fmt.Errorf("object targets namespace %q, not the environment namespace %q", ns, namespace))
+	}
+	obj.SetNamespace(namespace)
+
+	resource := c.dynamicClient.Resource(mapping.Resource).Namespace(namespace)
+
+	live, err := resource.Get(c.ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fail(err)
+		}
+		live = nil
+	}
+
+	// Fields owned by another manager are a conflict the user resolves,
+	// rather than something imperm silently takes over
+	opts := metav1.ApplyOptions{FieldManager: FieldManager}
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+
+	result, err := resource.Apply(c.ctx, obj.GetName(), obj, opts)
+	if err != nil {
+		if apierrors.IsConflict(err) {
+			return fail(fmt.Errorf("fields are managed by someone else, change or remove them from the manifest: %w", err))
+		}
+		return fail(err)
+	}
+
+	var before map[string]interface{}
+	if live != nil {
+		before = diffableContent(live)
+	}
+	after := diffableContent(result)
+	if result.GroupVersionKind() == corev1.SchemeGroupVersion.WithKind("Secret") {
+		maskSecretDiff(before, after)
+	}
+	applied.Diff = unifiedDiff("live/"+obj.GetName(), "applied/"+obj.GetName(), toYAML(before), toYAML(after))
+
+	switch {
+	case live == nil:
+		applied.Action = models.ApplyCreated
+	case applied.Diff == "":
+		applied.Action = models.ApplyUnchanged
+	default:
+		applied.Action = models.ApplyConfigured
+	}
+	return applied
+}
+
+// restMapping resolves a kind to its API resource, refreshing discovery once
+// in case the kind was installed after the cache was filled (e.g. a new CRD)
+func (c *K8sClient) restMapping(gvk schema.GroupVersionKind) (*meta.RESTMapping, error) {
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		c.mapper.Reset()
+		mapping, err = c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unknown kind %s: %w", gvk.String(), err)
+	}
+	return mapping, nil
+}
+
+// decodeManifest splits multi-document YAML (or JSON) into objects. Empty
+// documents are skipped and List kinds are expanded into their items.
+func decodeManifest(manifest string) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(manifest), 4096)
+
+	var objects []*unstructured.Unstructured
+	for doc := 1; ; doc++ {
+		var content map[string]interface{}
+		if err := decoder.Decode(&content); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("failed to parse document %d: %w", doc, err)
+		}
+		if len(content) == 0 {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{Object: content}
+		if obj.GetAPIVersion() == "" || obj.GetKind() == "" {
+			return nil, fmt.Errorf("document %d: apiVersion and kind are required", doc)
+		}
+
+		if obj.IsList() {
+			list, err := obj.ToList()
+			if err != nil {
+				return nil, fmt.Errorf("document %d: %w", doc, err)
+			}
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			continue
+		}
+		objects = append(objects, obj)
+	}
+
+	if len(objects) == 0 {
+		return nil, errors.New("manifest contains no objects")
+	}
+	return objects, nil
+}
+
+// diffableContent copies an object without the fields the server maintains,
+// so diffs only show changes to what users write
+func diffableContent(obj *unstructured.Unstructured) map[string]interface{} {
+	content := obj.DeepCopy().Object
+	unstructured.RemoveNestedField(content, "status")
+	for _, field := range []string{"managedFields", "resourceVersion", "uid", "generation", "creationTimestamp"} {
+		unstructured.RemoveNestedField(content, "metadata", field)
+	}
+	return content
+}
+
+// maskSecretDiff masks the values of a Secret on both sides of a diff. Values
+// that change are masked differently on the applied side, so the diff still
+// shows which keys change without showing what they hold.
+func maskSecretDiff(before, after map[string]interface{}) {
+	changed := make(map[string][]string)
+	for _, field := range []string{"data", "stringData"} {
+		old, _ := before[field].(map[string]interface{})
+		values, _ := after[field].(map[string]interface{})
+		for key, value := range values {
+			if existing, ok := old[key]; ok && !reflect.DeepEqual(existing, value) {
+				changed[field] = append(changed[field], key)
+			}
+		}
+	}
+
+	maskSecret(before)
+	maskSecret(after)
+	for field, keys := range changed {
+		values := after[field].(map[string]interface{})
+		for _, key := range keys {
+			values[key] = models.SecretMask + " (changed)"
+		}
+	}
+}
+
+// toYAML renders content for diffing, with nil as the empty text
+func toYAML(content map[string]interface{}) string {
+	if content == nil {
+		return ""
+	}
+	out, err := yaml.Marshal(content)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
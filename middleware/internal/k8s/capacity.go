@@ -0,0 +1,69 @@
+package k8s
+
+import (
+	"fmt"
+	"imperm-middleware/pkg/models"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetClusterCapacity reports the allocatable capacity of every schedulable,
+// ready node and the requests of the pods currently assigned to it
+func (c *K8sClient) GetClusterCapacity() (*models.ClusterCapacity, error) {
+	nodes, err := c.clientset.CoreV1().Nodes().List(c.ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	// Finished pods no longer hold on to their requests
+	pods, err := c.clientset.CoreV1().Pods("").List(c.ctx, metav1.ListOptions{
+		FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	byNode := make(map[string]*models.NodeCapacity)
+	for _, node := range nodes.Items {
+		if node.Spec.Unschedulable || !nodeReady(node) {
+			continue
+		}
+		allocatable := node.Status.Allocatable
+		byNode[node.Name] = &models.NodeCapacity{
+			Name:                   node.Name,
+			AllocatableCPUMillis:   allocatable.Cpu().MilliValue(),
+			AllocatableMemoryBytes: allocatable.Memory().Value(),
+			AllocatablePods:        allocatable.Pods().Value(),
+		}
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		node, ok := byNode[pod.Spec.NodeName]
+		if !ok {
+			continue
+		}
+		requests := podRequests(pod)
+		node.RequestedCPUMillis += requests.Cpu().MilliValue()
+		node.RequestedMemoryBytes += requests.Memory().Value()
+		node.RequestedPods++
+	}
+
+	capacity := &models.ClusterCapacity{Nodes: make([]models.NodeCapacity, 0, len(byNode))}
+	for _, node := range byNode {
+		capacity.Nodes = append(capacity.Nodes, *node)
+	}
+	sort.Slice(capacity.Nodes, func(i, j int) bool { return capacity.Nodes[i].Name < capacity.Nodes[j].Name })
+	return capacity, nil
+}
+
+func nodeReady(node corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
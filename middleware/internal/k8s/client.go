@@ -3,26 +3,37 @@ package k8s
 import (
 	"context"
 	"fmt"
-	"os"
-	"path/filepath"
 
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
 	metricsv "k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
 // K8sClient implements the client.Client interface for real Kubernetes
 type K8sClient struct {
-	clientset       *kubernetes.Clientset
-	metricsClient   *metricsv.Clientset
+	clientset       kubernetes.Interface
+	metricsClient   metricsv.Interface // nil when metrics-server is not reachable
+	dynamicClient   dynamic.Interface
+	mapper          *restmapper.DeferredDiscoveryRESTMapper
+	config          *rest.Config
+	forwards        *portForwards
+	namespaces      *namespaceFilter // Which namespaces are environments
 	ctx             context.Context
 }
 
 // NewClient creates a new Kubernetes client
 // It tries in-cluster config first, then falls back to kubeconfig
 func NewClient() (*K8sClient, error) {
-	config, err := getKubeConfig()
+	return NewClientForCluster(ClusterConfig{Name: DefaultClusterName})
+}
+
+// NewClientForCluster creates a Kubernetes client for one configured cluster
+func NewClientForCluster(cluster ClusterConfig) (*K8sClient, error) {
+	config, err := getKubeConfig(cluster)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get kubernetes config: %w", err)
 	}
@@ -33,49 +44,77 @@ func NewClient() (*K8sClient, error) {
 	}
 
 	// Create metrics client (optional - won't fail if metrics-server isn't available)
-	metricsClient, err := metricsv.NewForConfig(config)
+	var metricsClient metricsv.Interface
+	if mc, err := metricsv.NewForConfig(config); err == nil {
+		metricsClient = mc
+	}
+
+	// Dynamic client and REST mapper for applying arbitrary manifests
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(clientset.Discovery()))
+
+	discovery := DefaultDiscovery()
+	if cluster.Discovery != nil {
+		discovery = *cluster.Discovery
+	}
+	namespaces, err := newNamespaceFilter(discovery)
 	if err != nil {
-		// Metrics client creation failed, but we can continue without it
-		metricsClient = nil
+		return nil, fmt.Errorf("invalid environment discovery: %w", err)
 	}
 
 	return &K8sClient{
 		clientset:     clientset,
 		metricsClient: metricsClient,
+		dynamicClient: dynamicClient,
+		mapper:        mapper,
+		config:        config,
+		forwards:      newPortForwards(),
+		namespaces:    namespaces,
 		ctx:           context.Background(),
 	}, nil
 }
 
 // getKubeConfig attempts to get Kubernetes config from various sources
-func getKubeConfig() (*rest.Config, error) {
-	// Try in-cluster config first (for when running inside K8s)
-	config, err := rest.InClusterConfig()
-	if err == nil {
-		// Increase rate limits to avoid throttling
-		config.QPS = 100    // Default is 5
-		config.Burst = 200  // Default is 10
-		return config, nil
-	}
-
-	// Fall back to kubeconfig file
-	kubeconfig := os.Getenv("KUBECONFIG")
-	if kubeconfig == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get home directory: %w", err)
+func getKubeConfig(cluster ClusterConfig) (*rest.Config, error) {
+	// Try in-cluster config first (for when running inside K8s), unless a
+	// kubeconfig or context was asked for explicitly
+	if cluster.Kubeconfig == "" && cluster.Context == "" {
+		config, err := rest.InClusterConfig()
+		if err == nil {
+			// Increase rate limits to avoid throttling
+			setRateLimits(config, cluster)
+			return config, nil
 		}
-		kubeconfig = filepath.Join(home, ".kube", "config")
 	}
 
-	config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	// Fall back to kubeconfig file, $KUBECONFIG or ~/.kube/config unless given
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = cluster.Kubeconfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: cluster.Context}
+
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).ClientConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build config from kubeconfig: %w", err)
 	}
 
 	// Increase rate limits to avoid client-side throttling
 	// Default QPS is 5 and Burst is 10, which is way too low for our use case
-	config.QPS = 100    // Allow up to 100 queries per second
-	config.Burst = 200  // Allow bursts of up to 200 requests
+	setRateLimits(config, cluster)
 
 	return config, nil
 }
+
+// setRateLimits applies the cluster's client-side rate limits, or the defaults
+func setRateLimits(config *rest.Config, cluster ClusterConfig) {
+	config.QPS = DefaultQPS
+	if cluster.QPS > 0 {
+		config.QPS = cluster.QPS
+	}
+	config.Burst = DefaultBurst
+	if cluster.Burst > 0 {
+		config.Burst = cluster.Burst
+	}
+}
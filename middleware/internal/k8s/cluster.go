@@ -0,0 +1,110 @@
+package k8s
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// DefaultClusterName names the cluster when none are configured
+const DefaultClusterName = "default"
+
+// Client-side rate limits used unless configured otherwise. client-go's own
+// defaults of 5 QPS with bursts of 10 are far too low for listing environments.
+const (
+	DefaultQPS   = 100
+	DefaultBurst = 200
+)
+
+// clusterName matches cluster names that are safe as a single path segment,
+// as they name working directories, state keys and API paths
+var clusterName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// CheckClusterName rejects names that can't name a cluster, e.g. EKS context
+// names like "arn:aws:eks:eu-west-1:123456789012:cluster/prod"
+func CheckClusterName(name string) error {
+	if !clusterName.MatchString(name) {
+		return fmt.Errorf("invalid cluster name %q: use letters, digits, '.', '_' and '-', starting with a letter or digit", name)
+	}
+	return nil
+}
+
+// ClusterConfig says how to reach one cluster. An empty Kubeconfig means
+// $KUBECONFIG or ~/.kube/config, and an empty Context the current context;
+// with both empty the in-cluster config is tried first.
+type ClusterConfig struct {
+	Name       string
+	Kubeconfig string
+	Context    string
+
+	QPS       float32    // Client-side rate limit, DefaultQPS when zero
+	Burst     int        // DefaultBurst when zero
+	Discovery *Discovery // Which namespaces are environments, DefaultDiscovery when nil
+}
+
+// ParseClusters reads comma separated name=kubeconfig@context entries, where
+// either side of the @ may be left out, e.g.
+// "dev=@kind-dev,staging=/home/me/.kube/staging". An empty spec yields a
+// single default cluster.
+func ParseClusters(spec string) ([]ClusterConfig, error) {
+	var clusters []ClusterConfig
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, target, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid cluster %q, expected name=kubeconfig@context", entry)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("cluster %q is configured twice", name)
+		}
+		seen[name] = true
+
+		kubeconfig, context, _ := strings.Cut(strings.TrimSpace(target), "@")
+		clusters = append(clusters, ClusterConfig{Name: name, Kubeconfig: kubeconfig, Context: context})
+	}
+
+	if len(clusters) == 0 {
+		clusters = append(clusters, ClusterConfig{Name: DefaultClusterName})
+	}
+	return clusters, nil
+}
+
+// ContextClusters returns one cluster per context of a kubeconfig, named
+// after the context, with the current context first
+func ContextClusters(kubeconfig string) ([]ClusterConfig, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfig
+
+	config, err := rules.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	if len(config.Contexts) == 0 {
+		return nil, fmt.Errorf("kubeconfig has no contexts")
+	}
+
+	names := make([]string, 0, len(config.Contexts))
+	for name := range config.Contexts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == config.CurrentContext) != (names[j] == config.CurrentContext) {
+			return names[i] == config.CurrentContext
+		}
+		return names[i] < names[j]
+	})
+
+	clusters := make([]ClusterConfig, 0, len(names))
+	for _, name := range names {
+		clusters = append(clusters, ClusterConfig{Name: name, Kubeconfig: kubeconfig, Context: name})
+	}
+	return clusters, nil
+}
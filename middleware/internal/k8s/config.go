@@ -0,0 +1,93 @@
+package k8s
+
+import (
+	"fmt"
+	"imperm-middleware/pkg/models"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListConfigMaps lists all config maps in a namespace (or all namespaces if namespace is empty)
+func (c *K8sClient) ListConfigMaps(namespace string) ([]models.ConfigMap, error) {
+	configMapList, err := c.clientset.CoreV1().ConfigMaps(namespace).List(c.ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list config maps: %w", err)
+	}
+
+	configMaps := make([]models.ConfigMap, 0, len(configMapList.Items))
+	for i := range configMapList.Items {
+		configMaps = append(configMaps, toConfigMap(&configMapList.Items[i]))
+	}
+	return configMaps, nil
+}
+
+// GetConfigMap gets a single config map
+func (c *K8sClient) GetConfigMap(namespace, name string) (*models.ConfigMap, error) {
+	cm, err := c.clientset.CoreV1().ConfigMaps(namespace).Get(c.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config map: %w", err)
+	}
+	configMap := toConfigMap(cm)
+	return &configMap, nil
+}
+
+// ListSecrets lists all secrets in a namespace with their values masked
+func (c *K8sClient) ListSecrets(namespace string) ([]models.Secret, error) {
+	secretList, err := c.clientset.CoreV1().Secrets(namespace).List(c.ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+
+	secrets := make([]models.Secret, 0, len(secretList.Items))
+	for i := range secretList.Items {
+		secrets = append(secrets, toSecret(&secretList.Items[i], false))
+	}
+	return secrets, nil
+}
+
+// GetSecret gets a single secret. Values are masked unless reveal is set.
+func (c *K8sClient) GetSecret(namespace, name string, reveal bool) (*models.Secret, error) {
+	s, err := c.clientset.CoreV1().Secrets(namespace).Get(c.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+	secret := toSecret(s, reveal)
+	return &secret, nil
+}
+
+// toConfigMap converts a Kubernetes config map to the API model
+func toConfigMap(cm *corev1.ConfigMap) models.ConfigMap {
+	configMap := models.ConfigMap{
+		Name:      cm.Name,
+		Namespace: cm.Namespace,
+		Data:      cm.Data,
+		Age:       cm.CreationTimestamp.Time,
+	}
+	for key := range cm.BinaryData {
+		configMap.BinaryKeys = append(configMap.BinaryKeys, key)
+	}
+	sort.Strings(configMap.BinaryKeys)
+	return configMap
+}
+
+// toSecret converts a Kubernetes secret to the API model, masking values unless reveal is set
+func toSecret(s *corev1.Secret, reveal bool) models.Secret {
+	secret := models.Secret{
+		Name:      s.Name,
+		Namespace: s.Namespace,
+		Type:      string(s.Type),
+		Data:      make(map[string]string, len(s.Data)),
+		Revealed:  reveal,
+		Age:       s.CreationTimestamp.Time,
+	}
+	for key, value := range s.Data {
+		if reveal {
+			secret.Data[key] = string(value)
+		} else {
+			secret.Data[key] = models.SecretMask
+		}
+	}
+	return secret
+}
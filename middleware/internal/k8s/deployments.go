@@ -3,8 +3,24 @@ package k8s
 import (
 	"fmt"
 	"imperm-middleware/pkg/models"
+	"sort"
+	"strconv"
+	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// revisionAnnotation is set by the deployment controller on deployments and their ReplicaSets
+	revisionAnnotation = "deployment.kubernetes.io/revision"
+
+	// changeCauseAnnotation records why a revision was created
+	changeCauseAnnotation = "kubernetes.io/change-cause"
+
+	// restartedAtAnnotation is the pod template annotation kubectl uses for rollout restart
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
 )
 
 // ListDeployments lists all deployments in a namespace (or all namespaces if namespace is empty)
@@ -16,57 +32,261 @@ func (c *K8sClient) ListDeployments(namespace string) ([]models.Deployment, erro
 		return nil, fmt.Errorf("failed to list deployments: %w", err)
 	}
 
+	return toDeployments(deploymentList.Items), nil
+}
+
+// toDeployments converts deployments to the model
+func toDeployments(items []appsv1.Deployment) []models.Deployment {
 	var deployments []models.Deployment
 
-	for _, deploy := range deploymentList.Items {
+	for _, deploy := range items {
 		// Calculate ready replicas string (e.g., "2/3")
 		ready := fmt.Sprintf("%d/%d", deploy.Status.ReadyReplicas, *deploy.Spec.Replicas)
 
 		d := models.Deployment{
-			Name:      deploy.Name,
-			Namespace: deploy.Namespace,
-			Ready:     ready,
-			UpToDate:  int(deploy.Status.UpdatedReplicas),
-			Available: int(deploy.Status.AvailableReplicas),
-			Age:       deploy.CreationTimestamp.Time,
+			Name:            deploy.Name,
+			Namespace:       deploy.Namespace,
+			Ready:           ready,
+			Replicas:        int(*deploy.Spec.Replicas),
+			UpToDate:        int(deploy.Status.UpdatedReplicas),
+			Available:       int(deploy.Status.AvailableReplicas),
+			Age:             deploy.CreationTimestamp.Time,
+			Paused:          deploy.Spec.Paused,
+			Revision:        revisionOf(deploy.Annotations),
+			RolloutComplete: rolloutComplete(&deploy),
 		}
 
 		deployments = append(deployments, d)
 	}
 
-	return deployments, nil
+	return deployments
 }
 
 // GetDeploymentEvents retrieves events for a specific deployment
-func (c *K8sClient) GetDeploymentEvents(namespace, deploymentName string) ([]models.Event, error) {
-	// Get events related to the deployment
-	fieldSelector := fmt.Sprintf("involvedObject.name=%s,involvedObject.namespace=%s", deploymentName, namespace)
-	eventList, err := c.clientset.CoreV1().Events(namespace).List(c.ctx, metav1.ListOptions{
-		FieldSelector: fieldSelector,
+func (c *K8sClient) GetDeploymentEvents(namespace, deploymentName string) ([]models.EventTimeEntry, error) {
+	return c.objectEvents(namespace, "Deployment", deploymentName)
+}
+
+// DeleteDeployment deletes a deployment in the specified namespace
+func (c *K8sClient) DeleteDeployment(namespace, deploymentName string) error {
+	deletePolicy := metav1.DeletePropagationForeground
+	return c.clientset.AppsV1().Deployments(namespace).Delete(c.ctx, deploymentName, metav1.DeleteOptions{
+		PropagationPolicy: &deletePolicy,
 	})
+}
+
+// ScaleDeployment sets the desired replica count of a deployment via the scale subresource
+func (c *K8sClient) ScaleDeployment(namespace, deploymentName string, replicas int) error {
+	if replicas < 0 {
+		return fmt.Errorf("replicas must not be negative")
+	}
+
+	scale, err := c.clientset.AppsV1().Deployments(namespace).GetScale(c.ctx, deploymentName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get deployment scale: %w", err)
+	}
+
+	scale.Spec.Replicas = int32(replicas)
+
+	_, err = c.clientset.AppsV1().Deployments(namespace).UpdateScale(c.ctx, deploymentName, scale, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to scale deployment: %w", err)
+	}
+
+	return nil
+}
+
+// RestartDeployment triggers a rolling restart by stamping the pod template, like kubectl rollout restart
+func (c *K8sClient) RestartDeployment(namespace, deploymentName string) error {
+	deploy, err := c.clientset.AppsV1().Deployments(namespace).Get(c.ctx, deploymentName, metav1.GetOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list events: %w", err)
+		return fmt.Errorf("failed to get deployment: %w", err)
 	}
 
-	var events []models.Event
-	for _, event := range eventList.Items {
-		e := models.Event{
-			Type:      event.Type,
-			Reason:    event.Reason,
-			Message:   event.Message,
-			Timestamp: event.LastTimestamp.Time,
-			Count:     int(event.Count),
+	if deploy.Spec.Paused {
+		return fmt.Errorf("cannot restart paused deployment %s (resume it first)", deploymentName)
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`,
+		restartedAtAnnotation, time.Now().Format(time.RFC3339))
+
+	_, err = c.clientset.AppsV1().Deployments(namespace).Patch(c.ctx, deploymentName, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to restart deployment: %w", err)
+	}
+
+	return nil
+}
+
+// PauseDeployment pauses rollouts of a deployment
+func (c *K8sClient) PauseDeployment(namespace, deploymentName string) error {
+	return c.setDeploymentPaused(namespace, deploymentName, true)
+}
+
+// ResumeDeployment resumes rollouts of a paused deployment
+func (c *K8sClient) ResumeDeployment(namespace, deploymentName string) error {
+	return c.setDeploymentPaused(namespace, deploymentName, false)
+}
+
+// setDeploymentPaused patches spec.paused on a deployment
+func (c *K8sClient) setDeploymentPaused(namespace, deploymentName string, paused bool) error {
+	patch := fmt.Sprintf(`{"spec":{"paused":%t}}`, paused)
+
+	_, err := c.clientset.AppsV1().Deployments(namespace).Patch(c.ctx, deploymentName, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update deployment: %w", err)
+	}
+
+	return nil
+}
+
+// GetDeploymentHistory lists the ReplicaSets owned by a deployment as rollout revisions, oldest first
+func (c *K8sClient) GetDeploymentHistory(namespace, deploymentName string) ([]models.DeploymentRevision, error) {
+	deploy, err := c.clientset.AppsV1().Deployments(namespace).Get(c.ctx, deploymentName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment: %w", err)
+	}
+
+	replicaSets, err := c.ownedReplicaSets(deploy)
+	if err != nil {
+		return nil, err
+	}
+
+	currentRevision := revisionOf(deploy.Annotations)
+
+	var history []models.DeploymentRevision
+	for _, rs := range replicaSets {
+		revision := revisionOf(rs.Annotations)
+
+		var images []string
+		for _, container := range rs.Spec.Template.Spec.Containers {
+			images = append(images, container.Image)
 		}
-		events = append(events, e)
+
+		replicas := 0
+		if rs.Spec.Replicas != nil {
+			replicas = int(*rs.Spec.Replicas)
+		}
+
+		history = append(history, models.DeploymentRevision{
+			Revision:    revision,
+			ReplicaSet:  rs.Name,
+			Images:      images,
+			ChangeCause: rs.Annotations[changeCauseAnnotation],
+			Replicas:    replicas,
+			Current:     revision == currentRevision,
+			Created:     rs.CreationTimestamp.Time,
+		})
 	}
 
-	return events, nil
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Revision < history[j].Revision
+	})
+
+	return history, nil
 }
 
-// DeleteDeployment deletes a deployment in the specified namespace
-func (c *K8sClient) DeleteDeployment(namespace, deploymentName string) error {
-	deletePolicy := metav1.DeletePropagationForeground
-	return c.clientset.AppsV1().Deployments(namespace).Delete(c.ctx, deploymentName, metav1.DeleteOptions{
-		PropagationPolicy: &deletePolicy,
+// RollbackDeployment rolls a deployment back to the pod template of a previous revision.
+// A revision of 0 means the revision before the current one.
+func (c *K8sClient) RollbackDeployment(namespace, deploymentName string, revision int64) error {
+	deploy, err := c.clientset.AppsV1().Deployments(namespace).Get(c.ctx, deploymentName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get deployment: %w", err)
+	}
+
+	if deploy.Spec.Paused {
+		return fmt.Errorf("cannot roll back paused deployment %s (resume it first)", deploymentName)
+	}
+
+	replicaSets, err := c.ownedReplicaSets(deploy)
+	if err != nil {
+		return err
+	}
+
+	currentRevision := revisionOf(deploy.Annotations)
+
+	var target *appsv1.ReplicaSet
+	for i := range replicaSets {
+		rs := &replicaSets[i]
+		rsRevision := revisionOf(rs.Annotations)
+
+		if revision == 0 {
+			// Pick the newest revision older than the current one
+			if rsRevision < currentRevision && (target == nil || rsRevision > revisionOf(target.Annotations)) {
+				target = rs
+			}
+		} else if rsRevision == revision {
+			target = rs
+			break
+		}
+	}
+
+	if target == nil {
+		if revision == 0 {
+			return fmt.Errorf("no previous revision found for deployment %s", deploymentName)
+		}
+		return fmt.Errorf("revision %d not found for deployment %s", revision, deploymentName)
+	}
+
+	if revisionOf(target.Annotations) == currentRevision {
+		return fmt.Errorf("deployment %s is already at revision %d", deploymentName, currentRevision)
+	}
+
+	// Copy the old template, dropping the hash label the controller adds to each ReplicaSet
+	template := target.Spec.Template.DeepCopy()
+	delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+	deploy.Spec.Template = *template
+
+	_, err = c.clientset.AppsV1().Deployments(namespace).Update(c.ctx, deploy, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to roll back deployment: %w", err)
+	}
+
+	return nil
+}
+
+// ownedReplicaSets returns the ReplicaSets controlled by a deployment
+func (c *K8sClient) ownedReplicaSets(deploy *appsv1.Deployment) ([]appsv1.ReplicaSet, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deploy.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deployment selector: %w", err)
+	}
+
+	rsList, err := c.clientset.AppsV1().ReplicaSets(deploy.Namespace).List(c.ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
 	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replica sets: %w", err)
+	}
+
+	var owned []appsv1.ReplicaSet
+	for _, rs := range rsList.Items {
+		if ref := metav1.GetControllerOf(&rs); ref != nil && ref.UID == deploy.UID {
+			owned = append(owned, rs)
+		}
+	}
+
+	return owned, nil
+}
+
+// revisionOf parses the rollout revision annotation, returning 0 if it is missing
+func revisionOf(annotations map[string]string) int64 {
+	revision, err := strconv.ParseInt(annotations[revisionAnnotation], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return revision
+}
+
+// rolloutComplete mirrors the checks kubectl rollout status uses to decide a rollout is done
+func rolloutComplete(deploy *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deploy.Spec.Replicas != nil {
+		replicas = *deploy.Spec.Replicas
+	}
+
+	return deploy.Status.ObservedGeneration >= deploy.Generation &&
+		deploy.Status.UpdatedReplicas == replicas &&
+		deploy.Status.Replicas == replicas &&
+		deploy.Status.AvailableReplicas == replicas
 }
@@ -0,0 +1,104 @@
+package k8s
+
+import (
+	"fmt"
+	"path"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// DefaultSelector matches the namespaces imperm manages; CreateEnvironment and
+// the Terraform module both label their namespaces with it
+const DefaultSelector = "managed-by=imperm"
+
+// DefaultExclude are the name patterns never listed as environments unless configured otherwise
+var DefaultExclude = []string{"kube-*", "default"}
+
+// Discovery decides which namespaces are environments. Namespaces matching
+// Selector are managed environments; the others are only listed on request,
+// as unmanaged ones. Patterns use path.Match syntax, e.g. "team-*".
+type Discovery struct {
+	Selector string   // Label selector of managed namespaces, empty for every namespace
+	Include  []string // Patterns a namespace must match one of to be listed at all, empty for any name
+	Exclude  []string // Patterns of namespaces never listed, even as unmanaged
+}
+
+// DefaultDiscovery lists the namespaces labelled by imperm, outside the system ones
+func DefaultDiscovery() Discovery {
+	return Discovery{Selector: DefaultSelector, Exclude: append([]string{}, DefaultExclude...)}
+}
+
+// Check reports an invalid selector or pattern
+func (d Discovery) Check() error {
+	if _, err := labels.Parse(d.Selector); err != nil {
+		return fmt.Errorf("invalid selector %q: %w", d.Selector, err)
+	}
+	for _, pattern := range append(append([]string{}, d.Include...), d.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// ManagedLabels returns the labels a namespace needs for discovery to find it managed
+func (c *K8sClient) ManagedLabels() (map[string]string, error) {
+	return c.namespaces.managedLabels()
+}
+
+// namespaceFilter is a Discovery ready to match namespaces with
+type namespaceFilter struct {
+	selector labels.Selector
+	include  []string
+	exclude  []string
+}
+
+func newNamespaceFilter(d Discovery) (*namespaceFilter, error) {
+	if err := d.Check(); err != nil {
+		return nil, err
+	}
+	selector, _ := labels.Parse(d.Selector)
+	return &namespaceFilter{selector: selector, include: d.Include, exclude: d.Exclude}, nil
+}
+
+// listed reports whether a namespace may be listed at all, managed or not
+func (f *namespaceFilter) listed(name string) bool {
+	if matchAny(f.exclude, name) {
+		return false
+	}
+	return len(f.include) == 0 || matchAny(f.include, name)
+}
+
+// managed reports whether a namespace's labels make it a managed environment
+func (f *namespaceFilter) managed(namespaceLabels map[string]string) bool {
+	return f.selector.Matches(labels.Set(namespaceLabels))
+}
+
+// managedLabels are the labels that make a namespace match the selector. Only
+// selectors made of equalities can be applied; the others fail, since no
+// labels could make an adopted namespace count as managed.
+func (f *namespaceFilter) managedLabels() (map[string]string, error) {
+	requirements, _ := f.selector.Requirements()
+	managedLabels := make(map[string]string, len(requirements))
+	for _, requirement := range requirements {
+		values := requirement.Values().List()
+		switch {
+		case requirement.Operator() == selection.Equals, requirement.Operator() == selection.DoubleEquals:
+		case requirement.Operator() == selection.In && len(values) == 1:
+		default:
+			return nil, fmt.Errorf("selector %q can't be applied as labels, only key=value requirements can", f.selector)
+		}
+		managedLabels[requirement.Key()] = values[0]
+	}
+	return managedLabels, nil
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
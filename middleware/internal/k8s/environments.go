@@ -1,42 +1,68 @@
 package k8s
 
 import (
+	"encoding/json"
 	"fmt"
+	"imperm-middleware/internal/capacity"
 	"imperm-middleware/pkg/models"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// ListEnvironments lists all environments (namespaces with their resources)
-func (c *K8sClient) ListEnvironments() ([]models.Environment, error) {
-	namespaces, err := c.clientset.CoreV1().Namespaces().List(c.ctx, metav1.ListOptions{})
+// ListEnvironments lists the namespaces discovery finds (with their
+// resources): the managed ones, and the unmanaged ones too if asked for
+func (c *K8sClient) ListEnvironments(filter models.EnvironmentFilter) ([]models.Environment, error) {
+	// Without unmanaged namespaces the API server can do the selecting
+	listOptions := metav1.ListOptions{}
+	if !filter.Unmanaged {
+		listOptions.LabelSelector = c.namespaces.selector.String()
+	}
+	namespaces, err := c.clientset.CoreV1().Namespaces().List(c.ctx, listOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list namespaces: %w", err)
 	}
 
 	var environments []models.Environment
+	now := time.Now()
 
 	for _, ns := range namespaces.Items {
-		// Skip system namespaces
-		if isSystemNamespace(ns.Name) {
+		// Skip namespaces excluded by name, such as system ones
+		if !c.namespaces.listed(ns.Name) {
+			continue
+		}
+		managed := c.namespaces.managed(ns.Labels)
+		if !managed && !filter.Unmanaged {
 			continue
 		}
 
 		// Get pods for this namespace
-		pods, err := c.ListPods(ns.Name)
-		if err != nil {
-			// Log error but continue
-			pods = []models.Pod{}
+		var pods []corev1.Pod
+		if podList, err := c.clientset.CoreV1().Pods(ns.Name).List(c.ctx, metav1.ListOptions{}); err == nil {
+			pods = podList.Items
 		}
 
 		// Get deployments for this namespace
-		deployments, err := c.ListDeployments(ns.Name)
-		if err != nil {
-			// Log error but continue
-			deployments = []models.Deployment{}
+		var deployments []appsv1.Deployment
+		if deploymentList, err := c.clientset.AppsV1().Deployments(ns.Name).List(c.ctx, metav1.ListOptions{}); err == nil {
+			deployments = deploymentList.Items
+		}
+
+		// Warning events feed into health; a failed list just leaves them out
+		var events []eventsv1.Event
+		if eventList, err := c.clientset.EventsV1().Events(ns.Name).List(c.ctx, metav1.ListOptions{
+			FieldSelector: "type=" + corev1.EventTypeWarning,
+		}); err == nil {
+			events = eventList.Items
+		}
+
+		// Quota usage is informational; a failed list just leaves it out
+		var quotas []corev1.ResourceQuota
+		if quotaList, err := c.clientset.CoreV1().ResourceQuotas(ns.Name).List(c.ctx, metav1.ListOptions{}); err == nil {
+			quotas = quotaList.Items
 		}
 
 		env := models.Environment{
@@ -44,8 +70,11 @@ func (c *K8sClient) ListEnvironments() ([]models.Environment, error) {
 			Namespace:   ns.Name,
 			Status:      string(ns.Status.Phase),
 			Age:         ns.CreationTimestamp.Time,
-			Pods:        pods,
-			Deployments: deployments,
+			Pods:        c.toPods(pods, c.listPodUsage(ns.Name)),
+			Deployments: toDeployments(deployments),
+			Health:      evaluateHealth(&ns, pods, deployments, events, now),
+			Quota:       quotaUsage(quotas),
+			Managed:     managed,
 		}
 
 		environments = append(environments, env)
@@ -54,28 +83,61 @@ func (c *K8sClient) ListEnvironments() ([]models.Environment, error) {
 	return environments, nil
 }
 
+// optionsAnnotation stores the options an environment was created with, so it can be cloned
+const optionsAnnotation = "deployment-options"
+
 // CreateEnvironment creates a new environment (namespace + optional starter resources)
 func (c *K8sClient) CreateEnvironment(name string, options *models.DeploymentOptions) error {
+	// Refuse replicas that could never be scheduled within the environment's own quota
+	if err := capacity.CheckQuota(options); err != nil {
+		return err
+	}
+
+	// Label the namespace so discovery finds it managed
+	labels, err := c.namespaces.managedLabels()
+	if err != nil {
+		return err
+	}
+	if _, ok := labels["environment"]; !ok {
+		labels["environment"] = name
+	}
+
 	// Create namespace
 	namespace := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: name,
-			Labels: map[string]string{
-				"managed-by": "imperm",
-			},
+			Name:   name,
+			Labels: labels,
 			Annotations: map[string]string{
 				"created-at": time.Now().Format(time.RFC3339),
 			},
 		},
 	}
+	if options != nil {
+		data, err := json.Marshal(options)
+		if err != nil {
+			return fmt.Errorf("failed to encode deployment options: %w", err)
+		}
+		namespace.Annotations[optionsAnnotation] = string(data)
+	}
 
-	_, err := c.clientset.CoreV1().Namespaces().Create(c.ctx, namespace, metav1.CreateOptions{})
+	_, err = c.clientset.CoreV1().Namespaces().Create(c.ctx, namespace, metav1.CreateOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to create namespace: %w", err)
 	}
 
-	// If options provided, create resources
-	if options != nil && options.HasVariables() {
+	// Without its guardrails the environment is only half created, so the
+	// namespace is removed rather than left listed as managed
+	if options != nil {
+		if err := c.createGuardrails(name, options); err != nil {
+			if deleteErr := c.clientset.CoreV1().Namespaces().Delete(c.ctx, name, metav1.DeleteOptions{}); deleteErr != nil {
+				return fmt.Errorf("%w (and failed to delete namespace: %v)", err, deleteErr)
+			}
+			return err
+		}
+	}
+
+	// If options ask for workloads, create resources; quota defaults alone don't
+	if options != nil && options.HasWorkloadVariables() {
 		if err := c.createSampleDeployment(name); err != nil {
 			// Namespace was created, so don't fail completely
 			// Just log the error (in production, you'd want proper logging)
@@ -96,6 +158,29 @@ func (c *K8sClient) DestroyEnvironment(name string) error {
 	return nil
 }
 
+// CloneEnvironment creates a new environment with the options stored on an
+// existing one, applying the request's variable overrides
+func (c *K8sClient) CloneEnvironment(sourceName string, req models.CloneEnvironmentRequest) (*models.DeploymentOptions, error) {
+	source, err := c.clientset.CoreV1().Namespaces().Get(c.ctx, sourceName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find environment %s: %w", sourceName, err)
+	}
+
+	// Environments created without options have nothing stored and clone as empty ones
+	stored := &models.DeploymentOptions{Name: sourceName}
+	if data, ok := source.Annotations[optionsAnnotation]; ok {
+		if err := json.Unmarshal([]byte(data), stored); err != nil {
+			return nil, fmt.Errorf("failed to decode options of environment %s: %w", sourceName, err)
+		}
+	}
+
+	options := stored.Clone(req.Name, req.Variables)
+	if err := c.CreateEnvironment(req.Name, options); err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
 // GetEnvironmentHistory returns history of environment operations
 // TODO: Implement persistent storage for history
 func (c *K8sClient) GetEnvironmentHistory() ([]models.EnvironmentHistory, error) {
@@ -159,7 +244,7 @@ func (c *K8sClient) GetResourceStats(resourceType, namespace string) (*models.Re
 
 	switch resourceType {
 	case "environments":
-		envs, err := c.ListEnvironments()
+		envs, err := c.ListEnvironments(models.EnvironmentFilter{})
 		if err != nil {
 			return nil, err
 		}
@@ -177,7 +262,7 @@ func (c *K8sClient) GetResourceStats(resourceType, namespace string) (*models.Re
 		}
 		stats.TotalCount = len(pods)
 		for _, pod := range pods {
-			switch pod.Status {
+			switch pod.Phase {
 			case "Running":
 				stats.RunningPods++
 			case "Pending":
@@ -197,25 +282,47 @@ func (c *K8sClient) GetResourceStats(resourceType, namespace string) (*models.Re
 			stats.TotalReplicas += dep.UpToDate
 			stats.AvailableReplicas += dep.Available
 		}
+
+	case "services", "configmaps", "secrets", "ingresses", "jobs", "statefulsets":
+		count, err := c.countResources(resourceType, namespace)
+		if err != nil {
+			return nil, err
+		}
+		stats.TotalCount = count
 	}
 
 	return stats, nil
 }
 
-// isSystemNamespace checks if a namespace is a system namespace to skip
-func isSystemNamespace(name string) bool {
-	systemNamespaces := []string{
-		"kube-system",
-		"kube-public",
-		"kube-node-lease",
-		"default", // Optional: uncomment if you want to hide default namespace
-	}
-
-	for _, sys := range systemNamespaces {
-		if name == sys {
-			return true
-		}
+// countResources counts the objects of a kind that only needs a total in its stats
+func (c *K8sClient) countResources(resourceType, namespace string) (int, error) {
+	var count int
+	var err error
+	switch resourceType {
+	case "services":
+		var items []models.Service
+		items, err = c.ListServices(namespace)
+		count = len(items)
+	case "configmaps":
+		var items []models.ConfigMap
+		items, err = c.ListConfigMaps(namespace)
+		count = len(items)
+	case "secrets":
+		var items []models.Secret
+		items, err = c.ListSecrets(namespace)
+		count = len(items)
+	case "ingresses":
+		var items []models.Ingress
+		items, err = c.ListIngresses(namespace)
+		count = len(items)
+	case "jobs":
+		var items []models.Job
+		items, err = c.ListJobs(namespace)
+		count = len(items)
+	case "statefulsets":
+		var items []models.StatefulSet
+		items, err = c.ListStatefulSets(namespace)
+		count = len(items)
 	}
-
-	return false
+	return count, err
 }
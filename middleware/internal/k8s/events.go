@@ -0,0 +1,141 @@
+package k8s
+
+import (
+	"fmt"
+	"imperm-middleware/pkg/models"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetEnvironmentEvents returns the events of an environment's namespace merged
+// with its creation by imperm, newest first
+func (c *K8sClient) GetEnvironmentEvents(envName string, filter models.EventFilter) ([]models.TimelineEvent, error) {
+	// Environments are namespaces of the same name
+	events, err := c.ListNamespaceEvents(envName)
+	if err != nil {
+		return nil, err
+	}
+
+	ns, err := c.clientset.CoreV1().Namespaces().Get(c.ctx, envName, metav1.GetOptions{})
+	if err == nil && c.namespaces.managed(ns.Labels) {
+		events = append(events, models.TimelineEvent{
+			Time:    ns.CreationTimestamp.Time,
+			Type:    corev1.EventTypeNormal,
+			Reason:  "Created",
+			Message: "environment namespace created",
+			Source:  models.EventSourceImperm,
+			Kind:    "Environment",
+			Name:    envName,
+			Count:   1,
+		})
+	}
+
+	return models.FilterTimeline(events, filter), nil
+}
+
+// ListNamespaceEvents lists every Kubernetes event recorded in a namespace as timeline entries
+func (c *K8sClient) ListNamespaceEvents(namespace string) ([]models.TimelineEvent, error) {
+	eventList, err := c.clientset.EventsV1().Events(namespace).List(c.ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	events := make([]models.TimelineEvent, 0, len(eventList.Items))
+	for i := range eventList.Items {
+		event := &eventList.Items[i]
+		_, last, count := eventTimes(event)
+		events = append(events, models.TimelineEvent{
+			Time:    last,
+			Type:    event.Type,
+			Reason:  event.Reason,
+			Message: event.Note,
+			Source:  models.EventSourceKubernetes,
+			Kind:    event.Regarding.Kind,
+			Name:    event.Regarding.Name,
+			Count:   count,
+		})
+	}
+	return events, nil
+}
+
+// objectEvents lists the events regarding one object, most recently seen first
+func (c *K8sClient) objectEvents(namespace, kind, name string) ([]models.EventTimeEntry, error) {
+	fieldSelector := fmt.Sprintf("regarding.kind=%s,regarding.name=%s,regarding.namespace=%s", kind, name, namespace)
+	eventList, err := c.clientset.EventsV1().Events(namespace).List(c.ctx, metav1.ListOptions{
+		FieldSelector: fieldSelector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	events := make([]models.EventTimeEntry, 0, len(eventList.Items))
+	for i := range eventList.Items {
+		events = append(events, toEventTimeEntry(&eventList.Items[i]))
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LastObservedTime.After(events[j].LastObservedTime)
+	})
+	return events, nil
+}
+
+func toEventTimeEntry(event *eventsv1.Event) models.EventTimeEntry {
+	first, last, count := eventTimes(event)
+	return models.EventTimeEntry{
+		Type:              event.Type,
+		Reason:            event.Reason,
+		From:              eventSource(event),
+		Message:           event.Note,
+		Count:             count,
+		FirstObservedTime: first,
+		LastObservedTime:  last,
+	}
+}
+
+// eventTimes works out when an event was first and last seen and how often it
+// happened. Events written through events.k8s.io carry an event time and, once
+// repeated, a series; events written through the core API only fill the
+// deprecated timestamps and count.
+func eventTimes(event *eventsv1.Event) (first, last time.Time, count int) {
+	first = event.EventTime.Time
+	if first.IsZero() {
+		first = event.DeprecatedFirstTimestamp.Time
+	}
+	if first.IsZero() {
+		first = event.CreationTimestamp.Time
+	}
+
+	count = 1
+	switch {
+	case event.Series != nil:
+		last = event.Series.LastObservedTime.Time
+		count = int(event.Series.Count)
+	case event.DeprecatedCount > 0:
+		last = event.DeprecatedLastTimestamp.Time
+		count = int(event.DeprecatedCount)
+	}
+	if last.IsZero() {
+		last = event.DeprecatedLastTimestamp.Time
+	}
+	if last.IsZero() || last.Before(first) {
+		last = first
+	}
+	if count < 1 {
+		count = 1
+	}
+	return first, last, count
+}
+
+// eventSource names the component that reported an event, e.g. "kubelet"
+func eventSource(event *eventsv1.Event) string {
+	if event.DeprecatedSource.Component != "" {
+		return event.DeprecatedSource.Component
+	}
+	if event.ReportingController != "" {
+		return event.ReportingController
+	}
+	return event.ReportingInstance
+}
@@ -0,0 +1,109 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"imperm-middleware/pkg/client"
+	"imperm-middleware/pkg/models"
+	"net/url"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// ExecPod runs a command inside a pod container and streams it until the
+// command exits or ctx is cancelled. WebSocket is tried first and SPDY is
+// used for API servers that don't support it.
+func (c *K8sClient) ExecPod(ctx context.Context, req models.ExecRequest, streams client.ExecStreams) error {
+	if len(req.Command) == 0 {
+		return fmt.Errorf("exec command is required")
+	}
+
+	pod, err := c.clientset.CoreV1().Pods(req.Namespace).Get(ctx, req.Pod, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get pod: %w", err)
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return fmt.Errorf("pod %s is %s, not Running", req.Pod, pod.Status.Phase)
+	}
+
+	container := req.Container
+	if container == "" {
+		if len(pod.Spec.Containers) == 0 {
+			return fmt.Errorf("pod has no containers")
+		}
+		container = pod.Spec.Containers[0].Name
+	}
+
+	execReq := c.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(req.Namespace).
+		Name(req.Pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   req.Command,
+			Stdin:     streams.Stdin != nil,
+			Stdout:    streams.Stdout != nil,
+			Stderr:    streams.Stderr != nil && !req.TTY,
+			TTY:       req.TTY,
+		}, scheme.ParameterCodec)
+
+	executor, err := c.newExecutor(execReq.URL())
+	if err != nil {
+		return err
+	}
+
+	options := remotecommand.StreamOptions{
+		Stdin:  streams.Stdin,
+		Stdout: streams.Stdout,
+		Tty:    req.TTY,
+	}
+	if !req.TTY {
+		options.Stderr = streams.Stderr
+	}
+	if streams.Resize != nil {
+		options.TerminalSizeQueue = sizeQueue(streams.Resize)
+	}
+
+	if err := executor.StreamWithContext(ctx, options); err != nil {
+		return fmt.Errorf("exec failed: %w", err)
+	}
+	return nil
+}
+
+// newExecutor builds a WebSocket executor with SPDY fallback for the exec URL
+func (c *K8sClient) newExecutor(execURL *url.URL) (remotecommand.Executor, error) {
+	if c.config == nil {
+		return nil, fmt.Errorf("kubernetes config not available")
+	}
+
+	websocketExec, err := remotecommand.NewWebSocketExecutor(c.config, "GET", execURL.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create websocket executor: %w", err)
+	}
+
+	spdyExec, err := remotecommand.NewSPDYExecutor(c.config, "POST", execURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spdy executor: %w", err)
+	}
+
+	return remotecommand.NewFallbackExecutor(websocketExec, spdyExec, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+}
+
+// sizeQueue adapts a channel of terminal sizes to remotecommand.TerminalSizeQueue
+type sizeQueue <-chan models.TerminalSize
+
+// Next blocks until the next resize arrives, returning nil once the channel closes
+func (q sizeQueue) Next() *remotecommand.TerminalSize {
+	size, ok := <-q
+	if !ok {
+		return nil
+	}
+	return &remotecommand.TerminalSize{Width: size.Width, Height: size.Height}
+}
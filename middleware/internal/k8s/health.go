@@ -0,0 +1,223 @@
+package k8s
+
+import (
+	"fmt"
+	"imperm-middleware/pkg/models"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+)
+
+const (
+	// startupGrace is how long pods and deployments may take to come up
+	// before they count against an environment's health
+	startupGrace = 5 * time.Minute
+
+	// restartWindow is how recent a container restart must be to count as a crash loop
+	restartWindow = 10 * time.Minute
+
+	// restartThreshold is the restart count from which recent restarts degrade health
+	restartThreshold = 3
+
+	// warningWindow is how far back warning events are counted
+	warningWindow = 15 * time.Minute
+
+	// warningThreshold is the number of recent warning events that degrades health
+	warningThreshold = 5
+)
+
+// failingWaitingReasons are container waiting reasons that keep a pod from ever running
+var failingWaitingReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// healthSeverity orders health statuses from best to worst
+var healthSeverity = map[string]int{
+	models.HealthHealthy:      0,
+	models.HealthProvisioning: 1,
+	models.HealthDegraded:     2,
+	models.HealthFailing:      3,
+}
+
+// healthReason is one finding of the evaluator with the status it implies
+type healthReason struct {
+	status  string
+	message string
+}
+
+// healthReport collects findings while evaluating an environment
+type healthReport struct {
+	reasons []healthReason
+}
+
+func (r *healthReport) add(status, format string, args ...interface{}) {
+	r.reasons = append(r.reasons, healthReason{status: status, message: fmt.Sprintf(format, args...)})
+}
+
+// result returns the worst status found, with reasons ordered most severe first
+func (r *healthReport) result() models.EnvironmentHealth {
+	sort.SliceStable(r.reasons, func(i, j int) bool {
+		return healthSeverity[r.reasons[i].status] > healthSeverity[r.reasons[j].status]
+	})
+
+	health := models.EnvironmentHealth{Status: models.HealthHealthy}
+	for _, reason := range r.reasons {
+		if healthSeverity[reason.status] > healthSeverity[health.Status] {
+			health.Status = reason.status
+		}
+		health.Reasons = append(health.Reasons, reason.message)
+	}
+	return health
+}
+
+// evaluateHealth rolls pod phases, container waiting reasons, restart rates,
+// deployment availability and recent warning events of a namespace up into
+// a single health status
+func evaluateHealth(ns *corev1.Namespace, pods []corev1.Pod, deployments []appsv1.Deployment, events []eventsv1.Event, now time.Time) models.EnvironmentHealth {
+	var report healthReport
+
+	if ns.Status.Phase == corev1.NamespaceTerminating {
+		report.add(models.HealthDegraded, "namespace is terminating")
+	}
+
+	// A brand new environment with nothing in it yet is still being set up
+	if len(pods) == 0 && len(deployments) == 0 && now.Sub(ns.CreationTimestamp.Time) < startupGrace {
+		report.add(models.HealthProvisioning, "waiting for workloads")
+	}
+
+	for i := range pods {
+		evaluatePod(&report, &pods[i], now)
+	}
+	for i := range deployments {
+		evaluateDeployment(&report, &deployments[i], now)
+	}
+	evaluateEvents(&report, events, now)
+
+	return report.result()
+}
+
+func evaluatePod(report *healthReport, pod *corev1.Pod, now time.Time) {
+	age := now.Sub(pod.CreationTimestamp.Time)
+
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return
+	case corev1.PodFailed:
+		reason := pod.Status.Reason
+		if reason == "" {
+			reason = "Failed"
+		}
+		report.add(models.HealthDegraded, "pod %s: %s", pod.Name, reason)
+		return
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+
+	failing := false
+	for _, status := range statuses {
+		if waiting := status.State.Waiting; waiting != nil && failingWaitingReasons[waiting.Reason] {
+			report.add(models.HealthFailing, "pod %s: container %s %s", pod.Name, status.Name, waiting.Reason)
+			failing = true
+			continue
+		}
+
+		last := status.LastTerminationState.Terminated
+		if last == nil || now.Sub(last.FinishedAt.Time) > restartWindow {
+			continue
+		}
+		if last.Reason == "OOMKilled" {
+			report.add(models.HealthDegraded, "pod %s: container %s was OOMKilled %s ago", pod.Name, status.Name, formatDuration(now.Sub(last.FinishedAt.Time)))
+		} else if status.RestartCount >= restartThreshold {
+			report.add(models.HealthDegraded, "pod %s: container %s restarting (%d restarts)", pod.Name, status.Name, status.RestartCount)
+		}
+	}
+	if failing {
+		return
+	}
+
+	switch {
+	case pod.Status.Phase == corev1.PodPending && age < startupGrace:
+		report.add(models.HealthProvisioning, "pod %s is starting", pod.Name)
+	case pod.Status.Phase == corev1.PodPending:
+		report.add(models.HealthDegraded, "pod %s pending for %s", pod.Name, formatDuration(age))
+	case !podReady(pod) && age >= startupGrace:
+		report.add(models.HealthDegraded, "pod %s is not ready", pod.Name)
+	}
+}
+
+func evaluateDeployment(report *healthReport, deploy *appsv1.Deployment, now time.Time) {
+	desired := int32(1)
+	if deploy.Spec.Replicas != nil {
+		desired = *deploy.Spec.Replicas
+	}
+	available := deploy.Status.AvailableReplicas
+	if desired == 0 || available >= desired {
+		return
+	}
+
+	stalled := false
+	for _, cond := range deploy.Status.Conditions {
+		if cond.Type == appsv1.DeploymentProgressing && cond.Reason == "ProgressDeadlineExceeded" {
+			stalled = true
+		}
+	}
+
+	starting := !stalled && now.Sub(deploy.CreationTimestamp.Time) < startupGrace
+	switch {
+	case available == 0 && !starting:
+		report.add(models.HealthFailing, "deployment %s has no available replicas (0/%d)", deploy.Name, desired)
+	case stalled:
+		report.add(models.HealthDegraded, "deployment %s rollout stalled (%d/%d available)", deploy.Name, available, desired)
+	case starting || deploy.Status.UpdatedReplicas < desired:
+		report.add(models.HealthProvisioning, "deployment %s rolling out (%d/%d available)", deploy.Name, available, desired)
+	default:
+		report.add(models.HealthDegraded, "deployment %s has %d/%d available replicas", deploy.Name, available, desired)
+	}
+}
+
+func evaluateEvents(report *healthReport, events []eventsv1.Event, now time.Time) {
+	count := 0
+	var latest *eventsv1.Event
+	var latestTime time.Time
+	for i := range events {
+		event := &events[i]
+		if event.Type != corev1.EventTypeWarning {
+			continue
+		}
+		_, seen, n := eventTimes(event)
+		if now.Sub(seen) > warningWindow {
+			continue
+		}
+		count += n
+		if seen.After(latestTime) {
+			latest, latestTime = event, seen
+		}
+	}
+
+	if count >= warningThreshold {
+		report.add(models.HealthDegraded, "%d warning events in the last %s (latest: %s)", count, formatDuration(warningWindow), latest.Reason)
+	}
+}
+
+// formatDuration renders a duration in its largest whole unit, like kubectl ages
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
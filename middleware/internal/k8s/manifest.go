@@ -0,0 +1,489 @@
+package k8s
+
+import (
+	"fmt"
+	"imperm-middleware/pkg/models"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+// GetResourceManifest gets the live object of a resource as YAML, with managed
+// fields stripped, together with a describe-style summary. Secret values are
+// masked unless reveal is set.
+func (c *K8sClient) GetResourceManifest(kind, namespace, name string, reveal bool) (*models.ResourceManifest, error) {
+	obj, describe, err := c.getDescribedObject(kind, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", kind, err)
+	}
+
+	// Typed objects from the clientset come without apiVersion/kind
+	if gvks, _, err := scheme.Scheme.ObjectKinds(obj); err == nil && len(gvks) > 0 {
+		obj.GetObjectKind().SetGroupVersionKind(gvks[0])
+	}
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata: %w", err)
+	}
+	accessor.SetManagedFields(nil)
+
+	describe.Labels = accessor.GetLabels()
+	describe.Annotations = accessor.GetAnnotations()
+	for _, ref := range accessor.GetOwnerReferences() {
+		describe.OwnerReferences = append(describe.OwnerReferences, models.OwnerReference{
+			Kind:       ref.Kind,
+			Name:       ref.Name,
+			Controller: ref.Controller != nil && *ref.Controller,
+		})
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s: %w", kind, err)
+	}
+	if kind == models.KindSecret && !reveal {
+		maskSecret(content)
+		describe.Annotations = (&unstructured.Unstructured{Object: content}).GetAnnotations()
+	}
+
+	out, err := yaml.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render %s as YAML: %w", kind, err)
+	}
+
+	return &models.ResourceManifest{
+		Kind:      kind,
+		Namespace: accessor.GetNamespace(),
+		Name:      accessor.GetName(),
+		YAML:      string(out),
+		Describe:  describe,
+	}, nil
+}
+
+// maskSecret masks the values of a Secret in its unstructured content: data,
+// stringData and the last-applied-configuration annotation, which kubectl
+// apply fills with the whole object, values included
+func maskSecret(content map[string]interface{}) {
+	for _, field := range []string{"data", "stringData"} {
+		if values, ok := content[field].(map[string]interface{}); ok {
+			for key := range values {
+				values[key] = models.SecretMask
+			}
+		}
+	}
+
+	annotations, ok, _ := unstructured.NestedStringMap(content, "metadata", "annotations")
+	if _, applied := annotations[corev1.LastAppliedConfigAnnotation]; ok && applied {
+		annotations[corev1.LastAppliedConfigAnnotation] = models.SecretMask
+		_ = unstructured.SetNestedStringMap(content, annotations, "metadata", "annotations")
+	}
+}
+
+// getDescribedObject fetches a typed object and builds its kind-specific describe sections
+func (c *K8sClient) getDescribedObject(kind, namespace, name string) (runtime.Object, models.ResourceDescription, error) {
+	opts := metav1.GetOptions{}
+
+	switch kind {
+	case models.KindPod:
+		pod, err := c.clientset.CoreV1().Pods(namespace).Get(c.ctx, name, opts)
+		if err != nil {
+			return nil, models.ResourceDescription{}, err
+		}
+		return pod, describePod(pod), nil
+
+	case models.KindDeployment:
+		dep, err := c.clientset.AppsV1().Deployments(namespace).Get(c.ctx, name, opts)
+		if err != nil {
+			return nil, models.ResourceDescription{}, err
+		}
+		return dep, describeDeployment(dep), nil
+
+	case models.KindStatefulSet:
+		sts, err := c.clientset.AppsV1().StatefulSets(namespace).Get(c.ctx, name, opts)
+		if err != nil {
+			return nil, models.ResourceDescription{}, err
+		}
+		return sts, describeStatefulSet(sts), nil
+
+	case models.KindJob:
+		job, err := c.clientset.BatchV1().Jobs(namespace).Get(c.ctx, name, opts)
+		if err != nil {
+			return nil, models.ResourceDescription{}, err
+		}
+		return job, describeJob(job), nil
+
+	case models.KindService:
+		svc, err := c.clientset.CoreV1().Services(namespace).Get(c.ctx, name, opts)
+		if err != nil {
+			return nil, models.ResourceDescription{}, err
+		}
+		return svc, describeService(svc), nil
+
+	case models.KindConfigMap:
+		cm, err := c.clientset.CoreV1().ConfigMaps(namespace).Get(c.ctx, name, opts)
+		if err != nil {
+			return nil, models.ResourceDescription{}, err
+		}
+		return cm, describeConfigMap(cm), nil
+
+	case models.KindSecret:
+		secret, err := c.clientset.CoreV1().Secrets(namespace).Get(c.ctx, name, opts)
+		if err != nil {
+			return nil, models.ResourceDescription{}, err
+		}
+		return secret, describeSecret(secret), nil
+
+	case models.KindIngress:
+		ing, err := c.clientset.NetworkingV1().Ingresses(namespace).Get(c.ctx, name, opts)
+		if err != nil {
+			return nil, models.ResourceDescription{}, err
+		}
+		return ing, describeIngress(ing), nil
+
+	case models.KindNamespace:
+		ns, err := c.clientset.CoreV1().Namespaces().Get(c.ctx, name, opts)
+		if err != nil {
+			return nil, models.ResourceDescription{}, err
+		}
+		return ns, describeNamespace(ns), nil
+	}
+
+	return nil, models.ResourceDescription{}, fmt.Errorf("unsupported resource kind %q", kind)
+}
+
+func describePod(pod *corev1.Pod) models.ResourceDescription {
+	d := models.ResourceDescription{
+		Fields: []models.DescribeField{
+			{Name: "Status", Value: string(pod.Status.Phase)},
+			{Name: "Node", Value: pod.Spec.NodeName},
+			{Name: "IP", Value: pod.Status.PodIP},
+			{Name: "QoS Class", Value: string(pod.Status.QOSClass)},
+			{Name: "Service Account", Value: pod.Spec.ServiceAccountName},
+		},
+		Volumes: describeVolumes(pod.Spec.Volumes),
+	}
+	if pod.Status.StartTime != nil {
+		d.Fields = append(d.Fields, models.DescribeField{Name: "Start Time", Value: pod.Status.StartTime.UTC().Format("2006-01-02 15:04:05 MST")})
+	}
+
+	for _, cond := range pod.Status.Conditions {
+		d.Conditions = append(d.Conditions, models.ResourceCondition{
+			Type:               string(cond.Type),
+			Status:             string(cond.Status),
+			Reason:             cond.Reason,
+			Message:            cond.Message,
+			LastTransitionTime: cond.LastTransitionTime.Time,
+		})
+	}
+
+	d.Containers = describeContainers(pod.Spec, pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses)
+	return d
+}
+
+func describeDeployment(dep *appsv1.Deployment) models.ResourceDescription {
+	desired := int32(1)
+	if dep.Spec.Replicas != nil {
+		desired = *dep.Spec.Replicas
+	}
+
+	d := models.ResourceDescription{
+		Fields: []models.DescribeField{
+			{Name: "Replicas", Value: fmt.Sprintf("%d desired | %d updated | %d total | %d available | %d unavailable",
+				desired, dep.Status.UpdatedReplicas, dep.Status.Replicas, dep.Status.AvailableReplicas, dep.Status.UnavailableReplicas)},
+			{Name: "Strategy", Value: string(dep.Spec.Strategy.Type)},
+			{Name: "Selector", Value: metav1.FormatLabelSelector(dep.Spec.Selector)},
+			{Name: "Paused", Value: fmt.Sprintf("%t", dep.Spec.Paused)},
+		},
+		Containers: describeContainers(dep.Spec.Template.Spec, nil, nil),
+		Volumes:    describeVolumes(dep.Spec.Template.Spec.Volumes),
+	}
+
+	for _, cond := range dep.Status.Conditions {
+		d.Conditions = append(d.Conditions, models.ResourceCondition{
+			Type:               string(cond.Type),
+			Status:             string(cond.Status),
+			Reason:             cond.Reason,
+			Message:            cond.Message,
+			LastTransitionTime: cond.LastTransitionTime.Time,
+		})
+	}
+	return d
+}
+
+func describeStatefulSet(sts *appsv1.StatefulSet) models.ResourceDescription {
+	desired := int32(1)
+	if sts.Spec.Replicas != nil {
+		desired = *sts.Spec.Replicas
+	}
+
+	d := models.ResourceDescription{
+		Fields: []models.DescribeField{
+			{Name: "Replicas", Value: fmt.Sprintf("%d desired | %d total | %d ready", desired, sts.Status.Replicas, sts.Status.ReadyReplicas)},
+			{Name: "Service", Value: sts.Spec.ServiceName},
+			{Name: "Update Strategy", Value: string(sts.Spec.UpdateStrategy.Type)},
+			{Name: "Selector", Value: metav1.FormatLabelSelector(sts.Spec.Selector)},
+		},
+		Containers: describeContainers(sts.Spec.Template.Spec, nil, nil),
+		Volumes:    describeVolumes(sts.Spec.Template.Spec.Volumes),
+	}
+	for _, claim := range sts.Spec.VolumeClaimTemplates {
+		d.Volumes = append(d.Volumes, models.VolumeDescription{
+			Name:   claim.Name,
+			Type:   "VolumeClaimTemplate",
+			Source: formatResourceList(claim.Spec.Resources.Requests),
+		})
+	}
+
+	for _, cond := range sts.Status.Conditions {
+		d.Conditions = append(d.Conditions, models.ResourceCondition{
+			Type:               string(cond.Type),
+			Status:             string(cond.Status),
+			Reason:             cond.Reason,
+			Message:            cond.Message,
+			LastTransitionTime: cond.LastTransitionTime.Time,
+		})
+	}
+	return d
+}
+
+func describeJob(job *batchv1.Job) models.ResourceDescription {
+	completions := int32(1)
+	if job.Spec.Completions != nil {
+		completions = *job.Spec.Completions
+	}
+	parallelism := int32(1)
+	if job.Spec.Parallelism != nil {
+		parallelism = *job.Spec.Parallelism
+	}
+
+	d := models.ResourceDescription{
+		Fields: []models.DescribeField{
+			{Name: "Completions", Value: fmt.Sprintf("%d", completions)},
+			{Name: "Parallelism", Value: fmt.Sprintf("%d", parallelism)},
+			{Name: "Pods Statuses", Value: fmt.Sprintf("%d Active / %d Succeeded / %d Failed", job.Status.Active, job.Status.Succeeded, job.Status.Failed)},
+		},
+		Containers: describeContainers(job.Spec.Template.Spec, nil, nil),
+		Volumes:    describeVolumes(job.Spec.Template.Spec.Volumes),
+	}
+	if job.Status.StartTime != nil {
+		d.Fields = append(d.Fields, models.DescribeField{Name: "Start Time", Value: job.Status.StartTime.UTC().Format("2006-01-02 15:04:05 MST")})
+	}
+	if job.Status.CompletionTime != nil {
+		d.Fields = append(d.Fields, models.DescribeField{Name: "Completed At", Value: job.Status.CompletionTime.UTC().Format("2006-01-02 15:04:05 MST")})
+	}
+
+	for _, cond := range job.Status.Conditions {
+		d.Conditions = append(d.Conditions, models.ResourceCondition{
+			Type:               string(cond.Type),
+			Status:             string(cond.Status),
+			Reason:             cond.Reason,
+			Message:            cond.Message,
+			LastTransitionTime: cond.LastTransitionTime.Time,
+		})
+	}
+	return d
+}
+
+func describeService(svc *corev1.Service) models.ResourceDescription {
+	service := toService(svc)
+
+	ports := make([]string, 0, len(service.Ports))
+	for _, p := range service.Ports {
+		port := fmt.Sprintf("%d/%s → %s", p.Port, p.Protocol, p.TargetPort)
+		if p.NodePort != 0 {
+			port += fmt.Sprintf(" (node port %d)", p.NodePort)
+		}
+		ports = append(ports, port)
+	}
+
+	return models.ResourceDescription{
+		Fields: []models.DescribeField{
+			{Name: "Type", Value: service.Type},
+			{Name: "IP", Value: service.ClusterIP},
+			{Name: "External IP", Value: service.ExternalIP},
+			{Name: "Ports", Value: strings.Join(ports, ", ")},
+			{Name: "Selector", Value: formatSelector(service.Selector)},
+			{Name: "Session Affinity", Value: string(svc.Spec.SessionAffinity)},
+		},
+	}
+}
+
+func describeConfigMap(cm *corev1.ConfigMap) models.ResourceDescription {
+	d := models.ResourceDescription{}
+	for _, key := range sortedKeys(cm.Data) {
+		d.Fields = append(d.Fields, models.DescribeField{Name: key, Value: fmt.Sprintf("%d bytes", len(cm.Data[key]))})
+	}
+	for _, key := range toConfigMap(cm).BinaryKeys {
+		d.Fields = append(d.Fields, models.DescribeField{Name: key, Value: fmt.Sprintf("%d bytes (binary)", len(cm.BinaryData[key]))})
+	}
+	return d
+}
+
+func describeSecret(secret *corev1.Secret) models.ResourceDescription {
+	d := models.ResourceDescription{
+		Fields: []models.DescribeField{{Name: "Type", Value: string(secret.Type)}},
+	}
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	// Like kubectl describe, only sizes are shown
+	for _, key := range keys {
+		d.Fields = append(d.Fields, models.DescribeField{Name: key, Value: fmt.Sprintf("%d bytes", len(secret.Data[key]))})
+	}
+	return d
+}
+
+func describeIngress(ing *networkingv1.Ingress) models.ResourceDescription {
+	ingress := toIngress(ing)
+
+	d := models.ResourceDescription{
+		Fields: []models.DescribeField{
+			{Name: "Class", Value: ingress.Class},
+			{Name: "Address", Value: ingress.Address},
+			{Name: "TLS", Value: fmt.Sprintf("%t", ingress.TLS)},
+		},
+	}
+	for _, rule := range ingress.Rules {
+		host := rule.Host
+		if host == "" {
+			host = "*"
+		}
+		d.Fields = append(d.Fields, models.DescribeField{Name: "Rule", Value: fmt.Sprintf("%s%s → %s:%s", host, rule.Path, rule.Service, rule.Port)})
+	}
+	return d
+}
+
+func describeNamespace(ns *corev1.Namespace) models.ResourceDescription {
+	d := models.ResourceDescription{
+		Fields: []models.DescribeField{{Name: "Status", Value: string(ns.Status.Phase)}},
+	}
+	for _, cond := range ns.Status.Conditions {
+		d.Conditions = append(d.Conditions, models.ResourceCondition{
+			Type:               string(cond.Type),
+			Status:             string(cond.Status),
+			Reason:             cond.Reason,
+			Message:            cond.Message,
+			LastTransitionTime: cond.LastTransitionTime.Time,
+		})
+	}
+	return d
+}
+
+// describeContainers describes the init and regular containers of a pod spec,
+// adding state from the statuses when the pod is running
+func describeContainers(spec corev1.PodSpec, initStatuses, statuses []corev1.ContainerStatus) []models.ContainerDescription {
+	containers := make([]models.ContainerDescription, 0, len(spec.InitContainers)+len(spec.Containers))
+	for _, container := range spec.InitContainers {
+		containers = append(containers, describeContainer(container, true, initStatuses))
+	}
+	for _, container := range spec.Containers {
+		containers = append(containers, describeContainer(container, false, statuses))
+	}
+	return containers
+}
+
+func describeContainer(container corev1.Container, init bool, statuses []corev1.ContainerStatus) models.ContainerDescription {
+	d := models.ContainerDescription{
+		Name:     container.Name,
+		Image:    container.Image,
+		Init:     init,
+		Requests: formatResourceList(container.Resources.Requests),
+		Limits:   formatResourceList(container.Resources.Limits),
+	}
+	for _, port := range container.Ports {
+		d.Ports = append(d.Ports, fmt.Sprintf("%d/%s", port.ContainerPort, port.Protocol))
+	}
+	for _, mount := range container.VolumeMounts {
+		line := mount.MountPath + " from " + mount.Name
+		if mount.ReadOnly {
+			line += " (ro)"
+		}
+		d.Mounts = append(d.Mounts, line)
+	}
+
+	for _, status := range statuses {
+		if status.Name != container.Name {
+			continue
+		}
+		d.Ready = status.Ready
+		d.RestartCount = int(status.RestartCount)
+		switch {
+		case status.State.Running != nil:
+			d.State = "Running"
+		case status.State.Waiting != nil:
+			d.State = "Waiting"
+			d.Reason = status.State.Waiting.Reason
+		case status.State.Terminated != nil:
+			d.State = "Terminated"
+			d.Reason = fmt.Sprintf("%s (exit code %d)", status.State.Terminated.Reason, status.State.Terminated.ExitCode)
+		}
+	}
+	return d
+}
+
+// describeVolumes names the source type and referenced object of each volume
+func describeVolumes(volumes []corev1.Volume) []models.VolumeDescription {
+	described := make([]models.VolumeDescription, 0, len(volumes))
+	for _, vol := range volumes {
+		d := models.VolumeDescription{Name: vol.Name, Type: "Other"}
+		switch {
+		case vol.ConfigMap != nil:
+			d.Type, d.Source = "ConfigMap", vol.ConfigMap.Name
+		case vol.Secret != nil:
+			d.Type, d.Source = "Secret", vol.Secret.SecretName
+		case vol.PersistentVolumeClaim != nil:
+			d.Type, d.Source = "PersistentVolumeClaim", vol.PersistentVolumeClaim.ClaimName
+		case vol.EmptyDir != nil:
+			d.Type = "EmptyDir"
+		case vol.HostPath != nil:
+			d.Type, d.Source = "HostPath", vol.HostPath.Path
+		case vol.Projected != nil:
+			d.Type = "Projected"
+		case vol.DownwardAPI != nil:
+			d.Type = "DownwardAPI"
+		}
+		described = append(described, d)
+	}
+	return described
+}
+
+// formatResourceList renders requests or limits like "cpu=100m, memory=128Mi"
+func formatResourceList(list corev1.ResourceList) string {
+	parts := make([]string, 0, len(list))
+	for name, quantity := range list {
+		parts = append(parts, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+// formatSelector renders a label selector map as "k=v,k2=v2"
+func formatSelector(selector map[string]string) string {
+	pairs := make([]string, 0, len(selector))
+	for _, key := range sortedKeys(selector) {
+		pairs = append(pairs, key+"="+selector[key])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
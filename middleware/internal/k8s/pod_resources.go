@@ -0,0 +1,117 @@
+package k8s
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// podUsage is the summed CPU and memory usage of a pod's containers as reported by metrics-server
+type podUsage struct {
+	cpuMillis   int64
+	memoryBytes int64
+}
+
+// listPodUsage fetches usage for every pod of a namespace, or all namespaces
+// when empty, in a single call. Pods are keyed by namespace/name; a missing
+// metrics client or metrics-server yields an empty map.
+func (c *K8sClient) listPodUsage(namespace string) map[string]podUsage {
+	usage := make(map[string]podUsage)
+	if c.metricsClient == nil {
+		return usage
+	}
+
+	metricsList, err := c.metricsClient.MetricsV1beta1().PodMetricses(namespace).List(c.ctx, metav1.ListOptions{})
+	if err != nil {
+		return usage
+	}
+
+	for _, podMetrics := range metricsList.Items {
+		if len(podMetrics.Containers) == 0 {
+			continue
+		}
+		var total podUsage
+		for _, container := range podMetrics.Containers {
+			total.cpuMillis += container.Usage.Cpu().MilliValue()
+			total.memoryBytes += container.Usage.Memory().Value()
+		}
+		usage[podMetrics.Namespace+"/"+podMetrics.Name] = total
+	}
+	return usage
+}
+
+// podRequests returns the resources the scheduler reserves for a pod
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	return podResources(pod, func(r corev1.ResourceRequirements) corev1.ResourceList { return r.Requests })
+}
+
+// podLimits returns the effective resource limits of a pod
+func podLimits(pod *corev1.Pod) corev1.ResourceList {
+	return podResources(pod, func(r corev1.ResourceRequirements) corev1.ResourceList { return r.Limits })
+}
+
+// podResources totals one kind of container resource the way the scheduler
+// does: app containers and sidecars run together and are summed, while each
+// regular init container runs on its own alongside the sidecars started before
+// it, so the pod needs the larger of the two, plus any pod overhead
+func podResources(pod *corev1.Pod, pick func(corev1.ResourceRequirements) corev1.ResourceList) corev1.ResourceList {
+	total := corev1.ResourceList{}
+	sidecars := corev1.ResourceList{}
+
+	for _, container := range pod.Spec.InitContainers {
+		if container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			addResources(sidecars, pick(container.Resources))
+			continue
+		}
+		running := sidecars.DeepCopy()
+		addResources(running, pick(container.Resources))
+		maxResources(total, running)
+	}
+
+	running := sidecars.DeepCopy()
+	for _, container := range pod.Spec.Containers {
+		addResources(running, pick(container.Resources))
+	}
+	maxResources(total, running)
+
+	addResources(total, pod.Spec.Overhead)
+	return total
+}
+
+func addResources(into, add corev1.ResourceList) {
+	for name, quantity := range add {
+		if current, ok := into[name]; ok {
+			current.Add(quantity)
+			into[name] = current
+		} else {
+			into[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+func maxResources(into, other corev1.ResourceList) {
+	for name, quantity := range other {
+		if current, ok := into[name]; !ok || quantity.Cmp(current) > 0 {
+			into[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+// formatCPU renders a CPU quantity in millicores, or "" when unset
+func formatCPU(resources corev1.ResourceList) string {
+	quantity, ok := resources[corev1.ResourceCPU]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%dm", quantity.MilliValue())
+}
+
+// formatMemory renders a memory quantity in mebibytes, or "" when unset
+func formatMemory(resources corev1.ResourceList) string {
+	quantity, ok := resources[corev1.ResourceMemory]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%dMi", quantity.Value()/(1024*1024))
+}
@@ -0,0 +1,150 @@
+package k8s
+
+import (
+	"fmt"
+	"imperm-middleware/pkg/models"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// podStatus computes the effective status of a pod the way kubectl get pods
+// does: init container progress, then the most relevant container waiting or
+// terminated reason, falling back to the pod phase
+func podStatus(pod *corev1.Pod) string {
+	reason := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		reason = pod.Status.Reason
+	}
+
+	initializing := false
+	for i, status := range pod.Status.InitContainerStatuses {
+		switch {
+		case status.State.Terminated != nil && status.State.Terminated.ExitCode == 0:
+			continue
+		case status.State.Terminated != nil:
+			reason = "Init:" + terminatedReason(status.State.Terminated)
+		case status.State.Waiting != nil && status.State.Waiting.Reason != "" && status.State.Waiting.Reason != "PodInitializing":
+			reason = "Init:" + status.State.Waiting.Reason
+		case isSidecar(pod, status.Name) && status.Started != nil && *status.Started:
+			// Restartable init containers keep running alongside the main containers
+			continue
+		default:
+			reason = fmt.Sprintf("Init:%d/%d", i, len(pod.Spec.InitContainers))
+		}
+		initializing = true
+		break
+	}
+
+	if !initializing {
+		hasRunning := false
+		for i := len(pod.Status.ContainerStatuses) - 1; i >= 0; i-- {
+			status := pod.Status.ContainerStatuses[i]
+			switch {
+			case status.State.Waiting != nil && status.State.Waiting.Reason != "":
+				reason = status.State.Waiting.Reason
+			case status.State.Terminated != nil:
+				reason = terminatedReason(status.State.Terminated)
+			case status.Ready && status.State.Running != nil:
+				hasRunning = true
+			}
+		}
+
+		// A pod with some containers completed and others running is still running
+		if reason == "Completed" && hasRunning {
+			reason = "Running"
+			if !podReady(pod) {
+				reason = "NotReady"
+			}
+		}
+	}
+
+	if pod.DeletionTimestamp != nil {
+		if pod.Status.Reason == "NodeLost" {
+			return "Unknown"
+		}
+		return "Terminating"
+	}
+	return reason
+}
+
+// terminatedReason describes a terminated container, preferring its reason
+func terminatedReason(state *corev1.ContainerStateTerminated) string {
+	switch {
+	case state.Reason != "":
+		return state.Reason
+	case state.Signal != 0:
+		return fmt.Sprintf("Signal:%d", state.Signal)
+	default:
+		return fmt.Sprintf("ExitCode:%d", state.ExitCode)
+	}
+}
+
+// isSidecar reports whether the named init container restarts like a regular container
+func isSidecar(pod *corev1.Pod, name string) bool {
+	for _, container := range pod.Spec.InitContainers {
+		if container.Name == name {
+			return container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways
+		}
+	}
+	return false
+}
+
+// podOwner returns the controlling owner of a pod as Kind/Name
+func podOwner(pod *corev1.Pod) string {
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		return owner.Kind + "/" + owner.Name
+	}
+	return ""
+}
+
+// podContainers reports the state of every init and regular container of a pod
+func podContainers(pod *corev1.Pod) []models.PodContainer {
+	var containers []models.PodContainer
+	for _, container := range pod.Spec.InitContainers {
+		containers = append(containers, podContainer(container, true, pod.Status.InitContainerStatuses))
+	}
+	for _, container := range pod.Spec.Containers {
+		containers = append(containers, podContainer(container, false, pod.Status.ContainerStatuses))
+	}
+	return containers
+}
+
+func podContainer(container corev1.Container, init bool, statuses []corev1.ContainerStatus) models.PodContainer {
+	pc := models.PodContainer{
+		Name:  container.Name,
+		Image: container.Image,
+		Init:  init,
+		State: "Waiting", // Until the kubelet reports a status
+	}
+
+	for _, status := range statuses {
+		if status.Name != container.Name {
+			continue
+		}
+
+		pc.Ready = status.Ready
+		pc.RestartCount = int(status.RestartCount)
+		switch {
+		case status.State.Running != nil:
+			pc.State = "Running"
+		case status.State.Terminated != nil:
+			pc.State = "Terminated"
+			pc.Reason = terminatedReason(status.State.Terminated)
+			pc.Message = status.State.Terminated.Message
+		case status.State.Waiting != nil:
+			pc.State = "Waiting"
+			pc.Reason = status.State.Waiting.Reason
+			pc.Message = status.State.Waiting.Message
+		}
+
+		if last := status.LastTerminationState.Terminated; last != nil {
+			exitCode := int(last.ExitCode)
+			pc.LastReason = terminatedReason(last)
+			pc.LastExitCode = &exitCode
+			pc.LastFinished = last.FinishedAt.Time
+		}
+		break
+	}
+	return pc
+}
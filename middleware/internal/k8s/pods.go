@@ -17,9 +17,15 @@ func (c *K8sClient) ListPods(namespace string) ([]models.Pod, error) {
 		return nil, fmt.Errorf("failed to list pods: %w", err)
 	}
 
+	return c.toPods(podList.Items, c.listPodUsage(namespace)), nil
+}
+
+// toPods converts pods to the model. Usage comes from a single metrics-server
+// listing of the pods' namespace(s) and falls back to the pods' requests.
+func (c *K8sClient) toPods(items []corev1.Pod, usage map[string]podUsage) []models.Pod {
 	var pods []models.Pod
 
-	for _, pod := range podList.Items {
+	for _, pod := range items {
 		// Calculate ready status (e.g., "1/2")
 		totalContainers := len(pod.Status.ContainerStatuses)
 		readyContainers := 0
@@ -34,53 +40,50 @@ func (c *K8sClient) ListPods(namespace string) ([]models.Pod, error) {
 
 		readyStatus := fmt.Sprintf("%d/%d", readyContainers, totalContainers)
 
-		// Get actual resource usage from metrics-server
-		cpu := "N/A"
-		memory := "N/A"
-
-		// Try to get metrics from metrics-server if available
-		if c.metricsClient != nil {
-			podMetrics, err := c.metricsClient.MetricsV1beta1().PodMetricses(pod.Namespace).Get(c.ctx, pod.Name, metav1.GetOptions{})
-			if err == nil && len(podMetrics.Containers) > 0 {
-				// Sum up all container metrics
-				var totalCPU, totalMemory int64
-				for _, container := range podMetrics.Containers {
-					totalCPU += container.Usage.Cpu().MilliValue()
-					totalMemory += container.Usage.Memory().Value()
-				}
-				cpu = fmt.Sprintf("%dm", totalCPU)
-				memory = fmt.Sprintf("%dMi", totalMemory/(1024*1024))
-			} else {
-				// Fall back to resource requests if metrics call failed
-				for _, container := range pod.Spec.Containers {
-					if container.Resources.Requests != nil {
-						if cpuReq := container.Resources.Requests.Cpu(); cpuReq != nil {
-							cpu = cpuReq.String()
-						}
-						if memReq := container.Resources.Requests.Memory(); memReq != nil {
-							memory = memReq.String()
-						}
-						break // Use first container's requests
-					}
-				}
-			}
+		requests := podRequests(&pod)
+		limits := podLimits(&pod)
+
+		// Use actual usage when metrics-server reported the pod, requests otherwise
+		cpu := formatCPU(requests)
+		memory := formatMemory(requests)
+		if u, ok := usage[pod.Namespace+"/"+pod.Name]; ok {
+			cpu = fmt.Sprintf("%dm", u.cpuMillis)
+			memory = fmt.Sprintf("%dMi", u.memoryBytes/(1024*1024))
+		}
+		if cpu == "" {
+			cpu = "N/A"
+		}
+		if memory == "" {
+			memory = "N/A"
 		}
 
 		p := models.Pod{
 			Name:      pod.Name,
 			Namespace: pod.Namespace,
-			Status:    string(pod.Status.Phase),
+			Status:    podStatus(&pod),
+			Phase:     string(pod.Status.Phase),
 			Ready:     readyStatus,
 			Restarts:  int(restarts),
 			Age:       pod.CreationTimestamp.Time,
 			CPU:       cpu,
 			Memory:    memory,
+
+			CPURequest:    formatCPU(requests),
+			CPULimit:      formatCPU(limits),
+			MemoryRequest: formatMemory(requests),
+			MemoryLimit:   formatMemory(limits),
+
+			Node:       pod.Spec.NodeName,
+			PodIP:      pod.Status.PodIP,
+			QOSClass:   string(pod.Status.QOSClass),
+			Owner:      podOwner(&pod),
+			Containers: podContainers(&pod),
 		}
 
 		pods = append(pods, p)
 	}
 
-	return pods, nil
+	return pods
 }
 
 // GetPodLogs retrieves logs for a specific pod
@@ -114,29 +117,8 @@ func (c *K8sClient) GetPodLogs(namespace, podName string) (string, error) {
 }
 
 // GetPodEvents retrieves events for a specific pod
-func (c *K8sClient) GetPodEvents(namespace, podName string) ([]models.Event, error) {
-	// Get events related to the pod
-	fieldSelector := fmt.Sprintf("involvedObject.name=%s,involvedObject.namespace=%s", podName, namespace)
-	eventList, err := c.clientset.CoreV1().Events(namespace).List(c.ctx, metav1.ListOptions{
-		FieldSelector: fieldSelector,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list events: %w", err)
-	}
-
-	var events []models.Event
-	for _, event := range eventList.Items {
-		e := models.Event{
-			Type:      event.Type,
-			Reason:    event.Reason,
-			Message:   event.Message,
-			Timestamp: event.LastTimestamp.Time,
-			Count:     int(event.Count),
-		}
-		events = append(events, e)
-	}
-
-	return events, nil
+func (c *K8sClient) GetPodEvents(namespace, podName string) ([]models.EventTimeEntry, error) {
+	return c.objectEvents(namespace, "Pod", podName)
 }
 
 // DeletePod deletes a pod in the specified namespace
@@ -168,23 +150,19 @@ func (c *K8sClient) GetPodMetrics(namespace string) ([]models.PodMetrics, error)
 	}
 
 	// Create a map of pod limits for easy lookup
-	podLimits := make(map[string]struct {
+	type limits struct {
 		cpuLimit    int64
 		memoryLimit int64
-	})
-
-	for _, pod := range podList.Items {
-		var totalCPULimit, totalMemoryLimit int64
-		for _, container := range pod.Spec.Containers {
-			if limit := container.Resources.Limits; limit != nil {
-				totalCPULimit += limit.Cpu().MilliValue()
-				totalMemoryLimit += limit.Memory().Value()
-			}
+	}
+	podLimitsByName := make(map[string]limits)
+
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		podLimit := podLimits(pod)
+		podLimitsByName[pod.Namespace+"/"+pod.Name] = limits{
+			cpuLimit:    podLimit.Cpu().MilliValue(),
+			memoryLimit: podLimit.Memory().Value(),
 		}
-		podLimits[pod.Name] = struct {
-			cpuLimit    int64
-			memoryLimit int64
-		}{cpuLimit: totalCPULimit, memoryLimit: totalMemoryLimit}
 	}
 
 	// Build metrics response
@@ -195,12 +173,8 @@ func (c *K8sClient) GetPodMetrics(namespace string) ([]models.PodMetrics, error)
 			totalMemory += container.Usage.Memory().Value()
 		}
 
-		limits, ok := podLimits[podMetric.Name]
-		if !ok {
-			// If we can't find limits, use 0
-			limits.cpuLimit = 0
-			limits.memoryLimit = 0
-		}
+		// Pods that are gone by now have no limits
+		limits := podLimitsByName[podMetric.Namespace+"/"+podMetric.Name]
 
 		cpuUsedPercentage := 0.0
 		if limits.cpuLimit > 0 {
@@ -214,12 +188,17 @@ func (c *K8sClient) GetPodMetrics(namespace string) ([]models.PodMetrics, error)
 
 		podMetrics = append(podMetrics, models.PodMetrics{
 			Name:                 podMetric.Name,
+			Namespace:            podMetric.Namespace,
 			CPULimit:             fmt.Sprintf("%dm", limits.cpuLimit),
 			CPUUsed:              fmt.Sprintf("%dm", totalCPU),
 			CPUUsedPercentage:    cpuUsedPercentage,
 			MemoryLimit:          fmt.Sprintf("%dMi", limits.memoryLimit/(1024*1024)),
 			MemoryUsed:           fmt.Sprintf("%dMi", totalMemory/(1024*1024)),
 			MemoryUsedPercentage: memoryUsedPercentage,
+			CPUUsedMillis:        totalCPU,
+			CPULimitMillis:       limits.cpuLimit,
+			MemoryUsedBytes:      totalMemory,
+			MemoryLimitBytes:     limits.memoryLimit,
 		})
 	}
 
@@ -0,0 +1,156 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"imperm-middleware/pkg/models"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+// BenchmarkListPods lists pods against fake clientsets, batched as ListPods
+// does and, as a baseline, with a metrics call per pod. Fakes answer
+// instantly, so the API calls per list, reported as calls/op, stand in for
+// the round trips a real cluster costs: they stay at 2 batched, while the
+// baseline grows with the number of pods.
+func BenchmarkListPods(b *testing.B) {
+	const namespaces = 5
+
+	fetches := []struct {
+		name string
+		list func(c *K8sClient, namespace string) ([]models.Pod, error)
+	}{
+		{"batched", (*K8sClient).ListPods},
+		{"per-pod", listPodsPerPod},
+	}
+
+	for _, fetch := range fetches {
+		for _, podsPerNamespace := range []int{10, 50, 250} {
+			for _, namespace := range []string{"ns-0", ""} {
+				scope := namespace
+				if scope == "" {
+					scope = "all-namespaces"
+				}
+				name := fmt.Sprintf("%s/%s/pods=%d", fetch.name, scope, podsPerNamespace)
+				b.Run(name, func(b *testing.B) {
+					clientset, metricsClient := newFakeClientsets(namespaces, podsPerNamespace)
+					client := &K8sClient{
+						clientset:     clientset,
+						metricsClient: metricsClient,
+						forwards:      newPortForwards(),
+						ctx:           context.Background(),
+					}
+
+					b.ResetTimer()
+					for i := 0; i < b.N; i++ {
+						if _, err := fetch.list(client, namespace); err != nil {
+							b.Fatal(err)
+						}
+					}
+					b.StopTimer()
+
+					calls := len(clientset.Actions()) + len(metricsClient.Actions())
+					b.ReportMetric(float64(calls)/float64(b.N), "calls/op")
+				})
+			}
+		}
+	}
+}
+
+// listPodsPerPod lists pods the way ListPods did before it batched metrics:
+// one metrics-server Get per pod, then the same conversion
+func listPodsPerPod(c *K8sClient, namespace string) ([]models.Pod, error) {
+	podList, err := c.clientset.CoreV1().Pods(namespace).List(c.ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]podUsage)
+	for _, pod := range podList.Items {
+		podMetrics, err := c.metricsClient.MetricsV1beta1().PodMetricses(pod.Namespace).Get(c.ctx, pod.Name, metav1.GetOptions{})
+		if err != nil || len(podMetrics.Containers) == 0 {
+			continue
+		}
+		var total podUsage
+		for _, container := range podMetrics.Containers {
+			total.cpuMillis += container.Usage.Cpu().MilliValue()
+			total.memoryBytes += container.Usage.Memory().Value()
+		}
+		usage[pod.Namespace+"/"+pod.Name] = total
+	}
+	return c.toPods(podList.Items, usage), nil
+}
+
+// newFakeClientsets builds pods with an init container, a sidecar and an app
+// container, and usage for each of them
+func newFakeClientsets(namespaces, podsPerNamespace int) (*fake.Clientset, *metricsfake.Clientset) {
+	always := corev1.ContainerRestartPolicyAlways
+	resources := func(cpu, memory string) corev1.ResourceRequirements {
+		list := corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}
+		return corev1.ResourceRequirements{Requests: list, Limits: list}
+	}
+
+	var pods []runtime.Object
+	var usage []metricsv1beta1.PodMetrics
+	for n := 0; n < namespaces; n++ {
+		for p := 0; p < podsPerNamespace; p++ {
+			meta := metav1.ObjectMeta{Name: fmt.Sprintf("pod-%d", p), Namespace: fmt.Sprintf("ns-%d", n)}
+			pods = append(pods, &corev1.Pod{
+				ObjectMeta: meta,
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{
+						{Name: "migrate", Resources: resources("500m", "256Mi")},
+						{Name: "proxy", RestartPolicy: &always, Resources: resources("50m", "32Mi")},
+					},
+					Containers: []corev1.Container{
+						{Name: "app", Resources: resources("200m", "128Mi")},
+					},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning},
+			})
+			usage = append(usage, metricsv1beta1.PodMetrics{
+				ObjectMeta: meta,
+				Containers: []metricsv1beta1.ContainerMetrics{
+					{Name: "proxy", Usage: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("5m"), corev1.ResourceMemory: resource.MustParse("20Mi")}},
+					{Name: "app", Usage: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("120m"), corev1.ResourceMemory: resource.MustParse("90Mi")}},
+				},
+			})
+		}
+	}
+
+	clientset := fake.NewSimpleClientset(pods...)
+	metricsClient := metricsfake.NewSimpleClientset()
+
+	// The fake metrics tracker files PodMetrics under the wrong resource, so serve them directly
+	metricsClient.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		list := &metricsv1beta1.PodMetricsList{}
+		for _, podMetrics := range usage {
+			if ns := action.GetNamespace(); ns == "" || ns == podMetrics.Namespace {
+				list.Items = append(list.Items, podMetrics)
+			}
+		}
+		return true, list, nil
+	})
+	metricsClient.PrependReactor("get", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		get := action.(k8stesting.GetAction)
+		for i := range usage {
+			if usage[i].Namespace == get.GetNamespace() && usage[i].Name == get.GetName() {
+				return true, &usage[i], nil
+			}
+		}
+		return false, nil, nil
+	})
+
+	return clientset, metricsClient
+}
@@ -0,0 +1,325 @@
+package k8s
+
+import (
+	"fmt"
+	"imperm-middleware/pkg/models"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// portForwardReadyTimeout bounds how long a new session may take to connect
+const portForwardReadyTimeout = 30 * time.Second
+
+// endedPortForwardTTL is how long a failed or closed session is still listed,
+// so clients get to see why it ended
+const endedPortForwardTTL = 2 * time.Minute
+
+// portForwardSession is a running client-go port forwarder listening on loopback
+type portForwardSession struct {
+	info    models.PortForward
+	address string // Loopback address the forwarder listens on
+	stop    chan struct{}
+	ended   time.Time // When the forwarder stopped, zero while active
+}
+
+// portForwards tracks the sessions opened through this client
+type portForwards struct {
+	mu       sync.Mutex
+	sessions map[string]*portForwardSession
+	nextID   int
+}
+
+func newPortForwards() *portForwards {
+	return &portForwards{sessions: make(map[string]*portForwardSession)}
+}
+
+// pruneLocked forgets the sessions that ended longer than endedPortForwardTTL ago
+func (f *portForwards) pruneLocked(now time.Time) {
+	for id, session := range f.sessions {
+		if !session.ended.IsZero() && now.Sub(session.ended) > endedPortForwardTTL {
+			delete(f.sessions, id)
+		}
+	}
+}
+
+// StartPortForward opens a port-forward session to a pod, or to a pod backing a service
+func (c *K8sClient) StartPortForward(req models.PortForwardRequest) (*models.PortForward, error) {
+	if req.Namespace == "" || req.Name == "" {
+		return nil, fmt.Errorf("namespace and name are required")
+	}
+
+	var pod *corev1.Pod
+	var targetPort int
+	var err error
+
+	switch req.Kind {
+	case models.PortForwardKindPod, "":
+		req.Kind = models.PortForwardKindPod
+		if req.Port <= 0 {
+			return nil, fmt.Errorf("port is required when forwarding to a pod")
+		}
+		pod, err = c.clientset.CoreV1().Pods(req.Namespace).Get(c.ctx, req.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod: %w", err)
+		}
+		if pod.Status.Phase != corev1.PodRunning {
+			return nil, fmt.Errorf("pod %s is %s, not Running", pod.Name, pod.Status.Phase)
+		}
+		targetPort = req.Port
+	case models.PortForwardKindService:
+		pod, targetPort, err = c.resolveServicePort(req.Namespace, req.Name, &req.Port)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported port-forward kind %q", req.Kind)
+	}
+
+	forwardReq := c.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward")
+
+	dialer, err := c.newPortForwardDialer(forwardReq.URL())
+	if err != nil {
+		return nil, err
+	}
+
+	stop := make(chan struct{})
+	ready := make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"}, []string{fmt.Sprintf("0:%d", targetPort)}, stop, ready, io.Discard, io.Discard)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create port forwarder: %w", err)
+	}
+
+	c.forwards.mu.Lock()
+	c.forwards.pruneLocked(time.Now())
+	c.forwards.nextID++
+	id := fmt.Sprintf("pf-%d", c.forwards.nextID)
+	c.forwards.mu.Unlock()
+
+	session := &portForwardSession{
+		info: models.PortForward{
+			ID:         id,
+			Namespace:  req.Namespace,
+			Kind:       req.Kind,
+			Name:       req.Name,
+			Pod:        pod.Name,
+			Port:       req.Port,
+			TargetPort: targetPort,
+			Status:     models.PortForwardActive,
+			Started:    time.Now(),
+		},
+		stop: stop,
+	}
+
+	// A session that ends, failed or closed, is listed for a while before it is forgotten
+	failed := make(chan error, 1)
+	go func() {
+		err := forwarder.ForwardPorts()
+		c.forwards.mu.Lock()
+		defer c.forwards.mu.Unlock()
+		if err != nil {
+			session.info.Status = models.PortForwardFailed
+			session.info.Error = err.Error()
+		} else {
+			session.info.Status = models.PortForwardClosed
+		}
+		session.ended = time.Now()
+		failed <- err
+	}()
+
+	select {
+	case <-ready:
+	case err := <-failed:
+		if err == nil {
+			err = fmt.Errorf("forwarder stopped before it was ready")
+		}
+		return nil, fmt.Errorf("port forward failed: %w", err)
+	case <-time.After(portForwardReadyTimeout):
+		close(stop)
+		return nil, fmt.Errorf("timed out waiting for port forward to %s", pod.Name)
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil || len(ports) == 0 {
+		close(stop)
+		return nil, fmt.Errorf("failed to get forwarded port: %v", err)
+	}
+	session.address = net.JoinHostPort("127.0.0.1", strconv.Itoa(int(ports[0].Local)))
+
+	c.forwards.mu.Lock()
+	defer c.forwards.mu.Unlock()
+	// The forwarder may have ended since it was ready
+	if session.info.Status != models.PortForwardActive {
+		return nil, fmt.Errorf("port forward to %s ended: %s", pod.Name, session.info.Status)
+	}
+	c.forwards.sessions[id] = session
+	info := session.info
+	return &info, nil
+}
+
+// ListPortForwards lists the port-forward sessions held open by this client and the ones that ended recently
+func (c *K8sClient) ListPortForwards() ([]models.PortForward, error) {
+	c.forwards.mu.Lock()
+	defer c.forwards.mu.Unlock()
+	c.forwards.pruneLocked(time.Now())
+
+	forwards := []models.PortForward{}
+	for _, session := range c.forwards.sessions {
+		forwards = append(forwards, session.info)
+	}
+	sort.Slice(forwards, func(i, j int) bool {
+		return forwards[i].Started.Before(forwards[j].Started)
+	})
+	return forwards, nil
+}
+
+// StopPortForward closes a port-forward session and forgets it
+func (c *K8sClient) StopPortForward(id string) error {
+	c.forwards.mu.Lock()
+	session, ok := c.forwards.sessions[id]
+	if ok {
+		delete(c.forwards.sessions, id)
+		if session.info.Status == models.PortForwardActive {
+			close(session.stop)
+		}
+	}
+	c.forwards.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("port forward %s not found", id)
+	}
+	return nil
+}
+
+// DialPortForward opens a connection through an active port-forward session
+func (c *K8sClient) DialPortForward(id string) (io.ReadWriteCloser, error) {
+	c.forwards.mu.Lock()
+	session, ok := c.forwards.sessions[id]
+	var address, status string
+	if ok {
+		address, status = session.address, session.info.Status
+	}
+	c.forwards.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("port forward %s not found", id)
+	}
+	if status != models.PortForwardActive {
+		return nil, fmt.Errorf("port forward %s is %s", id, status)
+	}
+	return net.Dial("tcp", address)
+}
+
+// resolveServicePort picks a ready pod behind a service and the container port
+// that the requested service port maps to. A port of 0 selects the first
+// service port and is updated in place.
+func (c *K8sClient) resolveServicePort(namespace, serviceName string, port *int) (*corev1.Pod, int, error) {
+	svc, err := c.clientset.CoreV1().Services(namespace).Get(c.ctx, serviceName, metav1.GetOptions{})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get service: %w", err)
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return nil, 0, fmt.Errorf("service %s has no selector", serviceName)
+	}
+	if len(svc.Spec.Ports) == 0 {
+		return nil, 0, fmt.Errorf("service %s exposes no ports", serviceName)
+	}
+
+	var servicePort *corev1.ServicePort
+	for i := range svc.Spec.Ports {
+		if *port == 0 || int(svc.Spec.Ports[i].Port) == *port {
+			servicePort = &svc.Spec.Ports[i]
+			break
+		}
+	}
+	if servicePort == nil {
+		return nil, 0, fmt.Errorf("service %s has no port %d", serviceName, *port)
+	}
+	*port = int(servicePort.Port)
+
+	podList, err := c.clientset.CoreV1().Pods(namespace).List(c.ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list pods for service: %w", err)
+	}
+
+	var pod *corev1.Pod
+	for i := range podList.Items {
+		candidate := &podList.Items[i]
+		if candidate.Status.Phase != corev1.PodRunning || candidate.DeletionTimestamp != nil {
+			continue
+		}
+		if pod == nil || (podReady(candidate) && !podReady(pod)) {
+			pod = candidate
+		}
+	}
+	if pod == nil {
+		return nil, 0, fmt.Errorf("no running pods behind service %s", serviceName)
+	}
+
+	// Named target ports are resolved against the chosen pod's containers
+	target := servicePort.TargetPort
+	if target.IntValue() > 0 {
+		return pod, target.IntValue(), nil
+	}
+	if target.StrVal == "" {
+		return pod, int(servicePort.Port), nil
+	}
+	for _, container := range pod.Spec.Containers {
+		for _, containerPort := range container.Ports {
+			if containerPort.Name == target.StrVal {
+				return pod, int(containerPort.ContainerPort), nil
+			}
+		}
+	}
+	return nil, 0, fmt.Errorf("pod %s has no port named %q", pod.Name, target.StrVal)
+}
+
+// newPortForwardDialer dials over WebSocket, falling back to SPDY like kubectl does
+func (c *K8sClient) newPortForwardDialer(forwardURL *url.URL) (httpstream.Dialer, error) {
+	if c.config == nil {
+		return nil, fmt.Errorf("kubernetes config not available")
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(c.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spdy transport: %w", err)
+	}
+	spdyDialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", forwardURL)
+
+	tunnelingDialer, err := portforward.NewSPDYOverWebsocketDialer(forwardURL, c.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create websocket dialer: %w", err)
+	}
+
+	return portforward.NewFallbackDialer(tunnelingDialer, spdyDialer, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	}), nil
+}
+
+// podReady reports whether the pod's Ready condition is true
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
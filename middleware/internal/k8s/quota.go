@@ -0,0 +1,108 @@
+package k8s
+
+import (
+	"fmt"
+	"imperm-middleware/internal/capacity"
+	"imperm-middleware/pkg/models"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Names of the guardrail objects created in every environment that asks for them
+const (
+	quotaName      = "imperm-quota"
+	limitRangeName = "imperm-limits"
+)
+
+// createGuardrails creates the ResourceQuota and LimitRange the options ask for
+func (c *K8sClient) createGuardrails(namespace string, options *models.DeploymentOptions) error {
+	hard, err := capacity.QuotaHard(options.Quota())
+	if err != nil {
+		return err
+	}
+	if len(hard) > 0 {
+		quota := &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   quotaName,
+				Labels: map[string]string{"managed-by": "imperm"},
+			},
+			Spec: corev1.ResourceQuotaSpec{Hard: hard},
+		}
+		if _, err := c.clientset.CoreV1().ResourceQuotas(namespace).Create(c.ctx, quota, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create resource quota: %w", err)
+		}
+	}
+
+	limits := options.LimitRange()
+	if limits.IsEmpty() {
+		return nil
+	}
+	item := corev1.LimitRangeItem{
+		Type:           corev1.LimitTypeContainer,
+		Default:        corev1.ResourceList{},
+		DefaultRequest: corev1.ResourceList{},
+	}
+	for _, field := range []struct {
+		list  corev1.ResourceList
+		name  corev1.ResourceName
+		value string
+	}{
+		{item.DefaultRequest, corev1.ResourceCPU, limits.DefaultCPURequest},
+		{item.DefaultRequest, corev1.ResourceMemory, limits.DefaultMemoryRequest},
+		{item.Default, corev1.ResourceCPU, limits.DefaultCPU},
+		{item.Default, corev1.ResourceMemory, limits.DefaultMemory},
+	} {
+		if field.value == "" {
+			continue
+		}
+		q, err := resource.ParseQuantity(field.value)
+		if err != nil {
+			return fmt.Errorf("invalid limit range default for %s: %q", field.name, field.value)
+		}
+		field.list[field.name] = q
+	}
+
+	limitRange := &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   limitRangeName,
+			Labels: map[string]string{"managed-by": "imperm"},
+		},
+		Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{item}},
+	}
+	if _, err := c.clientset.CoreV1().LimitRanges(namespace).Create(c.ctx, limitRange, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create limit range: %w", err)
+	}
+	return nil
+}
+
+// quotaUsage reports usage against every resource quota of a namespace;
+// where several quotas limit the same resource the tightest one is shown
+func quotaUsage(quotas []corev1.ResourceQuota) []models.QuotaUsage {
+	byResource := make(map[corev1.ResourceName]models.QuotaUsage)
+	for _, quota := range quotas {
+		for name, hard := range quota.Status.Hard {
+			used := quota.Status.Used[name]
+			usage := models.QuotaUsage{
+				Resource: string(name),
+				Used:     used.String(),
+				Hard:     hard.String(),
+			}
+			if hard.MilliValue() > 0 {
+				usage.Percentage = float64(used.MilliValue()) / float64(hard.MilliValue()) * 100
+			}
+			if current, ok := byResource[name]; !ok || usage.Percentage > current.Percentage {
+				byResource[name] = usage
+			}
+		}
+	}
+
+	usages := make([]models.QuotaUsage, 0, len(byResource))
+	for _, usage := range byResource {
+		usages = append(usages, usage)
+	}
+	sort.Slice(usages, func(i, j int) bool { return usages[i].Resource < usages[j].Resource })
+	return usages
+}
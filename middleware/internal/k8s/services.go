@@ -0,0 +1,164 @@
+package k8s
+
+import (
+	"fmt"
+	"imperm-middleware/pkg/models"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListServices lists all services in a namespace (or all namespaces if namespace is empty)
+func (c *K8sClient) ListServices(namespace string) ([]models.Service, error) {
+	serviceList, err := c.clientset.CoreV1().Services(namespace).List(c.ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+
+	services := make([]models.Service, 0, len(serviceList.Items))
+	for i := range serviceList.Items {
+		services = append(services, toService(&serviceList.Items[i]))
+	}
+	return services, nil
+}
+
+// GetService gets a single service
+func (c *K8sClient) GetService(namespace, name string) (*models.Service, error) {
+	svc, err := c.clientset.CoreV1().Services(namespace).Get(c.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %w", err)
+	}
+	service := toService(svc)
+	return &service, nil
+}
+
+// ListIngresses lists all ingresses in a namespace (or all namespaces if namespace is empty)
+func (c *K8sClient) ListIngresses(namespace string) ([]models.Ingress, error) {
+	ingressList, err := c.clientset.NetworkingV1().Ingresses(namespace).List(c.ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingresses: %w", err)
+	}
+
+	ingresses := make([]models.Ingress, 0, len(ingressList.Items))
+	for i := range ingressList.Items {
+		ingresses = append(ingresses, toIngress(&ingressList.Items[i]))
+	}
+	return ingresses, nil
+}
+
+// GetIngress gets a single ingress
+func (c *K8sClient) GetIngress(namespace, name string) (*models.Ingress, error) {
+	ing, err := c.clientset.NetworkingV1().Ingresses(namespace).Get(c.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ingress: %w", err)
+	}
+	ingress := toIngress(ing)
+	return &ingress, nil
+}
+
+// toService converts a Kubernetes service to the API model
+func toService(svc *corev1.Service) models.Service {
+	service := models.Service{
+		Name:      svc.Name,
+		Namespace: svc.Namespace,
+		Type:      string(svc.Spec.Type),
+		ClusterIP: svc.Spec.ClusterIP,
+		Selector:  svc.Spec.Selector,
+		Age:       svc.CreationTimestamp.Time,
+	}
+
+	// External address: load balancer ingress first, then explicit external IPs
+	var external []string
+	for _, lb := range svc.Status.LoadBalancer.Ingress {
+		if lb.IP != "" {
+			external = append(external, lb.IP)
+		} else if lb.Hostname != "" {
+			external = append(external, lb.Hostname)
+		}
+	}
+	external = append(external, svc.Spec.ExternalIPs...)
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		external = append(external, svc.Spec.ExternalName)
+	}
+	service.ExternalIP = strings.Join(external, ",")
+
+	for _, port := range svc.Spec.Ports {
+		service.Ports = append(service.Ports, models.ServicePort{
+			Name:       port.Name,
+			Protocol:   string(port.Protocol),
+			Port:       int(port.Port),
+			TargetPort: port.TargetPort.String(),
+			NodePort:   int(port.NodePort),
+		})
+	}
+
+	return service
+}
+
+// toIngress converts a Kubernetes ingress to the API model
+func toIngress(ing *networkingv1.Ingress) models.Ingress {
+	ingress := models.Ingress{
+		Name:      ing.Name,
+		Namespace: ing.Namespace,
+		TLS:       len(ing.Spec.TLS) > 0,
+		Age:       ing.CreationTimestamp.Time,
+	}
+	if ing.Spec.IngressClassName != nil {
+		ingress.Class = *ing.Spec.IngressClassName
+	}
+
+	var addresses []string
+	for _, lb := range ing.Status.LoadBalancer.Ingress {
+		if lb.IP != "" {
+			addresses = append(addresses, lb.IP)
+		} else if lb.Hostname != "" {
+			addresses = append(addresses, lb.Hostname)
+		}
+	}
+	ingress.Address = strings.Join(addresses, ",")
+
+	hosts := make(map[string]bool)
+	if backend := ing.Spec.DefaultBackend; backend != nil && backend.Service != nil {
+		ingress.Rules = append(ingress.Rules, models.IngressRule{
+			Host:    "*",
+			Path:    "/",
+			Service: backend.Service.Name,
+			Port:    ingressPort(backend.Service.Port),
+		})
+	}
+	for _, rule := range ing.Spec.Rules {
+		host := rule.Host
+		if host == "" {
+			host = "*"
+		}
+		hosts[host] = true
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			r := models.IngressRule{Host: host, Path: path.Path}
+			if path.Backend.Service != nil {
+				r.Service = path.Backend.Service.Name
+				r.Port = ingressPort(path.Backend.Service.Port)
+			}
+			ingress.Rules = append(ingress.Rules, r)
+		}
+	}
+	for host := range hosts {
+		ingress.Hosts = append(ingress.Hosts, host)
+	}
+	sort.Strings(ingress.Hosts)
+
+	return ingress
+}
+
+// ingressPort formats an ingress backend port, which is either a number or a name
+func ingressPort(port networkingv1.ServiceBackendPort) string {
+	if port.Name != "" {
+		return port.Name
+	}
+	return fmt.Sprintf("%d", port.Number)
+}
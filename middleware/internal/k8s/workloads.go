@@ -0,0 +1,128 @@
+package k8s
+
+import (
+	"fmt"
+	"imperm-middleware/pkg/models"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListJobs lists all jobs in a namespace (or all namespaces if namespace is empty)
+func (c *K8sClient) ListJobs(namespace string) ([]models.Job, error) {
+	jobList, err := c.clientset.BatchV1().Jobs(namespace).List(c.ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	jobs := make([]models.Job, 0, len(jobList.Items))
+	for i := range jobList.Items {
+		jobs = append(jobs, toJob(&jobList.Items[i]))
+	}
+	return jobs, nil
+}
+
+// GetJob gets a single job
+func (c *K8sClient) GetJob(namespace, name string) (*models.Job, error) {
+	j, err := c.clientset.BatchV1().Jobs(namespace).Get(c.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	job := toJob(j)
+	return &job, nil
+}
+
+// ListStatefulSets lists all stateful sets in a namespace (or all namespaces if namespace is empty)
+func (c *K8sClient) ListStatefulSets(namespace string) ([]models.StatefulSet, error) {
+	stsList, err := c.clientset.AppsV1().StatefulSets(namespace).List(c.ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stateful sets: %w", err)
+	}
+
+	statefulSets := make([]models.StatefulSet, 0, len(stsList.Items))
+	for i := range stsList.Items {
+		statefulSets = append(statefulSets, toStatefulSet(&stsList.Items[i]))
+	}
+	return statefulSets, nil
+}
+
+// GetStatefulSet gets a single stateful set
+func (c *K8sClient) GetStatefulSet(namespace, name string) (*models.StatefulSet, error) {
+	sts, err := c.clientset.AppsV1().StatefulSets(namespace).Get(c.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stateful set: %w", err)
+	}
+	statefulSet := toStatefulSet(sts)
+	return &statefulSet, nil
+}
+
+// toJob converts a Kubernetes job to the API model
+func toJob(j *batchv1.Job) models.Job {
+	completions := int32(1)
+	if j.Spec.Completions != nil {
+		completions = *j.Spec.Completions
+	}
+
+	job := models.Job{
+		Name:        j.Name,
+		Namespace:   j.Namespace,
+		Status:      "Running",
+		Completions: fmt.Sprintf("%d/%d", j.Status.Succeeded, completions),
+		Active:      int(j.Status.Active),
+		Succeeded:   int(j.Status.Succeeded),
+		Failed:      int(j.Status.Failed),
+		Age:         j.CreationTimestamp.Time,
+	}
+	if j.Status.StartTime != nil {
+		start := j.Status.StartTime.Time
+		job.StartTime = &start
+	}
+	if j.Status.CompletionTime != nil {
+		end := j.Status.CompletionTime.Time
+		job.EndTime = &end
+	}
+
+	if j.Spec.Suspend != nil && *j.Spec.Suspend {
+		job.Status = "Suspended"
+	}
+	for _, condition := range j.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			job.Status = "Complete"
+		case batchv1.JobFailed:
+			job.Status = "Failed"
+			if job.EndTime == nil {
+				end := condition.LastTransitionTime.Time
+				job.EndTime = &end
+			}
+		}
+	}
+
+	return job
+}
+
+// toStatefulSet converts a Kubernetes stateful set to the API model
+func toStatefulSet(sts *appsv1.StatefulSet) models.StatefulSet {
+	replicas := 1
+	if sts.Spec.Replicas != nil {
+		replicas = int(*sts.Spec.Replicas)
+	}
+
+	return models.StatefulSet{
+		Name:            sts.Name,
+		Namespace:       sts.Namespace,
+		Ready:           fmt.Sprintf("%d/%d", sts.Status.ReadyReplicas, replicas),
+		Replicas:        replicas,
+		ReadyReplicas:   int(sts.Status.ReadyReplicas),
+		UpdatedReplicas: int(sts.Status.UpdatedReplicas),
+		ServiceName:     sts.Spec.ServiceName,
+		CurrentRevision: sts.Status.CurrentRevision,
+		UpdateRevision:  sts.Status.UpdateRevision,
+		Age:             sts.CreationTimestamp.Time,
+	}
+}
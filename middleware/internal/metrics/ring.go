@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"imperm-middleware/pkg/models"
+	"time"
+)
+
+// ring is a fixed-size buffer that overwrites its oldest sample once full
+type ring struct {
+	samples []models.MetricsSample
+	next    int  // Index the next sample is written to
+	full    bool // Whether the buffer has wrapped around
+}
+
+func newRing(capacity int) *ring {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &ring{samples: make([]models.MetricsSample, capacity)}
+}
+
+func (r *ring) add(sample models.MetricsSample) {
+	r.samples[r.next] = sample
+	r.next = (r.next + 1) % len(r.samples)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// list returns a copy of the samples, oldest first
+func (r *ring) list() []models.MetricsSample {
+	if !r.full {
+		return append([]models.MetricsSample{}, r.samples[:r.next]...)
+	}
+	out := make([]models.MetricsSample, 0, len(r.samples))
+	out = append(out, r.samples[r.next:]...)
+	return append(out, r.samples[:r.next]...)
+}
+
+// latest returns when the newest sample was taken
+func (r *ring) latest() time.Time {
+	if r.next == 0 && !r.full {
+		return time.Time{}
+	}
+	return r.samples[(r.next-1+len(r.samples))%len(r.samples)].Time
+}
@@ -0,0 +1,163 @@
+// Package metrics keeps a short history of pod resource usage by sampling
+// metrics-server on an interval
+package metrics
+
+import (
+	"imperm-middleware/pkg/models"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultInterval is how often usage is sampled unless configured otherwise
+	DefaultInterval = 15 * time.Second
+
+	// DefaultRetention is how much history is kept unless configured otherwise
+	DefaultRetention = 30 * time.Minute
+)
+
+// Source returns the current usage of every pod in a namespace, or all namespaces when empty
+type Source func(namespace string) ([]models.PodMetrics, error)
+
+// Sampler records pod and namespace usage into fixed-size ring buffers
+type Sampler struct {
+	source    Source
+	interval  time.Duration
+	retention time.Duration
+	capacity  int
+
+	mu         sync.RWMutex
+	pods       map[string]*ring // Keyed by namespace/pod
+	namespaces map[string]*ring
+	failing    bool // Whether the last sample failed, to log only the first failure
+}
+
+// NewSampler creates a sampler keeping retention worth of samples taken every interval
+func NewSampler(source Source, interval, retention time.Duration) *Sampler {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	if retention < interval {
+		retention = interval
+	}
+
+	return &Sampler{
+		source:     source,
+		interval:   interval,
+		retention:  retention,
+		capacity:   int(retention / interval),
+		pods:       make(map[string]*ring),
+		namespaces: make(map[string]*ring),
+	}
+}
+
+// Run samples until stop is closed
+func (s *Sampler) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.Sample(time.Now())
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			s.Sample(now)
+		}
+	}
+}
+
+// Sample takes one reading of every pod and adds it to the history
+func (s *Sampler) Sample(now time.Time) {
+	podMetrics, err := s.source("")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		if !s.failing {
+			log.Printf("Warning: metrics sampling failed: %v", err)
+		}
+		s.failing = true
+		return
+	}
+	s.failing = false
+
+	totals := make(map[string]*models.MetricsSample)
+	for _, pm := range podMetrics {
+		sample := models.MetricsSample{
+			Time:             now,
+			CPUUsedMillis:    pm.CPUUsedMillis,
+			CPULimitMillis:   pm.CPULimitMillis,
+			MemoryUsedBytes:  pm.MemoryUsedBytes,
+			MemoryLimitBytes: pm.MemoryLimitBytes,
+		}
+		s.record(s.pods, pm.Namespace+"/"+pm.Name, withPercentages(sample))
+
+		total, ok := totals[pm.Namespace]
+		if !ok {
+			total = &models.MetricsSample{Time: now}
+			totals[pm.Namespace] = total
+		}
+		total.CPUUsedMillis += sample.CPUUsedMillis
+		total.CPULimitMillis += sample.CPULimitMillis
+		total.MemoryUsedBytes += sample.MemoryUsedBytes
+		total.MemoryLimitBytes += sample.MemoryLimitBytes
+	}
+	for namespace, total := range totals {
+		s.record(s.namespaces, namespace, withPercentages(*total))
+	}
+
+	// Forget pods and namespaces that have not been seen for a whole retention period
+	cutoff := now.Add(-s.retention)
+	for _, rings := range []map[string]*ring{s.pods, s.namespaces} {
+		for key, r := range rings {
+			if r.latest().Before(cutoff) {
+				delete(rings, key)
+			}
+		}
+	}
+}
+
+func (s *Sampler) record(rings map[string]*ring, key string, sample models.MetricsSample) {
+	r, ok := rings[key]
+	if !ok {
+		r = newRing(s.capacity)
+		rings[key] = r
+	}
+	r.add(sample)
+}
+
+// History returns the samples of a pod, or of the whole namespace when pod is empty, oldest first
+func (s *Sampler) History(namespace, pod string) *models.MetricsHistory {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r := s.namespaces[namespace]
+	if pod != "" {
+		r = s.pods[namespace+"/"+pod]
+	}
+
+	history := &models.MetricsHistory{
+		Namespace: namespace,
+		Pod:       pod,
+		Interval:  int(s.interval / time.Second),
+		Samples:   []models.MetricsSample{},
+	}
+	if r != nil {
+		history.Samples = r.list()
+	}
+	return history
+}
+
+// withPercentages fills in usage as a share of the limits, where limits are set
+func withPercentages(sample models.MetricsSample) models.MetricsSample {
+	if sample.CPULimitMillis > 0 {
+		sample.CPUUsedPercentage = float64(sample.CPUUsedMillis) / float64(sample.CPULimitMillis) * 100
+	}
+	if sample.MemoryLimitBytes > 0 {
+		sample.MemoryUsedPercentage = float64(sample.MemoryUsedBytes) / float64(sample.MemoryLimitBytes) * 100
+	}
+	return sample
+}
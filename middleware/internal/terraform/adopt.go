@@ -0,0 +1,98 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"imperm-middleware/internal/k8s"
+	"imperm-middleware/pkg/models"
+)
+
+// AdoptEnvironment brings an existing namespace under Terraform management:
+// it labels the namespace, generates a working directory for the options its
+// logger deployments correspond to and imports the namespace and those
+// deployments into the state, so the environment can then be cloned and
+// destroyed like one created through Terraform. Nothing is applied.
+func (c *TerraformClient) AdoptEnvironment(name string) (*models.DeploymentOptions, error) {
+	envDir := filepath.Join(c.baseDir, name)
+	if _, err := os.Stat(envDir); err == nil {
+		return nil, fmt.Errorf("environment %s already has a Terraform working directory", name)
+	}
+	if err := c.k8sClient.CheckAdoptable(name); err != nil {
+		return nil, err
+	}
+
+	options, err := c.k8sClient.DetectOptions(name)
+	if err != nil {
+		return nil, err
+	}
+
+	opLog := c.logs.CreateOperation(name, "adopt")
+
+	opLog.AddLine("Labelling namespace as managed by imperm...")
+	if err := c.k8sClient.LabelManaged(name, options); err != nil {
+		opLog.SetFailed(err)
+		return nil, err
+	}
+
+	// Without a complete state the directory would make destroy skip
+	// resources, so a failed adoption leaves no directory behind and the
+	// namespace unmanaged, as it was
+	fail := func(err error) (*models.DeploymentOptions, error) {
+		opLog.SetFailed(err)
+		if removeErr := RemoveWorkingDir(c.baseDir, name); removeErr != nil {
+			opLog.AddLine(removeErr.Error())
+		}
+		if unlabelErr := c.k8sClient.UnlabelManaged(name); unlabelErr != nil {
+			opLog.AddLine(unlabelErr.Error())
+		}
+		return nil, err
+	}
+
+	opLog.AddLine("Creating working directory...")
+	envDir, err = CreateWorkingDir(c.baseDir, name)
+	if err != nil {
+		return fail(err)
+	}
+	opLog.Persist(envDir)
+
+	opLog.AddLine("Generating Terraform configuration...")
+	if err := c.generateConfig(envDir, name, c.modulePath, options); err != nil {
+		return fail(err)
+	}
+	if err := saveOptions(envDir, options, c.modulePath); err != nil {
+		return fail(err)
+	}
+
+	executor := c.operationExecutor(envDir, opLog)
+
+	opLog.SetPhase("init")
+	if err := executor.Init(); err != nil {
+		return fail(err)
+	}
+	opLog.SetPhase("import")
+	if err := executor.Import("module.environment.kubernetes_namespace.environment", name); err != nil {
+		return fail(err)
+	}
+	for _, variable := range k8s.LoggerVariables {
+		replicas, ok := options.Variables[variable]
+		if !ok {
+			continue
+		}
+		// The module only declares the deployment of a logger with replicas,
+		// so one scaled to 0 has nothing to import into and stays unmanaged
+		if replicas == "0" {
+			opLog.AddLine(fmt.Sprintf("Skipping %s: scaled to 0, it stays outside Terraform", k8s.LoggerDeployment(variable)))
+			continue
+		}
+		address := fmt.Sprintf("module.environment.kubernetes_deployment.%s[0]", variable)
+		if err := executor.Import(address, name+"/"+k8s.LoggerDeployment(variable)); err != nil {
+			return fail(err)
+		}
+	}
+
+	opLog.SetCompleted()
+	opLog.AddLine("Environment adopted successfully!")
+	return options, nil
+}
@@ -0,0 +1,154 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// State backends environments can keep their Terraform state in
+const (
+	BackendLocal      = "local"
+	BackendS3         = "s3"
+	BackendKubernetes = "kubernetes"
+	BackendHTTP       = "http"
+)
+
+// backendCheckKey is the state key the startup check initializes against
+const backendCheckKey = "imperm-backend-check"
+
+// Backend says where environments keep their Terraform state. Each
+// environment gets its own key: Prefix followed by the environment name.
+// Credentials are not part of it; terraform reads them from its usual
+// environment variables, such as AWS_ACCESS_KEY_ID or TF_HTTP_PASSWORD.
+type Backend struct {
+	Type   string // One of the Backend* constants, local when empty
+	Prefix string // Prepended to every key, e.g. to keep clusters apart
+
+	Path string // local: directory of <key>.tfstate files, empty for each working directory's own
+
+	Bucket    string // s3
+	Region    string // s3, us-east-1 when empty
+	Endpoint  string // s3: an S3-compatible endpoint such as MinIO, empty for AWS
+	PathStyle bool   // s3: address buckets as endpoint/bucket, as MinIO needs
+
+	Namespace string // kubernetes: namespace of the state secrets, default when empty
+
+	Address  string // http: base URL, the key is appended as a path
+	Username string // http
+}
+
+// external reports whether state is kept outside the working directories
+func (b Backend) external() bool {
+	return b.Type != "" && b.Type != BackendLocal || b.Path != ""
+}
+
+// block renders the backend block of an environment's terraform block, or
+// nothing when the state stays in the working directory. The kubernetes
+// backend reaches the cluster the same way the provider does.
+func (b Backend) block(name, kubeconfig, context string) (string, error) {
+	key := b.Prefix + name
+
+	var lines []string
+	switch b.Type {
+	case "", BackendLocal:
+		if b.Path == "" {
+			return "", nil
+		}
+		path := filepath.Join(b.Path, key+".tfstate")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return "", fmt.Errorf("failed to create state directory: %w", err)
+		}
+		lines = append(lines, hclAttr("path", path))
+
+	case BackendS3:
+		region := b.Region
+		if region == "" {
+			region = "us-east-1"
+		}
+		lines = append(lines,
+			hclAttr("bucket", b.Bucket),
+			hclAttr("key", key+"/terraform.tfstate"),
+			hclAttr("region", region))
+		if b.Endpoint != "" {
+			// S3-compatible stores know nothing of AWS accounts and regions
+			lines = append(lines,
+				fmt.Sprintf("endpoints = { s3 = %q }", b.Endpoint),
+				"skip_credentials_validation = true",
+				"skip_requesting_account_id = true",
+				"skip_region_validation = true",
+				"skip_metadata_api_check = true")
+		}
+		if b.PathStyle {
+			lines = append(lines, "use_path_style = true")
+		}
+
+	case BackendKubernetes:
+		namespace := b.Namespace
+		if namespace == "" {
+			namespace = "default"
+		}
+		// Secret names can't hold slashes
+		lines = append(lines,
+			hclAttr("secret_suffix", strings.ReplaceAll(key, "/", "-")),
+			hclAttr("namespace", namespace),
+			hclAttr("config_path", kubeconfig))
+		if context != "" {
+			lines = append(lines, hclAttr("config_context", context))
+		}
+
+	case BackendHTTP:
+		address := strings.TrimSuffix(b.Address, "/") + "/" + key
+		lines = append(lines,
+			hclAttr("address", address),
+			hclAttr("lock_address", address),
+			hclAttr("unlock_address", address))
+		if b.Username != "" {
+			lines = append(lines, hclAttr("username", b.Username))
+		}
+
+	default:
+		return "", fmt.Errorf("unknown state backend %q", b.Type)
+	}
+
+	backendType := b.Type
+	if backendType == "" {
+		backendType = BackendLocal
+	}
+	return fmt.Sprintf("\n  backend %q {\n    %s\n  }\n", backendType, strings.Join(lines, "\n    ")), nil
+}
+
+// Check makes sure the backend can be reached by initializing an otherwise
+// empty configuration against it in a scratch directory. Local state in the
+// working directories needs no check.
+func (b Backend) Check(binary, kubeconfig, context string) error {
+	if !b.external() {
+		return nil
+	}
+
+	block, err := b.block(backendCheckKey, kubeconfig, context)
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "imperm-backend-check-")
+	if err != nil {
+		return fmt.Errorf("failed to create backend check directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	mainTf := fmt.Sprintf("terraform {%s}\n", block)
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(mainTf), 0644); err != nil {
+		return fmt.Errorf("failed to write backend check configuration: %w", err)
+	}
+
+	if err := NewExecutor(binary, dir).Init(); err != nil {
+		return fmt.Errorf("%s state backend check failed: %w", b.Type, err)
+	}
+	return nil
+}
+
+func hclAttr(name, value string) string {
+	return fmt.Sprintf("%s = %q", name, value)
+}
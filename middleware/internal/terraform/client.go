@@ -1,28 +1,66 @@
 package terraform
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"imperm-middleware/internal/capacity"
 	"imperm-middleware/internal/k8s"
+	"imperm-middleware/pkg/client"
 	"imperm-middleware/pkg/models"
 )
 
 // TerraformClient implements the client.Client interface using Terraform for provisioning
 // and Kubernetes API for querying
 type TerraformClient struct {
+	binary     string         // terraform executable
 	baseDir    string         // Base directory for terraform environments
 	modulePath string         // Path to the k8s-namespace module
 	kubeconfig string         // Path to kubeconfig file
+	context    string         // Kubeconfig context, empty for the current one
+	backend    Backend        // Where environments keep their state
+	providers  Providers      // How environments' providers are installed
 	k8sClient  *k8s.K8sClient // Embedded K8s client for read operations
+	drift      *DriftChecker  // Latest drift check of each environment
+	logs       *LogStore      // Operations on the cluster's environments
+
+	interrupted *interruptions // Operations cut short by a restart, found on startup
+}
+
+// DefaultBinary is the terraform executable used unless configured otherwise, looked up on the PATH
+const DefaultBinary = "terraform"
+
+// Options says where a Terraform client finds terraform and its module, and
+// where it keeps the environments' working directories
+type Options struct {
+	Binary     string // terraform executable, DefaultBinary when empty
+	BaseDir    string // Parent of the environments' working directories
+	ModulePath string // Module environments are created from unless they name their own template
+	Kubeconfig string // Kubeconfig the kubernetes provider reads
+	Backend    Backend
+	Providers  Providers
+
+	LogRetention time.Duration // How long finished operation logs are kept, DefaultLogRetention when 0
+	LogMaxLines  int           // Lines kept per operation log, every line when 0
 }
 
-// NewClient creates a new Terraform client
-func NewClient(baseDir, modulePath, kubeconfig string) (*TerraformClient, error) {
+// NewClient creates a new Terraform client provisioning into one cluster
+func NewClient(options Options, cluster k8s.ClusterConfig) (*TerraformClient, error) {
+	baseDir := options.BaseDir
+
 	// Validate terraform is installed
-	executor := NewExecutor(baseDir)
+	executor := NewExecutor(options.Binary, baseDir)
 	if err := executor.Validate(); err != nil {
 		return nil, fmt.Errorf("terraform validation failed: %w", err)
 	}
@@ -32,31 +70,89 @@ func NewClient(baseDir, modulePath, kubeconfig string) (*TerraformClient, error)
 		return nil, fmt.Errorf("failed to create base directory: %w", err)
 	}
 
+	// Make sure new environments will be able to keep their state
+	if err := options.Backend.Check(executor.binary, options.Kubeconfig, cluster.Context); err != nil {
+		return nil, err
+	}
+
 	// Create K8s client for read operations
-	k8sClient, err := k8s.NewClient()
+	k8sClient, err := k8s.NewClientForCluster(cluster)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create k8s client: %w", err)
 	}
 
+	retention := options.LogRetention
+	if retention == 0 {
+		retention = DefaultLogRetention
+	}
+	logs := NewLogStore(retention, options.LogMaxLines)
+
 	return &TerraformClient{
+		binary:     executor.binary,
 		baseDir:    baseDir,
-		modulePath: modulePath,
-		kubeconfig: kubeconfig,
+		modulePath: options.ModulePath,
+		kubeconfig: options.Kubeconfig,
+		context:    cluster.Context,
+		backend:    options.Backend,
+		providers:  options.Providers,
 		k8sClient:  k8sClient,
+		drift:      newDriftChecker(executor.binary, baseDir, logs),
+		logs:       logs,
+
+		interrupted: &interruptions{ops: make(map[string]models.InterruptedOperation), claimed: make(map[string]bool)},
 	}, nil
 }
 
+// Logs returns the logs of operations on the cluster's environments
+func (c *TerraformClient) Logs() *LogStore {
+	return c.logs
+}
+
+// newExecutor creates an executor for an environment's working directory
+func (c *TerraformClient) newExecutor(envDir string) *Executor {
+	executor := NewExecutor(c.binary, envDir)
+	executor.SetProviders(c.providers)
+	return executor
+}
+
+// operationExecutor creates an executor for an environment's working
+// directory whose output goes to an operation's log
+func (c *TerraformClient) operationExecutor(envDir string, opLog *OperationLog) *Executor {
+	executor := c.newExecutor(envDir)
+	executor.SetLogCallback(opLog.AddLine)
+	executor.SetEventCallback(opLog.AddEvent)
+	return executor
+}
+
 // ListEnvironments lists all environments using Kubernetes API
-func (c *TerraformClient) ListEnvironments() ([]models.Environment, error) {
+func (c *TerraformClient) ListEnvironments(filter models.EnvironmentFilter) ([]models.Environment, error) {
 	// Delegate to K8s client for listing
-	return c.k8sClient.ListEnvironments()
+	envs, err := c.k8sClient.ListEnvironments(filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range envs {
+		if status, ok := c.drift.Result(envs[i].Name); ok {
+			envs[i].Drift = &status
+		}
+	}
+	return c.withInterrupted(envs), nil
 }
 
 // CreateEnvironment creates a new environment using Terraform
 func (c *TerraformClient) CreateEnvironment(name string, options *models.DeploymentOptions) error {
+	return c.createEnvironment(name, options, c.modulePath)
+}
+
+// createEnvironment creates a new environment from a module the server chose
+func (c *TerraformClient) createEnvironment(name string, options *models.DeploymentOptions, module string) error {
+	// Refuse replicas that could never be scheduled within the environment's own quota
+	if err := capacity.CheckQuota(options); err != nil {
+		return err
+	}
+
 	// Create operation log
-	logStore := GetLogStore()
-	opLog := logStore.CreateOperation(name, "create")
+	opLog := c.logs.CreateOperation(name, "create")
 
 	// Create working directory
 	opLog.AddLine("Creating working directory...")
@@ -65,26 +161,37 @@ func (c *TerraformClient) CreateEnvironment(name string, options *models.Deploym
 		opLog.SetFailed(err)
 		return err
 	}
+	opLog.Persist(envDir)
+
+	// Record the options with the module actually used, so clones get the same module
+	stored := &models.DeploymentOptions{Name: name}
+	if options != nil {
+		stored = options.Clone(name, nil)
+	}
 
 	// Generate Terraform configuration
+	opLog.SetPhase("generate")
 	opLog.AddLine("Generating Terraform configuration...")
-	if err := c.generateConfig(envDir, name, options); err != nil {
+	if err := c.generateConfig(envDir, name, module, stored); err != nil {
+		opLog.SetFailed(err)
+		return err
+	}
+	if err := saveOptions(envDir, stored, module); err != nil {
 		opLog.SetFailed(err)
 		return err
 	}
 
 	// Initialize Terraform
-	executor := NewExecutor(envDir)
-	executor.SetLogCallback(func(line string) {
-		opLog.AddLine(line)
-	})
+	executor := c.operationExecutor(envDir, opLog)
 
+	opLog.SetPhase("init")
 	if err := executor.Init(); err != nil {
 		opLog.SetFailed(err)
 		return err
 	}
 
 	// Apply Terraform configuration
+	opLog.SetPhase("apply")
 	if err := executor.Apply(); err != nil {
 		opLog.SetFailed(err)
 		return err
@@ -98,8 +205,7 @@ func (c *TerraformClient) CreateEnvironment(name string, options *models.Deploym
 // DestroyEnvironment destroys an environment using Terraform or Kubernetes
 func (c *TerraformClient) DestroyEnvironment(name string) error {
 	// Create operation log
-	logStore := GetLogStore()
-	opLog := logStore.CreateOperation(name, "destroy")
+	opLog := c.logs.CreateOperation(name, "destroy")
 
 	envDir := filepath.Join(c.baseDir, name)
 
@@ -115,22 +221,15 @@ func (c *TerraformClient) DestroyEnvironment(name string) error {
 		opLog.SetCompleted()
 		opLog.AddLine("Environment destroyed successfully via Kubernetes API!")
 
-		// Clean up log after a short delay
-		go func() {
-			time.Sleep(30 * time.Second)
-			logStore.DeleteOperation(name)
-		}()
-
 		return nil
 	}
 
 	// Terraform directory exists - use Terraform destroy
 	opLog.AddLine("Found Terraform directory, using Terraform destroy...")
-	executor := NewExecutor(envDir)
-	executor.SetLogCallback(func(line string) {
-		opLog.AddLine(line)
-	})
+	opLog.Persist(envDir)
+	executor := c.operationExecutor(envDir, opLog)
 
+	opLog.SetPhase("destroy")
 	if err := executor.Destroy(); err != nil {
 		opLog.SetFailed(err)
 		return err
@@ -138,23 +237,65 @@ func (c *TerraformClient) DestroyEnvironment(name string) error {
 
 	// Remove working directory
 	opLog.AddLine("Cleaning up working directory...")
+	opLog.StopPersisting()
 	if err := RemoveWorkingDir(c.baseDir, name); err != nil {
 		opLog.SetFailed(err)
 		return err
 	}
+	c.drift.Forget(name)
+	c.interrupted.forget(name)
 
 	opLog.SetCompleted()
 	opLog.AddLine("Environment destroyed successfully!")
 
-	// Clean up log after a short delay
-	go func() {
-		time.Sleep(30 * time.Second)
-		logStore.DeleteOperation(name)
-	}()
-
 	return nil
 }
 
+// CloneEnvironment creates a new environment from the options and module
+// stored in an existing environment's working directory
+func (c *TerraformClient) CloneEnvironment(sourceName string, req models.CloneEnvironmentRequest) (*models.DeploymentOptions, error) {
+	envDir := filepath.Join(c.baseDir, sourceName)
+	if _, err := os.Stat(envDir); os.IsNotExist(err) {
+		return nil, fmt.Errorf("environment %s has no Terraform working directory", sourceName)
+	}
+
+	stored, module, err := loadOptions(envDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load options of environment %s: %w", sourceName, err)
+	}
+	if module == "" {
+		module = c.modulePath
+	}
+
+	options := stored.Clone(req.Name, req.Variables)
+	c.logs.RecordEvent(req.Name, models.TimelineEvent{
+		Time:    time.Now(),
+		Type:    "Normal",
+		Reason:  "Cloned",
+		Message: fmt.Sprintf("cloned from %s", sourceName),
+	})
+	if err := c.createEnvironment(req.Name, options, module); err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+// GetEnvironmentEvents merges the namespace's Kubernetes events with the
+// Terraform operations run on the environment, newest first
+func (c *TerraformClient) GetEnvironmentEvents(envName string, filter models.EventFilter) ([]models.TimelineEvent, error) {
+	events, err := c.k8sClient.ListNamespaceEvents(envName)
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, c.logs.Events(envName)...)
+	return models.FilterTimeline(events, filter), nil
+}
+
+// ApplyManifest applies user-provided YAML into an environment using Kubernetes API
+func (c *TerraformClient) ApplyManifest(envName string, req models.ApplyManifestRequest) (*models.ApplyResult, error) {
+	return c.k8sClient.ApplyManifest(envName, req)
+}
+
 // ListPods lists pods in a namespace using Kubernetes API
 func (c *TerraformClient) ListPods(namespace string) ([]models.Pod, error) {
 	return c.k8sClient.ListPods(namespace)
@@ -166,7 +307,7 @@ func (c *TerraformClient) GetPodLogs(namespace, podName string) (string, error)
 }
 
 // GetPodEvents gets events for a pod using Kubernetes API
-func (c *TerraformClient) GetPodEvents(namespace, podName string) ([]models.Event, error) {
+func (c *TerraformClient) GetPodEvents(namespace, podName string) ([]models.EventTimeEntry, error) {
 	return c.k8sClient.GetPodEvents(namespace, podName)
 }
 
@@ -175,13 +316,103 @@ func (c *TerraformClient) DeletePod(namespace, podName string) error {
 	return c.k8sClient.DeletePod(namespace, podName)
 }
 
+// ExecPod runs a command in a pod container using Kubernetes API
+func (c *TerraformClient) ExecPod(ctx context.Context, req models.ExecRequest, streams client.ExecStreams) error {
+	return c.k8sClient.ExecPod(ctx, req, streams)
+}
+
+// ListServices lists services in a namespace using Kubernetes API
+func (c *TerraformClient) ListServices(namespace string) ([]models.Service, error) {
+	return c.k8sClient.ListServices(namespace)
+}
+
+// GetService gets a service using Kubernetes API
+func (c *TerraformClient) GetService(namespace, name string) (*models.Service, error) {
+	return c.k8sClient.GetService(namespace, name)
+}
+
+// ListConfigMaps lists config maps in a namespace using Kubernetes API
+func (c *TerraformClient) ListConfigMaps(namespace string) ([]models.ConfigMap, error) {
+	return c.k8sClient.ListConfigMaps(namespace)
+}
+
+// GetConfigMap gets a config map using Kubernetes API
+func (c *TerraformClient) GetConfigMap(namespace, name string) (*models.ConfigMap, error) {
+	return c.k8sClient.GetConfigMap(namespace, name)
+}
+
+// ListSecrets lists secrets in a namespace with values masked using Kubernetes API
+func (c *TerraformClient) ListSecrets(namespace string) ([]models.Secret, error) {
+	return c.k8sClient.ListSecrets(namespace)
+}
+
+// GetSecret gets a secret, revealing values only when asked using Kubernetes API
+func (c *TerraformClient) GetSecret(namespace, name string, reveal bool) (*models.Secret, error) {
+	return c.k8sClient.GetSecret(namespace, name, reveal)
+}
+
+// ListIngresses lists ingresses in a namespace using Kubernetes API
+func (c *TerraformClient) ListIngresses(namespace string) ([]models.Ingress, error) {
+	return c.k8sClient.ListIngresses(namespace)
+}
+
+// GetIngress gets an ingress using Kubernetes API
+func (c *TerraformClient) GetIngress(namespace, name string) (*models.Ingress, error) {
+	return c.k8sClient.GetIngress(namespace, name)
+}
+
+// ListJobs lists jobs in a namespace using Kubernetes API
+func (c *TerraformClient) ListJobs(namespace string) ([]models.Job, error) {
+	return c.k8sClient.ListJobs(namespace)
+}
+
+// GetJob gets a job using Kubernetes API
+func (c *TerraformClient) GetJob(namespace, name string) (*models.Job, error) {
+	return c.k8sClient.GetJob(namespace, name)
+}
+
+// ListStatefulSets lists stateful sets in a namespace using Kubernetes API
+func (c *TerraformClient) ListStatefulSets(namespace string) ([]models.StatefulSet, error) {
+	return c.k8sClient.ListStatefulSets(namespace)
+}
+
+// GetStatefulSet gets a stateful set using Kubernetes API
+func (c *TerraformClient) GetStatefulSet(namespace, name string) (*models.StatefulSet, error) {
+	return c.k8sClient.GetStatefulSet(namespace, name)
+}
+
+// GetResourceManifest gets the live manifest of a resource using Kubernetes API
+func (c *TerraformClient) GetResourceManifest(kind, namespace, name string, reveal bool) (*models.ResourceManifest, error) {
+	return c.k8sClient.GetResourceManifest(kind, namespace, name, reveal)
+}
+
+// StartPortForward opens a port-forward session using Kubernetes API
+func (c *TerraformClient) StartPortForward(req models.PortForwardRequest) (*models.PortForward, error) {
+	return c.k8sClient.StartPortForward(req)
+}
+
+// ListPortForwards lists open port-forward sessions
+func (c *TerraformClient) ListPortForwards() ([]models.PortForward, error) {
+	return c.k8sClient.ListPortForwards()
+}
+
+// StopPortForward closes a port-forward session
+func (c *TerraformClient) StopPortForward(id string) error {
+	return c.k8sClient.StopPortForward(id)
+}
+
+// DialPortForward opens a connection through a port-forward session
+func (c *TerraformClient) DialPortForward(id string) (io.ReadWriteCloser, error) {
+	return c.k8sClient.DialPortForward(id)
+}
+
 // ListDeployments lists deployments in a namespace using Kubernetes API
 func (c *TerraformClient) ListDeployments(namespace string) ([]models.Deployment, error) {
 	return c.k8sClient.ListDeployments(namespace)
 }
 
 // GetDeploymentEvents gets events for a deployment using Kubernetes API
-func (c *TerraformClient) GetDeploymentEvents(namespace, deploymentName string) ([]models.Event, error) {
+func (c *TerraformClient) GetDeploymentEvents(namespace, deploymentName string) ([]models.EventTimeEntry, error) {
 	return c.k8sClient.GetDeploymentEvents(namespace, deploymentName)
 }
 
@@ -190,11 +421,46 @@ func (c *TerraformClient) DeleteDeployment(namespace, deploymentName string) err
 	return c.k8sClient.DeleteDeployment(namespace, deploymentName)
 }
 
+// ScaleDeployment scales a deployment using Kubernetes API
+func (c *TerraformClient) ScaleDeployment(namespace, deploymentName string, replicas int) error {
+	return c.k8sClient.ScaleDeployment(namespace, deploymentName, replicas)
+}
+
+// RestartDeployment restarts a deployment using Kubernetes API
+func (c *TerraformClient) RestartDeployment(namespace, deploymentName string) error {
+	return c.k8sClient.RestartDeployment(namespace, deploymentName)
+}
+
+// PauseDeployment pauses a deployment rollout using Kubernetes API
+func (c *TerraformClient) PauseDeployment(namespace, deploymentName string) error {
+	return c.k8sClient.PauseDeployment(namespace, deploymentName)
+}
+
+// ResumeDeployment resumes a deployment rollout using Kubernetes API
+func (c *TerraformClient) ResumeDeployment(namespace, deploymentName string) error {
+	return c.k8sClient.ResumeDeployment(namespace, deploymentName)
+}
+
+// GetDeploymentHistory gets rollout history for a deployment using Kubernetes API
+func (c *TerraformClient) GetDeploymentHistory(namespace, deploymentName string) ([]models.DeploymentRevision, error) {
+	return c.k8sClient.GetDeploymentHistory(namespace, deploymentName)
+}
+
+// RollbackDeployment rolls back a deployment using Kubernetes API
+func (c *TerraformClient) RollbackDeployment(namespace, deploymentName string, revision int64) error {
+	return c.k8sClient.RollbackDeployment(namespace, deploymentName, revision)
+}
+
 // GetPodMetrics gets pod metrics using Kubernetes API
 func (c *TerraformClient) GetPodMetrics(namespace string) ([]models.PodMetrics, error) {
 	return c.k8sClient.GetPodMetrics(namespace)
 }
 
+// GetClusterCapacity gets node capacity using Kubernetes API
+func (c *TerraformClient) GetClusterCapacity() (*models.ClusterCapacity, error) {
+	return c.k8sClient.GetClusterCapacity()
+}
+
 // GetResourceStats gets resource statistics using Kubernetes API
 func (c *TerraformClient) GetResourceStats(resourceType, namespace string) (*models.ResourceStats, error) {
 	return c.k8sClient.GetResourceStats(resourceType, namespace)
@@ -206,7 +472,27 @@ func (c *TerraformClient) GetEnvironmentHistory() ([]models.EnvironmentHistory,
 }
 
 // generateConfig generates Terraform configuration files for an environment
-func (c *TerraformClient) generateConfig(envDir, name string, options *models.DeploymentOptions) error {
+// from a module the server chose
+func (c *TerraformClient) generateConfig(envDir, name, module string, options *models.DeploymentOptions) error {
+	backend, err := c.backend.block(name, c.kubeconfig, c.context)
+	if err != nil {
+		return err
+	}
+
+	// The module labels the namespace so discovery finds it managed
+	managedLabels, err := c.k8sClient.ManagedLabels()
+	if err != nil {
+		return err
+	}
+	namespaceLabels := make(map[string]interface{}, len(managedLabels))
+	for key, value := range managedLabels {
+		namespaceLabels[key] = value
+	}
+	renderedLabels, err := hclLiteral(namespaceLabels)
+	if err != nil {
+		return err
+	}
+
 	// Start with the base configuration
 	mainTf := fmt.Sprintf(`terraform {
   required_providers {
@@ -215,26 +501,38 @@ func (c *TerraformClient) generateConfig(envDir, name string, options *models.De
       version = "~> 2.20"
     }
   }
-}
+%s}
 
 provider "kubernetes" {
-  config_path = "%s"
+  config_path = %q%s
 }
 
 module "environment" {
-  source = "%s"
+  source = %q
 
-  namespace_name = "%s"
-`, c.kubeconfig, c.modulePath, name)
+  namespace_name   = %q
+  namespace_labels = %s
+`, backend, c.kubeconfig, providerContext(c.context), module, name, renderedLabels)
 
 	// Add all variables from options
 	if options != nil && len(options.Variables) > 0 {
 		for key, value := range options.Variables {
-			// Skip the name and namespace_name variables as namespace_name is already set above
-			if key == "name" || key == "namespace_name" {
+			// Skip the variables already set above
+			if key == "name" || key == "namespace_name" || key == "namespace_labels" {
 				continue
 			}
-			mainTf += fmt.Sprintf("  %s = %s\n", key, value)
+			// Keys and values come from requests, so neither may break out of the module block
+			if !hclIdentifier.MatchString(key) {
+				return fmt.Errorf("invalid variable name %q", key)
+			}
+			if strings.ContainsAny(value, "\r\n") {
+				return fmt.Errorf("value of variable %s spans several lines", key)
+			}
+			rendered, err := hclValue(value)
+			if err != nil {
+				return fmt.Errorf("invalid value of variable %s: %w", key, err)
+			}
+			mainTf += fmt.Sprintf("  %s = %s\n", key, rendered)
 		}
 	}
 
@@ -253,3 +551,97 @@ output "namespace_name" {
 
 	return nil
 }
+
+// hclIdentifier matches the names a module variable can have
+var hclIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// providerContext pins the kubernetes provider to a kubeconfig context, if one is set
+func providerContext(context string) string {
+	if context == "" {
+		return ""
+	}
+	return fmt.Sprintf("\n  config_context = %q", context)
+}
+
+// hclNumber matches the numbers a variable value passes through as, in JSON syntax
+var hclNumber = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// hclValue renders a variable value for main.tf. Values come from requests,
+// so nothing is emitted as an expression terraform would evaluate: numbers
+// and booleans pass through, JSON strings, lists and maps are converted to
+// their HCL literals, and anything else, such as "4Gi" or "docker.io", is
+// quoted as a string.
+func hclValue(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if hclNumber.MatchString(value) || value == "true" || value == "false" {
+		return value, nil
+	}
+	if strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "[") || strings.HasPrefix(value, "{") {
+		decoder := json.NewDecoder(strings.NewReader(value))
+		decoder.UseNumber()
+		var literal interface{}
+		if err := decoder.Decode(&literal); err != nil || decoder.More() {
+			return "", fmt.Errorf("value %s is not a JSON string, list or map", value)
+		}
+		return hclLiteral(literal)
+	}
+	return hclString(value)
+}
+
+// hclLiteral renders a decoded JSON value as an HCL literal
+func hclLiteral(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "null", nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case json.Number:
+		return v.String(), nil
+	case string:
+		return hclString(v)
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			rendered, err := hclLiteral(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, rendered)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		items := make([]string, 0, len(keys))
+		for _, key := range keys {
+			renderedKey, err := hclString(key)
+			if err != nil {
+				return "", err
+			}
+			rendered, err := hclLiteral(v[key])
+			if err != nil {
+				return "", err
+			}
+			items = append(items, renderedKey+" = "+rendered)
+		}
+		return "{" + strings.Join(items, ", ") + "}", nil
+	}
+	return "", fmt.Errorf("unsupported value %v", value)
+}
+
+// hclString quotes a string for HCL. Quoted strings are templates there, so
+// interpolation and directive sequences are escaped to stay literal.
+func hclString(value string) (string, error) {
+	for _, r := range value {
+		if r == utf8.RuneError || unicode.IsControl(r) {
+			return "", fmt.Errorf("value %q contains control or invalid characters", value)
+		}
+	}
+	quoted := strconv.Quote(value)
+	quoted = strings.ReplaceAll(quoted, "${", "$${")
+	quoted = strings.ReplaceAll(quoted, "%{", "%%{")
+	return quoted, nil
+}
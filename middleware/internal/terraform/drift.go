@@ -0,0 +1,228 @@
+package terraform
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"imperm-middleware/pkg/models"
+)
+
+// Drift checking used unless configured otherwise
+const (
+	DefaultDriftInterval    = 10 * time.Minute
+	DefaultDriftConcurrency = 2 // Plans run at once, each holding a provider process
+)
+
+// driftResource matches the resources a refresh-only plan reports, e.g.
+// "  # module.environment.kubernetes_deployment.fast_logger[0] has changed"
+var driftResource = regexp.MustCompile(`#\s+(\S+)\s+has\s+(?:been\s+)?(?:changed|deleted)`)
+
+// DriftChecker periodically compares the Terraform state of every environment
+// working directory with the cluster and keeps the latest result of each
+type DriftChecker struct {
+	binary  string
+	baseDir string
+	logs    *LogStore // Operations running on the environments
+
+	mu       sync.RWMutex
+	results  map[string]models.DriftStatus
+	checking map[string]bool // Environments with a check in flight
+}
+
+func newDriftChecker(binary, baseDir string, logs *LogStore) *DriftChecker {
+	return &DriftChecker{
+		binary:   binary,
+		baseDir:  baseDir,
+		logs:     logs,
+		results:  make(map[string]models.DriftStatus),
+		checking: make(map[string]bool),
+	}
+}
+
+// Run checks every environment each interval, at most concurrency at once, until stop is closed
+func (d *DriftChecker) Run(interval time.Duration, concurrency int, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	d.CheckAll(concurrency, stop)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			d.CheckAll(concurrency, stop)
+		}
+	}
+}
+
+// CheckAll checks every environment working directory, at most concurrency at
+// once, and forgets the results of environments that no longer have one. No
+// more checks are started once stop is closed.
+func (d *DriftChecker) CheckAll(concurrency int, stop <-chan struct{}) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	entries, err := os.ReadDir(d.baseDir)
+	if err != nil {
+		log.Printf("Warning: drift check failed to list environments: %v", err)
+		return
+	}
+	present := make(map[string]bool, len(entries))
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			present[entry.Name()] = true
+			names = append(names, entry.Name())
+		}
+	}
+
+	d.mu.Lock()
+	for name := range d.results {
+		if !present[name] {
+			delete(d.results, name)
+		}
+	}
+	d.mu.Unlock()
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, name := range names {
+		// Environments mid-operation would only report the operation's own changes
+		if op := d.logs.GetOperation(name); op != nil && op.GetStatus() == StatusRunning {
+			continue
+		}
+
+		select {
+		case <-stop:
+			wg.Wait()
+			return
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			d.Check(name)
+		}(name)
+	}
+	wg.Wait()
+}
+
+// Check runs a refresh-only plan for one environment and records the result.
+// A check already in flight for the environment is not started twice.
+func (d *DriftChecker) Check(name string) models.DriftStatus {
+	d.mu.Lock()
+	if d.checking[name] {
+		status := d.results[name]
+		d.mu.Unlock()
+		return status
+	}
+	d.checking[name] = true
+	d.mu.Unlock()
+
+	status := models.DriftStatus{Environment: name, Resources: []string{}}
+	drifted, output, err := NewExecutor(d.binary, filepath.Join(d.baseDir, name)).PlanRefreshOnly()
+	status.CheckedAt = time.Now()
+	if err != nil {
+		status.Error = err.Error()
+	} else {
+		status.Drifted = drifted
+		status.Resources = driftedResources(output)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.checking, name)
+	d.results[name] = status
+	return status
+}
+
+// Result returns the latest drift check of an environment, if there was one
+func (d *DriftChecker) Result(name string) (models.DriftStatus, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	status, ok := d.results[name]
+	return status, ok
+}
+
+// Results returns the latest drift check of every environment, by name
+func (d *DriftChecker) Results() []models.DriftStatus {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	results := make([]models.DriftStatus, 0, len(d.results))
+	for _, status := range d.results {
+		results = append(results, status)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Environment < results[j].Environment })
+	return results
+}
+
+// Forget drops the result of an environment, e.g. once it is destroyed
+func (d *DriftChecker) Forget(name string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.results, name)
+}
+
+// driftedResources lists the resource addresses a refresh-only plan reports as changed
+func driftedResources(output string) []string {
+	resources := []string{}
+	for _, match := range driftResource.FindAllStringSubmatch(output, -1) {
+		resources = append(resources, match[1])
+	}
+	return resources
+}
+
+// StartDriftChecks checks every environment for drift in the background each
+// interval, running at most concurrency plans at once, until stop is closed
+func (c *TerraformClient) StartDriftChecks(interval time.Duration, concurrency int, stop <-chan struct{}) {
+	go c.drift.Run(interval, concurrency, stop)
+}
+
+// DriftResults returns the latest drift check of every environment
+func (c *TerraformClient) DriftResults() ([]models.DriftStatus, error) {
+	return c.drift.Results(), nil
+}
+
+// CheckDrift checks one environment for drift right away
+func (c *TerraformClient) CheckDrift(name string) (*models.DriftStatus, error) {
+	if _, err := os.Stat(filepath.Join(c.baseDir, name)); os.IsNotExist(err) {
+		return nil, fmt.Errorf("environment %s has no Terraform working directory", name)
+	}
+	status := c.drift.Check(name)
+	return &status, nil
+}
+
+// ReconcileEnvironment re-applies an environment's configuration, undoing
+// changes made outside Terraform, and checks it for drift again
+func (c *TerraformClient) ReconcileEnvironment(name string) error {
+	envDir := filepath.Join(c.baseDir, name)
+	if _, err := os.Stat(envDir); os.IsNotExist(err) {
+		return fmt.Errorf("environment %s has no Terraform working directory", name)
+	}
+	if op := c.logs.GetOperation(name); op != nil && op.GetStatus() == StatusRunning {
+		return fmt.Errorf("environment %s already has a %s running", name, op.Operation)
+	}
+
+	opLog := c.logs.CreateOperation(name, "reconcile")
+	opLog.Persist(envDir)
+	executor := c.operationExecutor(envDir, opLog)
+
+	opLog.SetPhase("apply")
+	if err := executor.Apply(); err != nil {
+		opLog.SetFailed(err)
+		return err
+	}
+
+	opLog.SetCompleted()
+	opLog.AddLine("Environment reconciled successfully!")
+	c.drift.Check(name)
+	return nil
+}
@@ -3,31 +3,51 @@ package terraform
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"sync"
+
+	"imperm-middleware/pkg/models"
 )
 
 // LogCallback is a function that receives log lines from terraform execution
 type LogCallback func(line string)
 
+// EventCallback is a function that receives the structured events of terraform -json output
+type EventCallback func(event models.OperationEvent)
+
 // Executor handles running Terraform commands
 type Executor struct {
-	workingDir  string
-	logCallback LogCallback
-	logMutex    sync.Mutex
+	binary        string    // terraform executable
+	providers     Providers // How init installs providers
+	workingDir    string
+	logCallback   LogCallback
+	eventCallback EventCallback
+	logMutex      sync.Mutex
 }
 
-// NewExecutor creates a new Terraform executor
-func NewExecutor(workingDir string) *Executor {
+// NewExecutor creates a new Terraform executor running binary, or terraform
+// from the PATH when empty
+func NewExecutor(binary, workingDir string) *Executor {
+	if binary == "" {
+		binary = DefaultBinary
+	}
 	return &Executor{
+		binary:     binary,
 		workingDir: workingDir,
 	}
 }
 
+// SetProviders sets how init installs providers
+func (e *Executor) SetProviders(providers Providers) {
+	e.providers = providers
+}
+
 // SetLogCallback sets a callback function to receive log lines
 func (e *Executor) SetLogCallback(callback LogCallback) {
 	e.logMutex.Lock()
@@ -46,6 +66,24 @@ func (e *Executor) log(line string) {
 	}
 }
 
+// SetEventCallback sets a callback function to receive structured events
+func (e *Executor) SetEventCallback(callback EventCallback) {
+	e.logMutex.Lock()
+	defer e.logMutex.Unlock()
+	e.eventCallback = callback
+}
+
+// event sends a structured event to the callback if set
+func (e *Executor) event(event models.OperationEvent) {
+	e.logMutex.Lock()
+	callback := e.eventCallback
+	e.logMutex.Unlock()
+
+	if callback != nil {
+		callback(event)
+	}
+}
+
 // streamOutput reads from a reader and sends lines to both a buffer and the log callback
 func (e *Executor) streamOutput(reader io.Reader, buffer *bytes.Buffer) error {
 	scanner := bufio.NewScanner(reader)
@@ -58,12 +96,44 @@ func (e *Executor) streamOutput(reader io.Reader, buffer *bytes.Buffer) error {
 	return scanner.Err()
 }
 
+// streamJSON reads terraform -json output, logging the text of each message
+// and sending the structured events; error diagnostics also go to diagnostics
+func (e *Executor) streamJSON(reader io.Reader, buffer, diagnostics *bytes.Buffer) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		text, event := parseJSONLine(scanner.Text())
+		buffer.WriteString(text)
+		buffer.WriteString("\n")
+		for _, line := range strings.Split(text, "\n") {
+			e.log(line)
+		}
+		if event == nil {
+			continue
+		}
+		if event.Diagnostic != nil && event.Diagnostic.Severity == "error" {
+			diagnostics.WriteString(text)
+			diagnostics.WriteString("\n")
+		}
+		e.event(*event)
+	}
+	return scanner.Err()
+}
+
 // Init initializes Terraform in the working directory
 func (e *Executor) Init() error {
 	e.log("=== Initializing Terraform ===")
 
-	cmd := exec.Command("terraform", "init")
+	unlock := e.providers.lock()
+	defer unlock()
+
+	if err := e.providers.prepare(e.workingDir); err != nil {
+		return err
+	}
+
+	cmd := exec.Command(e.binary, e.providers.initArgs()...)
 	cmd.Dir = e.workingDir
+	cmd.Env = e.providers.env()
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -98,13 +168,17 @@ func (e *Executor) Init() error {
 		return fmt.Errorf("terraform init failed: %w\n%s", err, stderrBuf.String())
 	}
 
+	if err := e.providers.saveLockFile(e.workingDir); err != nil {
+		e.log(fmt.Sprintf("Warning: %v", err))
+	}
+
 	e.log("=== Terraform initialization complete ===")
 	return nil
 }
 
 // Plan runs terraform plan
 func (e *Executor) Plan() (string, error) {
-	cmd := exec.Command("terraform", "plan", "-no-color")
+	cmd := exec.Command(e.binary, "plan", "-no-color")
 	cmd.Dir = e.workingDir
 
 	var stdout, stderr bytes.Buffer
@@ -118,11 +192,35 @@ func (e *Executor) Plan() (string, error) {
 	return stdout.String(), nil
 }
 
+// PlanRefreshOnly compares the state with the real objects without changing
+// either, reporting whether they differ along with the plan output. It takes
+// no state lock so it never holds up an operation on the environment.
+func (e *Executor) PlanRefreshOnly() (bool, string, error) {
+	cmd := exec.Command(e.binary, "plan", "-refresh-only", "-detailed-exitcode", "-no-color", "-input=false", "-lock=false")
+	cmd.Dir = e.workingDir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 2 {
+		// -detailed-exitcode: 2 means the plan has changes
+		return true, stdout.String(), nil
+	}
+	if err != nil {
+		return false, "", fmt.Errorf("terraform plan failed: %w\n%s", err, stderr.String())
+	}
+
+	return false, stdout.String(), nil
+}
+
 // Apply runs terraform apply
 func (e *Executor) Apply() error {
 	e.log("=== Applying Terraform configuration ===")
 
-	cmd := exec.Command("terraform", "apply", "-auto-approve", "-no-color")
+	cmd := exec.Command(e.binary, "apply", "-auto-approve", "-json")
 	cmd.Dir = e.workingDir
 
 	stdout, err := cmd.StdoutPipe()
@@ -139,13 +237,15 @@ func (e *Executor) Apply() error {
 		return fmt.Errorf("failed to start terraform apply: %w", err)
 	}
 
-	var stdoutBuf, stderrBuf bytes.Buffer
+	// Diagnostics come on stdout with -json, so errors are collected from
+	// there too, into a buffer of their own as stderr is read concurrently
+	var stdoutBuf, stderrBuf, diagnosticsBuf bytes.Buffer
 	var wg sync.WaitGroup
 
 	wg.Add(2)
 	go func() {
 		defer wg.Done()
-		e.streamOutput(stdout, &stdoutBuf)
+		e.streamJSON(stdout, &stdoutBuf, &diagnosticsBuf)
 	}()
 	go func() {
 		defer wg.Done()
@@ -155,7 +255,7 @@ func (e *Executor) Apply() error {
 	wg.Wait()
 
 	if err := cmd.Wait(); err != nil {
-		return fmt.Errorf("terraform apply failed: %w\n%s", err, stderrBuf.String())
+		return fmt.Errorf("terraform apply failed: %w\n%s%s", err, diagnosticsBuf.String(), stderrBuf.String())
 	}
 
 	e.log("=== Terraform apply complete ===")
@@ -166,7 +266,7 @@ func (e *Executor) Apply() error {
 func (e *Executor) Destroy() error {
 	e.log("=== Destroying Terraform resources ===")
 
-	cmd := exec.Command("terraform", "destroy", "-auto-approve", "-no-color")
+	cmd := exec.Command(e.binary, "destroy", "-auto-approve", "-json")
 	cmd.Dir = e.workingDir
 
 	stdout, err := cmd.StdoutPipe()
@@ -183,6 +283,52 @@ func (e *Executor) Destroy() error {
 		return fmt.Errorf("failed to start terraform destroy: %w", err)
 	}
 
+	// Diagnostics come on stdout with -json, so errors are collected from
+	// there too, into a buffer of their own as stderr is read concurrently
+	var stdoutBuf, stderrBuf, diagnosticsBuf bytes.Buffer
+	var wg sync.WaitGroup
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		e.streamJSON(stdout, &stdoutBuf, &diagnosticsBuf)
+	}()
+	go func() {
+		defer wg.Done()
+		e.streamOutput(stderr, &stderrBuf)
+	}()
+
+	wg.Wait()
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("terraform destroy failed: %w\n%s%s", err, diagnosticsBuf.String(), stderrBuf.String())
+	}
+
+	e.log("=== Terraform destroy complete ===")
+	return nil
+}
+
+// Import brings an existing object into the Terraform state under address
+func (e *Executor) Import(address, id string) error {
+	e.log(fmt.Sprintf("=== Importing %s ===", address))
+
+	cmd := exec.Command(e.binary, "import", "-no-color", address, id)
+	cmd.Dir = e.workingDir
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stderr pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start terraform import: %w", err)
+	}
+
 	var stdoutBuf, stderrBuf bytes.Buffer
 	var wg sync.WaitGroup
 
@@ -199,16 +345,29 @@ func (e *Executor) Destroy() error {
 	wg.Wait()
 
 	if err := cmd.Wait(); err != nil {
-		return fmt.Errorf("terraform destroy failed: %w\n%s", err, stderrBuf.String())
+		return fmt.Errorf("terraform import of %s failed: %w\n%s", address, err, stderrBuf.String())
 	}
 
-	e.log("=== Terraform destroy complete ===")
+	return nil
+}
+
+// ForceUnlock releases a state lock left behind by a terraform that was stopped
+func (e *Executor) ForceUnlock(lockID string) error {
+	cmd := exec.Command(e.binary, "force-unlock", "-force", lockID)
+	cmd.Dir = e.workingDir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("terraform force-unlock failed: %w\n%s", err, stderr.String())
+	}
 	return nil
 }
 
 // Output retrieves terraform output values
 func (e *Executor) Output(name string) (string, error) {
-	cmd := exec.Command("terraform", "output", "-raw", name)
+	cmd := exec.Command(e.binary, "output", "-raw", name)
 	cmd.Dir = e.workingDir
 
 	var stdout, stderr bytes.Buffer
@@ -224,7 +383,7 @@ func (e *Executor) Output(name string) (string, error) {
 
 // Show runs terraform show in JSON format
 func (e *Executor) Show() (string, error) {
-	cmd := exec.Command("terraform", "show", "-json")
+	cmd := exec.Command(e.binary, "show", "-json")
 	cmd.Dir = e.workingDir
 
 	var stdout, stderr bytes.Buffer
@@ -240,7 +399,7 @@ func (e *Executor) Show() (string, error) {
 
 // Validate checks if Terraform is installed and available
 func (e *Executor) Validate() error {
-	cmd := exec.Command("terraform", "version")
+	cmd := exec.Command(e.binary, "version")
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("terraform not found: %w", err)
 	}
@@ -0,0 +1,202 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"imperm-middleware/pkg/models"
+)
+
+// jsonResource identifies a resource in terraform's machine-readable UI
+type jsonResource struct {
+	Addr string `json:"addr"`
+}
+
+// jsonPosition is a position in a configuration file
+type jsonPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// jsonMessage is one line of terraform's machine-readable UI (-json); only
+// the fields imperm uses are decoded
+type jsonMessage struct {
+	Message   string `json:"@message"`
+	Timestamp string `json:"@timestamp"`
+	Type      string `json:"type"`
+
+	// apply_start, apply_complete and apply_errored
+	Hook *struct {
+		Resource jsonResource `json:"resource"`
+		Action   string       `json:"action"`
+		Elapsed  int          `json:"elapsed_seconds"`
+	} `json:"hook"`
+
+	// planned_change
+	Change *struct {
+		Resource jsonResource `json:"resource"`
+		Action   string       `json:"action"`
+	} `json:"change"`
+
+	// change_summary
+	Changes *models.ChangeSummary `json:"changes"`
+
+	// diagnostic
+	Diagnostic *struct {
+		Severity string `json:"severity"`
+		Summary  string `json:"summary"`
+		Detail   string `json:"detail"`
+		Address  string `json:"address"`
+		Range    *struct {
+			Filename string       `json:"filename"`
+			Start    jsonPosition `json:"start"`
+			End      jsonPosition `json:"end"`
+		} `json:"range"`
+	} `json:"diagnostic"`
+}
+
+// parseJSONLine decodes a line of terraform -json output into the text it
+// stands for and, for the message types imperm follows, a structured event.
+// Lines that aren't JSON are returned as they are.
+func parseJSONLine(line string) (string, *models.OperationEvent) {
+	var msg jsonMessage
+	if err := json.Unmarshal([]byte(line), &msg); err != nil || msg.Type == "" {
+		return line, nil
+	}
+
+	event := &models.OperationEvent{Type: msg.Type, Message: msg.Message}
+	if t, err := time.Parse(time.RFC3339Nano, msg.Timestamp); err == nil {
+		event.Time = t
+	} else {
+		event.Time = time.Now()
+	}
+
+	text := msg.Message
+	switch msg.Type {
+	case models.EventPlannedChange:
+		// Data sources read during the plan aren't changes
+		if msg.Change == nil || msg.Change.Action == "noop" || msg.Change.Action == "read" {
+			return text, nil
+		}
+		event.Address = msg.Change.Resource.Addr
+		event.Action = msg.Change.Action
+	case models.EventApplyStart, models.EventApplyComplete, models.EventApplyErrored:
+		if msg.Hook == nil {
+			return text, nil
+		}
+		event.Address = msg.Hook.Resource.Addr
+		event.Action = msg.Hook.Action
+		event.Elapsed = msg.Hook.Elapsed
+	case models.EventChangeSummary:
+		if msg.Changes == nil {
+			return text, nil
+		}
+		event.Summary = msg.Changes
+	case models.EventDiagnostic:
+		if msg.Diagnostic == nil {
+			return text, nil
+		}
+		diag := &models.Diagnostic{
+			Severity: msg.Diagnostic.Severity,
+			Summary:  msg.Diagnostic.Summary,
+			Detail:   msg.Diagnostic.Detail,
+			Address:  msg.Diagnostic.Address,
+		}
+		if r := msg.Diagnostic.Range; r != nil {
+			diag.Range = &models.SourceRange{
+				Filename:    r.Filename,
+				StartLine:   r.Start.Line,
+				StartColumn: r.Start.Column,
+				EndLine:     r.End.Line,
+				EndColumn:   r.End.Column,
+			}
+		}
+		event.Address = diag.Address
+		event.Diagnostic = diag
+		if diag.Detail != "" {
+			text = fmt.Sprintf("%s\n%s", text, diag.Detail)
+		}
+	default:
+		return text, nil
+	}
+	return text, event
+}
+
+// progressTracker rolls the structured events of an operation up into where
+// each resource stands as they arrive, so the progress doesn't depend on
+// which events are still kept
+type progressTracker struct {
+	resources   []models.ResourceProgress
+	index       map[string]int
+	diagnostics []models.Diagnostic
+	summary     *models.ChangeSummary
+}
+
+func newProgressTracker() *progressTracker {
+	return &progressTracker{index: make(map[string]int)}
+}
+
+func (p *progressTracker) resource(event models.OperationEvent) *models.ResourceProgress {
+	i, ok := p.index[event.Address]
+	if !ok {
+		i = len(p.resources)
+		p.index[event.Address] = i
+		p.resources = append(p.resources, models.ResourceProgress{Address: event.Address, Action: event.Action})
+	}
+	return &p.resources[i]
+}
+
+// add takes an event into account
+func (p *progressTracker) add(event models.OperationEvent) {
+	switch event.Type {
+	case models.EventPlannedChange:
+		if r := p.resource(event); r.Status == "" {
+			r.Status = models.ResourcePlanned
+		}
+	case models.EventApplyStart:
+		r := p.resource(event)
+		r.Status = models.ResourceApplying
+		r.Action = event.Action
+	case models.EventApplyComplete:
+		r := p.resource(event)
+		r.Status = models.ResourceComplete
+		r.Elapsed = event.Elapsed
+	case models.EventApplyErrored:
+		r := p.resource(event)
+		r.Status = models.ResourceErrored
+		r.Elapsed = event.Elapsed
+	case models.EventDiagnostic:
+		p.diagnostics = append(p.diagnostics, *event.Diagnostic)
+		if len(p.diagnostics) > maxTerraformEvents {
+			p.diagnostics = p.diagnostics[len(p.diagnostics)-maxTerraformEvents:]
+		}
+	case models.EventChangeSummary:
+		if event.Summary.Operation != "plan" {
+			summary := *event.Summary
+			p.summary = &summary
+		}
+	}
+}
+
+// progress returns a copy of where the operation stands
+func (p *progressTracker) progress() models.OperationProgress {
+	progress := models.OperationProgress{
+		Resources:   append([]models.ResourceProgress{}, p.resources...),
+		Diagnostics: append([]models.Diagnostic{}, p.diagnostics...),
+		Total:       len(p.resources),
+	}
+	if p.summary != nil {
+		summary := *p.summary
+		progress.Summary = &summary
+	}
+	for _, r := range p.resources {
+		switch r.Status {
+		case models.ResourceComplete:
+			progress.Done++
+		case models.ResourceErrored:
+			progress.Errored++
+		}
+	}
+	return progress
+}
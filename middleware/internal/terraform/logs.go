@@ -1,20 +1,58 @@
 package terraform
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 	"time"
+
+	"imperm-middleware/pkg/models"
+)
+
+// maxOperationEvents bounds the operation events kept per environment
+const maxOperationEvents = 200
+
+// maxTerraformEvents bounds the structured terraform events kept per operation
+const maxTerraformEvents = 2000
+
+// Limits on operation logs used unless configured otherwise
+const (
+	DefaultLogRetention = time.Hour // How long a finished operation's log is kept
+	DefaultLogMaxLines  = 5000      // How many of the latest lines each operation keeps
 )
 
 // OperationLog stores logs for a terraform operation
 type OperationLog struct {
 	EnvironmentName string
-	Operation       string // "create" or "destroy"
+	Operation       string // "create", "destroy", "adopt", "reconcile", "resume" or "rollback"
 	Lines           []LogLine
 	StartTime       time.Time
 	EndTime         *time.Time
-	Status          string // "running", "completed", "failed"
+	Status          string // "running", "completed", "failed", "interrupted"
+	Phase           string // Step the operation is in, e.g. "init" or "apply"
+	Transitions     []Transition
+	Events          []models.OperationEvent // Latest structured events of terraform -json output
+	progress        *progressTracker        // Progress rolled up from every event, nil until the first
 	Error           string
+	maxLines        int
+	recordFile      string // Where the operation's state is persisted, empty until it has a working directory
 	mutex           sync.RWMutex
+	store           *LogStore
+}
+
+// Statuses of an operation
+const (
+	StatusRunning     = "running"
+	StatusCompleted   = "completed"
+	StatusFailed      = "failed"
+	StatusInterrupted = "interrupted" // The server stopped while it was running
+)
+
+// Transition is one change of an operation's status or phase
+type Transition struct {
+	Time   time.Time `json:"time"`
+	Status string    `json:"status"`
+	Phase  string    `json:"phase,omitempty"`
 }
 
 // LogLine represents a single log line
@@ -23,19 +61,37 @@ type LogLine struct {
 	Content   string
 }
 
-// LogStore manages operation logs
+// LogStore manages the operation logs of one cluster's environments
 type LogStore struct {
-	logs  map[string]*OperationLog
-	mutex sync.RWMutex
+	logs      map[string]*OperationLog
+	events    map[string][]models.TimelineEvent // Operation events per environment, kept after logs are deleted
+	retention time.Duration
+	maxLines  int
+	mutex     sync.RWMutex
 }
 
-var globalLogStore = &LogStore{
-	logs: make(map[string]*OperationLog),
+// NewLogStore creates a log store that keeps finished operation logs for
+// retention and the latest maxLines lines of each; a maxLines of 0 keeps
+// every line
+func NewLogStore(retention time.Duration, maxLines int) *LogStore {
+	return &LogStore{
+		logs:      make(map[string]*OperationLog),
+		events:    make(map[string][]models.TimelineEvent),
+		retention: retention,
+		maxLines:  maxLines,
+	}
 }
 
-// GetLogStore returns the global log store
-func GetLogStore() *LogStore {
-	return globalLogStore
+// pruneLocked drops the logs of operations that finished longer than the retention ago
+func (s *LogStore) pruneLocked(now time.Time) {
+	for envName, log := range s.logs {
+		log.mutex.RLock()
+		expired := log.EndTime != nil && now.Sub(*log.EndTime) > s.retention
+		log.mutex.RUnlock()
+		if expired {
+			delete(s.logs, envName)
+		}
+	}
 }
 
 // CreateOperation creates a new operation log
@@ -43,22 +99,73 @@ func (s *LogStore) CreateOperation(envName, operation string) *OperationLog {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
+	s.pruneLocked(time.Now())
 	log := &OperationLog{
 		EnvironmentName: envName,
 		Operation:       operation,
 		Lines:           []LogLine{},
 		StartTime:       time.Now(),
-		Status:          "running",
+		Status:          StatusRunning,
+		maxLines:        s.maxLines,
+		store:           s,
 	}
+	log.Transitions = []Transition{{Time: log.StartTime, Status: StatusRunning}}
 
 	s.logs[envName] = log
+	s.recordEventLocked(envName, models.TimelineEvent{
+		Time:    log.StartTime,
+		Type:    "Normal",
+		Reason:  operationReason(operation, "Started"),
+		Message: fmt.Sprintf("%s started", operation),
+	})
 	return log
 }
 
-// GetOperation retrieves an operation log
-func (s *LogStore) GetOperation(envName string) *OperationLog {
+// RecordEvent adds an imperm event to an environment's timeline
+func (s *LogStore) RecordEvent(envName string, event models.TimelineEvent) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.recordEventLocked(envName, event)
+}
+
+func (s *LogStore) recordEventLocked(envName string, event models.TimelineEvent) {
+	event.Source = models.EventSourceImperm
+	event.Kind = "Environment"
+	event.Name = envName
+	if event.Count == 0 {
+		event.Count = 1
+	}
+
+	events := append(s.events[envName], event)
+	if len(events) > maxOperationEvents {
+		events = events[len(events)-maxOperationEvents:]
+	}
+	s.events[envName] = events
+}
+
+// Events returns the operation events recorded for an environment
+func (s *LogStore) Events(envName string) []models.TimelineEvent {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
+
+	events := make([]models.TimelineEvent, len(s.events[envName]))
+	copy(events, s.events[envName])
+	return events
+}
+
+// operationReason builds an event reason such as "CreateStarted"
+func operationReason(operation, outcome string) string {
+	if operation == "" {
+		return outcome
+	}
+	return strings.ToUpper(operation[:1]) + operation[1:] + outcome
+}
+
+// GetOperation retrieves an operation log
+func (s *LogStore) GetOperation(envName string) *OperationLog {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.pruneLocked(time.Now())
 	return s.logs[envName]
 }
 
@@ -85,34 +192,102 @@ func (s *LogStore) DeleteOperation(envName string) {
 func (o *OperationLog) AddLine(content string) {
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
+	o.appendLineLocked(content)
+}
 
+func (o *OperationLog) appendLineLocked(content string) {
 	o.Lines = append(o.Lines, LogLine{
 		Timestamp: time.Now(),
 		Content:   content,
 	})
+	if o.maxLines > 0 && len(o.Lines) > o.maxLines {
+		o.Lines = o.Lines[len(o.Lines)-o.maxLines:]
+	}
 }
 
-// SetCompleted marks the operation as completed
-func (o *OperationLog) SetCompleted() {
+// AddEvent adds a structured terraform event to the operation
+func (o *OperationLog) AddEvent(event models.OperationEvent) {
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
 
+	if o.progress == nil {
+		o.progress = newProgressTracker()
+	}
+	o.progress.add(event)
+
+	// Only the raw events are trimmed; the progress has taken them into account
+	o.Events = append(o.Events, event)
+	if len(o.Events) > maxTerraformEvents {
+		o.Events = o.Events[len(o.Events)-maxTerraformEvents:]
+	}
+}
+
+// SetCompleted marks the operation as completed
+func (o *OperationLog) SetCompleted() {
+	o.mutex.Lock()
 	now := time.Now()
 	o.EndTime = &now
-	o.Status = "completed"
+	o.Status = StatusCompleted
+	o.transitionLocked(now)
+	event := models.TimelineEvent{
+		Time:    now,
+		Type:    "Normal",
+		Reason:  operationReason(o.Operation, "Completed"),
+		Message: fmt.Sprintf("%s completed in %s", o.Operation, now.Sub(o.StartTime).Round(time.Second)),
+	}
+	o.mutex.Unlock()
+
+	o.recordEvent(event)
 }
 
 // SetFailed marks the operation as failed
 func (o *OperationLog) SetFailed(err error) {
 	o.mutex.Lock()
-	defer o.mutex.Unlock()
-
 	now := time.Now()
 	o.EndTime = &now
-	o.Status = "failed"
+	o.Status = StatusFailed
 	if err != nil {
 		o.Error = err.Error()
 	}
+	o.transitionLocked(now)
+	event := models.TimelineEvent{
+		Time:    now,
+		Type:    "Warning",
+		Reason:  operationReason(o.Operation, "Failed"),
+		Message: fmt.Sprintf("%s failed: %s", o.Operation, o.Error),
+	}
+	o.mutex.Unlock()
+
+	o.recordEvent(event)
+}
+
+// SetPhase records the step the operation moves on to
+func (o *OperationLog) SetPhase(phase string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.Phase = phase
+	o.transitionLocked(time.Now())
+}
+
+// transitionLocked notes the current status and phase and persists them
+func (o *OperationLog) transitionLocked(now time.Time) {
+	o.Transitions = append(o.Transitions, Transition{Time: now, Status: o.Status, Phase: o.Phase})
+	if o.recordFile == "" {
+		return
+	}
+	if err := saveRecord(o.recordFile, o.recordLocked()); err != nil {
+		o.appendLineLocked(fmt.Sprintf("Warning: %v", err))
+	}
+}
+
+// recordEvent adds an event to the store the operation belongs to. It takes
+// the store's lock, which is held while the store reads its logs, so it must
+// be called without holding the operation's own lock.
+func (o *OperationLog) recordEvent(event models.TimelineEvent) {
+	if o.store != nil {
+		o.store.RecordEvent(o.EnvironmentName, event)
+	}
 }
 
 // GetLines returns all log lines (thread-safe)
@@ -125,6 +300,26 @@ func (o *OperationLog) GetLines() []LogLine {
 	return lines
 }
 
+// GetEvents returns all structured events (thread-safe)
+func (o *OperationLog) GetEvents() []models.OperationEvent {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	events := make([]models.OperationEvent, len(o.Events))
+	copy(events, o.Events)
+	return events
+}
+
+// GetProgress returns how far the operation got with each resource (thread-safe)
+func (o *OperationLog) GetProgress() models.OperationProgress {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	if o.progress == nil {
+		return newProgressTracker().progress()
+	}
+	return o.progress.progress()
+}
+
 // GetStatus returns the current status (thread-safe)
 func (o *OperationLog) GetStatus() string {
 	o.mutex.RLock()
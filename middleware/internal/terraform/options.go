@@ -0,0 +1,56 @@
+package terraform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"imperm-middleware/pkg/models"
+)
+
+// optionsFile records the options an environment was created with, next to its main.tf
+const optionsFile = "options.json"
+
+// storedOptions are the options of an environment together with the module
+// it was created from. The module is only ever set by the server, from its
+// configured module path, never from a request.
+type storedOptions struct {
+	models.DeploymentOptions
+	Module string `json:"module"`
+}
+
+// saveOptions writes the options and module used for an environment into its working directory
+func saveOptions(envDir string, options *models.DeploymentOptions, module string) error {
+	stored := storedOptions{Module: module}
+	if options != nil {
+		stored.DeploymentOptions = *options
+	}
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode deployment options: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(envDir, optionsFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", optionsFile, err)
+	}
+	return nil
+}
+
+// loadOptions reads the options an environment was created with and its
+// module, empty for environments created before the module was recorded
+func loadOptions(envDir string) (*models.DeploymentOptions, string, error) {
+	data, err := os.ReadFile(filepath.Join(envDir, optionsFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, "", fmt.Errorf("no stored options in %s", envDir)
+		}
+		return nil, "", fmt.Errorf("failed to read %s: %w", optionsFile, err)
+	}
+
+	var stored storedOptions
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, "", fmt.Errorf("failed to decode %s: %w", optionsFile, err)
+	}
+	return &stored.DeploymentOptions, stored.Module, nil
+}
@@ -0,0 +1,135 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// lockFileName is the dependency lock file terraform init writes
+const lockFileName = ".terraform.lock.hcl"
+
+// Providers says how terraform init gets the environments' providers
+// without downloading them for every environment. Empty fields are unused.
+type Providers struct {
+	PluginCacheDir string // Shared TF_PLUGIN_CACHE_DIR that downloaded providers are linked from
+	Mirror         string // Offline mirror providers are installed from instead of a registry
+	LockFile       string // Lock file template; saved from the first init when missing
+}
+
+// Terraform doesn't support concurrent inits sharing a plugin cache, so inits
+// are serialized per cache directory
+var (
+	pluginCacheMutex sync.Mutex
+	pluginCacheLocks = make(map[string]*sync.Mutex)
+)
+
+// lock takes the plugin cache for an init, returning the function releasing
+// it; without a cache there is nothing to take
+func (p Providers) lock() func() {
+	if p.PluginCacheDir == "" {
+		return func() {}
+	}
+	dir, err := filepath.Abs(p.PluginCacheDir)
+	if err != nil {
+		dir = filepath.Clean(p.PluginCacheDir)
+	}
+
+	pluginCacheMutex.Lock()
+	mutex, ok := pluginCacheLocks[dir]
+	if !ok {
+		mutex = &sync.Mutex{}
+		pluginCacheLocks[dir] = mutex
+	}
+	pluginCacheMutex.Unlock()
+
+	mutex.Lock()
+	return mutex.Unlock
+}
+
+// prepare readies the plugin cache and puts the lock file template in place
+// in a working directory about to be initialized
+func (p Providers) prepare(workingDir string) error {
+	if p.PluginCacheDir != "" {
+		if err := os.MkdirAll(p.PluginCacheDir, 0755); err != nil {
+			return fmt.Errorf("failed to create plugin cache directory: %w", err)
+		}
+	}
+	if p.LockFile == "" {
+		return nil
+	}
+
+	target := filepath.Join(workingDir, lockFileName)
+	if _, err := os.Stat(target); err == nil {
+		return nil
+	}
+	data, err := os.ReadFile(p.LockFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read lock file template: %w", err)
+	}
+	if err := os.WriteFile(target, data, 0644); err != nil {
+		return fmt.Errorf("failed to write lock file: %w", err)
+	}
+	return nil
+}
+
+// saveLockFile keeps the lock file of an initialized working directory as the
+// template, if there is none yet
+func (p Providers) saveLockFile(workingDir string) error {
+	if p.LockFile == "" {
+		return nil
+	}
+	if _, err := os.Stat(p.LockFile); err == nil {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(workingDir, lockFileName))
+	if err != nil {
+		return fmt.Errorf("failed to read lock file: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(p.LockFile), 0755); err != nil {
+		return fmt.Errorf("failed to create lock file template directory: %w", err)
+	}
+
+	// Written aside and renamed into place, so a concurrent init never reads half a template
+	tmp, err := os.CreateTemp(filepath.Dir(p.LockFile), filepath.Base(p.LockFile)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save lock file template: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save lock file template: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save lock file template: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to save lock file template: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), p.LockFile); err != nil {
+		return fmt.Errorf("failed to save lock file template: %w", err)
+	}
+	return nil
+}
+
+// initArgs are the terraform init arguments installing providers this way
+func (p Providers) initArgs() []string {
+	args := []string{"init", "-input=false"}
+	if p.Mirror != "" {
+		args = append(args, "-plugin-dir="+p.Mirror)
+	}
+	return args
+}
+
+// env is the environment terraform init runs with
+func (p Providers) env() []string {
+	env := os.Environ()
+	if p.PluginCacheDir != "" {
+		env = append(env, "TF_PLUGIN_CACHE_DIR="+p.PluginCacheDir)
+	}
+	return env
+}
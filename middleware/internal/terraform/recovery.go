@@ -0,0 +1,506 @@
+package terraform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"imperm-middleware/pkg/models"
+)
+
+// Files in an environment's working directory telling how its last operation went
+const (
+	recordFileName   = "operation.json"               // imperm's record of the last operation
+	lockInfoName     = ".terraform.tfstate.lock.info" // Local state lock, removed by terraform when done
+	erroredStateName = "errored.tfstate"              // State terraform couldn't save when it stopped
+)
+
+// Lock Info terraform prints when it fails to acquire a state lock that is held
+var (
+	heldLockID      = regexp.MustCompile(`Error acquiring the state lock[\s\S]*?\bID:\s+(\S+)`)
+	heldLockCreated = regexp.MustCompile(`Created:\s+(.+)`)
+)
+
+// Phases in which terraform changes resources, leaving the state incomplete when cut short
+var changingPhases = map[string]bool{"apply": true, "destroy": true, "import": true}
+
+// operationRecord is the persisted state of an operation
+type operationRecord struct {
+	Environment string       `json:"environment"`
+	Operation   string       `json:"operation"`
+	Status      string       `json:"status"`
+	Phase       string       `json:"phase,omitempty"`
+	StartTime   time.Time    `json:"start_time"`
+	EndTime     *time.Time   `json:"end_time,omitempty"`
+	Error       string       `json:"error,omitempty"`
+	Transitions []Transition `json:"transitions"`
+}
+
+func saveRecord(path string, record operationRecord) error {
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode operation record: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", recordFileName, err)
+	}
+	return nil
+}
+
+// loadRecord reads the record of an environment's last operation, nil when there is none
+func loadRecord(envDir string) (*operationRecord, error) {
+	data, err := os.ReadFile(filepath.Join(envDir, recordFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", recordFileName, err)
+	}
+
+	var record operationRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", recordFileName, err)
+	}
+	return &record, nil
+}
+
+func (o *OperationLog) recordLocked() operationRecord {
+	return operationRecord{
+		Environment: o.EnvironmentName,
+		Operation:   o.Operation,
+		Status:      o.Status,
+		Phase:       o.Phase,
+		StartTime:   o.StartTime,
+		EndTime:     o.EndTime,
+		Error:       o.Error,
+		Transitions: o.Transitions,
+	}
+}
+
+// Persist keeps the operation's state in the environment's working directory
+// from now on, so it is known after a restart how far it got
+func (o *OperationLog) Persist(envDir string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.recordFile = filepath.Join(envDir, recordFileName)
+	if err := saveRecord(o.recordFile, o.recordLocked()); err != nil {
+		o.appendLineLocked(fmt.Sprintf("Warning: %v", err))
+	}
+}
+
+// StopPersisting stops keeping the operation's state, before its working
+// directory is removed
+func (o *OperationLog) StopPersisting() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.recordFile = ""
+}
+
+// restoreInterrupted brings back the log of an operation found interrupted,
+// marking it as such in its record
+func (s *LogStore) restoreInterrupted(envDir string, record operationRecord, now time.Time) *OperationLog {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	log := &OperationLog{
+		EnvironmentName: record.Environment,
+		Operation:       record.Operation,
+		Lines:           []LogLine{},
+		StartTime:       record.StartTime,
+		EndTime:         &now,
+		Status:          StatusInterrupted,
+		Phase:           record.Phase,
+		Transitions:     append(record.Transitions, Transition{Time: now, Status: StatusInterrupted, Phase: record.Phase}),
+		Error:           "interrupted by a server restart",
+		maxLines:        s.maxLines,
+		recordFile:      filepath.Join(envDir, recordFileName),
+		store:           s,
+	}
+	log.appendLineLocked(fmt.Sprintf("The server stopped while this %s was running; resume or roll it back", record.Operation))
+	if err := saveRecord(log.recordFile, log.recordLocked()); err != nil {
+		log.appendLineLocked(fmt.Sprintf("Warning: %v", err))
+	}
+
+	s.logs[record.Environment] = log
+	s.recordEventLocked(record.Environment, models.TimelineEvent{
+		Time:    now,
+		Type:    "Warning",
+		Reason:  operationReason(record.Operation, "Interrupted"),
+		Message: fmt.Sprintf("%s interrupted by a server restart", record.Operation),
+	})
+	return log
+}
+
+// interruptions holds the interrupted operations found on startup until
+// they are resumed or rolled back
+type interruptions struct {
+	ops     map[string]models.InterruptedOperation
+	claimed map[string]bool // Environments being resumed or rolled back
+	mutex   sync.RWMutex
+}
+
+func (i *interruptions) get(name string) (models.InterruptedOperation, bool) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	op, ok := i.ops[name]
+	return op, ok
+}
+
+func (i *interruptions) list() []models.InterruptedOperation {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	ops := make([]models.InterruptedOperation, 0, len(i.ops))
+	for _, op := range i.ops {
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(a, b int) bool { return ops[a].Environment < ops[b].Environment })
+	return ops
+}
+
+func (i *interruptions) set(op models.InterruptedOperation) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.ops[op.Environment] = op
+}
+
+func (i *interruptions) forget(name string) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	delete(i.ops, name)
+	delete(i.claimed, name)
+}
+
+// claim reserves an interrupted operation for one resume or rollback at a
+// time, until it is forgotten or unclaimed
+func (i *interruptions) claim(name string) (models.InterruptedOperation, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	op, ok := i.ops[name]
+	if !ok {
+		return op, fmt.Errorf("environment %s has no interrupted operation", name)
+	}
+	if i.claimed[name] {
+		return op, fmt.Errorf("environment %s is already being resumed or rolled back", name)
+	}
+	i.claimed[name] = true
+	return op, nil
+}
+
+func (i *interruptions) unclaim(name string) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	delete(i.claimed, name)
+}
+
+// lockReleased records that an interrupted operation's stale lock is gone
+func (i *interruptions) lockReleased(name string) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	if op, ok := i.ops[name]; ok {
+		op.StaleLock = false
+		i.ops[name] = op
+	}
+}
+
+// RecoverOperations scans the environments' working directories for
+// operations that were running when the server stopped: ones whose record
+// still says running, or that left a state lock or unsaved state behind.
+// They are marked interrupted and kept until resumed or rolled back.
+func (c *TerraformClient) RecoverOperations() ([]models.InterruptedOperation, error) {
+	entries, err := os.ReadDir(c.baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", c.baseDir, err)
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		envDir := filepath.Join(c.baseDir, entry.Name())
+		record, err := loadRecord(envDir)
+		if err != nil {
+			log.Printf("Skipping %s: %v", envDir, err)
+			continue
+		}
+
+		staleLock := fileExists(filepath.Join(envDir, lockInfoName))
+		erroredState := fileExists(filepath.Join(envDir, erroredStateName))
+		unfinished := record != nil && (record.Status == StatusRunning || record.Status == StatusInterrupted)
+		if !unfinished && !staleLock && !erroredState {
+			continue
+		}
+
+		if record == nil || !unfinished {
+			// Terraform was stopped by something imperm has no record of
+			record = &operationRecord{Environment: entry.Name(), Operation: "unknown", Status: StatusRunning}
+			if info, err := entry.Info(); err == nil {
+				record.StartTime = info.ModTime()
+			}
+		}
+		record.Environment = entry.Name()
+		c.logs.restoreInterrupted(envDir, *record, now)
+		c.interrupted.set(models.InterruptedOperation{
+			Environment:  entry.Name(),
+			Operation:    record.Operation,
+			Phase:        record.Phase,
+			StartedAt:    record.StartTime,
+			DetectedAt:   now,
+			StaleLock:    staleLock,
+			PartialState: erroredState || changingPhases[record.Phase],
+		})
+	}
+	return c.interrupted.list(), nil
+}
+
+// InterruptedOperations returns the interrupted operations not yet resumed or rolled back
+func (c *TerraformClient) InterruptedOperations() ([]models.InterruptedOperation, error) {
+	return c.interrupted.list(), nil
+}
+
+// ResumeEnvironment finishes an interrupted operation: a destroy is run
+// again, an adoption is started over and anything else re-applies the
+// environment's configuration
+func (c *TerraformClient) ResumeEnvironment(name string) error {
+	envDir, interrupted, err := c.interruptedOperation(name)
+	if err != nil {
+		return err
+	}
+	defer c.interrupted.unclaim(name)
+
+	switch interrupted.Operation {
+	case "destroy":
+		executor := c.newExecutor(envDir)
+		if err := c.releaseStaleLock(executor, interrupted); err != nil {
+			return err
+		}
+		err := c.retryUnlocked(executor, interrupted, func() error {
+			return c.DestroyEnvironment(name)
+		})
+		if err != nil {
+			return err
+		}
+	case "adopt":
+		// Imports can't be resumed halfway, so the adoption starts from scratch
+		if err := RemoveWorkingDir(c.baseDir, name); err != nil {
+			return err
+		}
+		if err := c.k8sClient.UnlabelManaged(name); err != nil {
+			return err
+		}
+		if _, err := c.AdoptEnvironment(name); err != nil {
+			return err
+		}
+	default:
+		opLog := c.logs.CreateOperation(name, "resume")
+		opLog.Persist(envDir)
+		executor := c.operationExecutor(envDir, opLog)
+
+		opLog.AddLine(fmt.Sprintf("Resuming %s interrupted during %s...", interrupted.Operation, phaseOrStart(interrupted.Phase)))
+		if err := c.releaseStaleLock(executor, interrupted); err != nil {
+			opLog.SetFailed(err)
+			return err
+		}
+		opLog.SetPhase("init")
+		if err := executor.Init(); err != nil {
+			opLog.SetFailed(err)
+			return err
+		}
+		opLog.SetPhase("apply")
+		if err := c.retryUnlocked(executor, interrupted, executor.Apply); err != nil {
+			opLog.SetFailed(err)
+			return err
+		}
+		opLog.SetCompleted()
+		opLog.AddLine("Environment resumed successfully!")
+		c.drift.Check(name)
+	}
+
+	c.interrupted.forget(name)
+	return nil
+}
+
+// RollbackEnvironment undoes an interrupted operation: the environment is
+// destroyed, except for an interrupted adoption, which only drops the working
+// directory and the namespace's imperm labels, leaving what runs in it alone
+func (c *TerraformClient) RollbackEnvironment(name string) error {
+	envDir, interrupted, err := c.interruptedOperation(name)
+	if err != nil {
+		return err
+	}
+	defer c.interrupted.unclaim(name)
+
+	opLog := c.logs.CreateOperation(name, "rollback")
+	opLog.Persist(envDir)
+	executor := c.operationExecutor(envDir, opLog)
+
+	opLog.AddLine(fmt.Sprintf("Rolling back %s interrupted during %s...", interrupted.Operation, phaseOrStart(interrupted.Phase)))
+	if interrupted.Operation != "adopt" {
+		if err := c.releaseStaleLock(executor, interrupted); err != nil {
+			opLog.SetFailed(err)
+			return err
+		}
+		opLog.SetPhase("init")
+		if err := executor.Init(); err != nil {
+			opLog.SetFailed(err)
+			return err
+		}
+		opLog.SetPhase("destroy")
+		if err := c.retryUnlocked(executor, interrupted, executor.Destroy); err != nil {
+			opLog.SetFailed(err)
+			return err
+		}
+	}
+
+	opLog.AddLine("Cleaning up working directory...")
+	opLog.StopPersisting()
+	if err := RemoveWorkingDir(c.baseDir, name); err != nil {
+		opLog.SetFailed(err)
+		return err
+	}
+	if interrupted.Operation == "adopt" {
+		if err := c.k8sClient.UnlabelManaged(name); err != nil {
+			opLog.SetFailed(err)
+			return err
+		}
+	}
+	c.drift.Forget(name)
+	c.interrupted.forget(name)
+
+	opLog.SetCompleted()
+	opLog.AddLine("Environment rolled back successfully!")
+	return nil
+}
+
+// interruptedOperation claims the interrupted operation of an environment
+// that can be resumed or rolled back now; the caller unclaims it when done
+func (c *TerraformClient) interruptedOperation(name string) (string, models.InterruptedOperation, error) {
+	interrupted, err := c.interrupted.claim(name)
+	if err != nil {
+		return "", interrupted, err
+	}
+	if op := c.logs.GetOperation(name); op != nil && op.GetStatus() == StatusRunning {
+		c.interrupted.unclaim(name)
+		return "", interrupted, fmt.Errorf("environment %s already has a %s running", name, op.Operation)
+	}
+
+	envDir := filepath.Join(c.baseDir, name)
+	if _, err := os.Stat(envDir); os.IsNotExist(err) {
+		c.interrupted.unclaim(name)
+		return "", interrupted, fmt.Errorf("environment %s has no Terraform working directory", name)
+	}
+	return envDir, interrupted, nil
+}
+
+// releaseStaleLock removes the state lock an interrupted terraform left behind
+func (c *TerraformClient) releaseStaleLock(executor *Executor, interrupted models.InterruptedOperation) error {
+	if !interrupted.StaleLock {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(executor.workingDir, lockInfoName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read state lock: %w", err)
+	}
+
+	var lock struct {
+		ID string `json:"ID"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return fmt.Errorf("failed to decode state lock: %w", err)
+	}
+	executor.log(fmt.Sprintf("Releasing stale state lock %s...", lock.ID))
+	if err := executor.ForceUnlock(lock.ID); err != nil {
+		return err
+	}
+	c.interrupted.lockReleased(interrupted.Environment)
+	return nil
+}
+
+// retryUnlocked runs a terraform command of an interrupted operation. Backends
+// outside the working directory keep their lock remotely, where recovery
+// can't see it, so when the command fails on a state lock taken before the
+// interruption was detected, that lock is released and the command run again.
+func (c *TerraformClient) retryUnlocked(executor *Executor, interrupted models.InterruptedOperation, run func() error) error {
+	err := run()
+	if err == nil || !c.backend.external() {
+		return err
+	}
+	held := heldLockID.FindStringSubmatch(err.Error())
+	if held == nil {
+		return err
+	}
+
+	// A lock taken since may belong to someone else working on the state
+	created := heldLockCreated.FindStringSubmatch(err.Error())
+	if created == nil {
+		return err
+	}
+	createdAt, parseErr := time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", strings.TrimSpace(created[1]))
+	if parseErr != nil || !createdAt.Before(interrupted.DetectedAt) {
+		return err
+	}
+
+	executor.log(fmt.Sprintf("Releasing stale state lock %s...", held[1]))
+	if err := executor.ForceUnlock(held[1]); err != nil {
+		return err
+	}
+	return run()
+}
+
+// withInterrupted attaches interrupted operations to the listed environments,
+// adding the ones that never got as far as creating their namespace
+func (c *TerraformClient) withInterrupted(envs []models.Environment) []models.Environment {
+	listed := make(map[string]bool, len(envs))
+	for i := range envs {
+		listed[envs[i].Name] = true
+		if op, ok := c.interrupted.get(envs[i].Name); ok {
+			envs[i].Interrupted = &op
+		}
+	}
+
+	for _, op := range c.interrupted.list() {
+		if listed[op.Environment] {
+			continue
+		}
+		op := op
+		envs = append(envs, models.Environment{
+			Name:      op.Environment,
+			Namespace: op.Environment,
+			Status:    "Interrupted",
+			Age:       op.StartedAt,
+			Health: models.EnvironmentHealth{
+				Status:  models.HealthFailing,
+				Reasons: []string{fmt.Sprintf("%s interrupted before the namespace was created", op.Operation)},
+			},
+			Managed:     true,
+			Interrupted: &op,
+		})
+	}
+	return envs
+}
+
+func phaseOrStart(phase string) string {
+	if phase == "" {
+		return "start"
+	}
+	return phase
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -1,29 +1,71 @@
 package client
 
 import (
+	"context"
+	"io"
+
 	"imperm-middleware/pkg/models"
 )
 
 // Client defines the interface for interacting with the Kubernetes middleware
 type Client interface {
 	// Environment operations
-	ListEnvironments() ([]models.Environment, error)
+	ListEnvironments(filter models.EnvironmentFilter) ([]models.Environment, error)
 	CreateEnvironment(name string, options *models.DeploymentOptions) error
 	DestroyEnvironment(name string) error
+	CloneEnvironment(sourceName string, req models.CloneEnvironmentRequest) (*models.DeploymentOptions, error)
+	AdoptEnvironment(name string) (*models.DeploymentOptions, error)
+	GetEnvironmentEvents(envName string, filter models.EventFilter) ([]models.TimelineEvent, error)
+	ApplyManifest(envName string, req models.ApplyManifestRequest) (*models.ApplyResult, error)
 
 	// Pod operations
 	ListPods(namespace string) ([]models.Pod, error)
 	GetPodLogs(namespace, podName string) (string, error)
-	GetPodEvents(namespace, podName string) ([]models.Event, error)
+	GetPodEvents(namespace, podName string) ([]models.EventTimeEntry, error)
 	DeletePod(namespace, podName string) error
+	ExecPod(ctx context.Context, req models.ExecRequest, streams ExecStreams) error
 
 	// Deployment operations
 	ListDeployments(namespace string) ([]models.Deployment, error)
-	GetDeploymentEvents(namespace, deploymentName string) ([]models.Event, error)
+	GetDeploymentEvents(namespace, deploymentName string) ([]models.EventTimeEntry, error)
 	DeleteDeployment(namespace, deploymentName string) error
+	ScaleDeployment(namespace, deploymentName string, replicas int) error
+
+	// Rollout operations
+	RestartDeployment(namespace, deploymentName string) error
+	PauseDeployment(namespace, deploymentName string) error
+	ResumeDeployment(namespace, deploymentName string) error
+	GetDeploymentHistory(namespace, deploymentName string) ([]models.DeploymentRevision, error)
+	RollbackDeployment(namespace, deploymentName string, revision int64) error
+
+	// Service and config operations
+	ListServices(namespace string) ([]models.Service, error)
+	GetService(namespace, name string) (*models.Service, error)
+	ListConfigMaps(namespace string) ([]models.ConfigMap, error)
+	GetConfigMap(namespace, name string) (*models.ConfigMap, error)
+	ListSecrets(namespace string) ([]models.Secret, error)
+	GetSecret(namespace, name string, reveal bool) (*models.Secret, error)
+	ListIngresses(namespace string) ([]models.Ingress, error)
+	GetIngress(namespace, name string) (*models.Ingress, error)
+
+	// Workload operations
+	ListJobs(namespace string) ([]models.Job, error)
+	GetJob(namespace, name string) (*models.Job, error)
+	ListStatefulSets(namespace string) ([]models.StatefulSet, error)
+	GetStatefulSet(namespace, name string) (*models.StatefulSet, error)
+
+	// Manifests (kind is one of the models.Kind* constants)
+	GetResourceManifest(kind, namespace, name string, reveal bool) (*models.ResourceManifest, error)
+
+	// Port forwarding
+	StartPortForward(req models.PortForwardRequest) (*models.PortForward, error)
+	ListPortForwards() ([]models.PortForward, error)
+	StopPortForward(id string) error
+	DialPortForward(id string) (io.ReadWriteCloser, error)
 
 	// Metrics operations
 	GetPodMetrics(namespace string) ([]models.PodMetrics, error)
+	GetClusterCapacity() (*models.ClusterCapacity, error)
 
 	// Stats operations
 	GetResourceStats(resourceType, namespace string) (*models.ResourceStats, error)
@@ -31,3 +73,12 @@ type Client interface {
 	// History
 	GetEnvironmentHistory() ([]models.EnvironmentHistory, error)
 }
+
+// ExecStreams connects an exec session to the caller. Stdin and Resize may be
+// nil; Stderr is unused when the session runs with a TTY.
+type ExecStreams struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	Resize <-chan models.TerminalSize
+}
@@ -2,9 +2,11 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"imperm-middleware/pkg/models"
+	"io"
 	"net/http"
 )
 
@@ -23,7 +25,7 @@ func NewHTTPClient(baseURL string) *HTTPClient {
 }
 
 // ListEnvironments fetches all environments from the upstream API
-func (c *HTTPClient) ListEnvironments() ([]models.Environment, error) {
+func (c *HTTPClient) ListEnvironments(filter models.EnvironmentFilter) ([]models.Environment, error) {
 	// This would need to be implemented based on how environments are managed
 	// For now, return empty list as environments are managed separately
 	return []models.Environment{}, nil
@@ -41,6 +43,26 @@ func (c *HTTPClient) DestroyEnvironment(name string) error {
 	return fmt.Errorf("not implemented via upstream API")
 }
 
+// CloneEnvironment creates a copy of an environment
+func (c *HTTPClient) CloneEnvironment(sourceName string, req models.CloneEnvironmentRequest) (*models.DeploymentOptions, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
+// AdoptEnvironment brings an existing namespace under imperm management
+func (c *HTTPClient) AdoptEnvironment(name string) (*models.DeploymentOptions, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
+// GetEnvironmentEvents fetches the event timeline of an environment
+func (c *HTTPClient) GetEnvironmentEvents(envName string, filter models.EventFilter) ([]models.TimelineEvent, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
+// ApplyManifest applies YAML into an environment
+func (c *HTTPClient) ApplyManifest(envName string, req models.ApplyManifestRequest) (*models.ApplyResult, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
 // ListPods fetches all pods from the upstream API
 func (c *HTTPClient) ListPods(namespace string) ([]models.Pod, error) {
 	url := fmt.Sprintf("%s/api/k8s/%s/pods", c.baseURL, namespace)
@@ -93,7 +115,7 @@ func (c *HTTPClient) GetPodLogs(namespace, podName string) (string, error) {
 }
 
 // GetPodEvents fetches events for a specific pod
-func (c *HTTPClient) GetPodEvents(namespace, podName string) ([]models.Event, error) {
+func (c *HTTPClient) GetPodEvents(namespace, podName string) ([]models.EventTimeEntry, error) {
 	url := fmt.Sprintf("%s/api/k8s/%s/events", c.baseURL, namespace)
 
 	resp, err := c.httpClient.Get(url)
@@ -106,24 +128,11 @@ func (c *HTTPClient) GetPodEvents(namespace, podName string) ([]models.Event, er
 		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	// Upstream returns []EventTimeEntry, convert to []Event
-	var eventEntries []models.EventTimeEntry
-	if err := json.NewDecoder(resp.Body).Decode(&eventEntries); err != nil {
+	var events []models.EventTimeEntry
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
 		return nil, fmt.Errorf("failed to decode events: %w", err)
 	}
 
-	// Convert EventTimeEntry to Event
-	events := make([]models.Event, 0, len(eventEntries))
-	for _, entry := range eventEntries {
-		events = append(events, models.Event{
-			Type:      entry.Type,
-			Reason:    entry.Reason,
-			Message:   entry.Message,
-			Timestamp: entry.LastObservedTime,
-			Count:     entry.Count,
-		})
-	}
-
 	return events, nil
 }
 
@@ -133,6 +142,96 @@ func (c *HTTPClient) DeletePod(namespace, podName string) error {
 	return fmt.Errorf("not implemented via upstream API")
 }
 
+// ExecPod runs a command in a pod container
+func (c *HTTPClient) ExecPod(ctx context.Context, req models.ExecRequest, streams ExecStreams) error {
+	return fmt.Errorf("not implemented via upstream API")
+}
+
+// ListServices fetches services from the upstream API
+func (c *HTTPClient) ListServices(namespace string) ([]models.Service, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
+// GetService fetches a service from the upstream API
+func (c *HTTPClient) GetService(namespace, name string) (*models.Service, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
+// ListConfigMaps fetches config maps from the upstream API
+func (c *HTTPClient) ListConfigMaps(namespace string) ([]models.ConfigMap, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
+// GetConfigMap fetches a config map from the upstream API
+func (c *HTTPClient) GetConfigMap(namespace, name string) (*models.ConfigMap, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
+// ListSecrets fetches secrets from the upstream API
+func (c *HTTPClient) ListSecrets(namespace string) ([]models.Secret, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
+// GetSecret fetches a secret from the upstream API
+func (c *HTTPClient) GetSecret(namespace, name string, reveal bool) (*models.Secret, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
+// ListIngresses fetches ingresses from the upstream API
+func (c *HTTPClient) ListIngresses(namespace string) ([]models.Ingress, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
+// GetIngress fetches an ingress from the upstream API
+func (c *HTTPClient) GetIngress(namespace, name string) (*models.Ingress, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
+// ListJobs fetches jobs from the upstream API
+func (c *HTTPClient) ListJobs(namespace string) ([]models.Job, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
+// GetJob fetches a job from the upstream API
+func (c *HTTPClient) GetJob(namespace, name string) (*models.Job, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
+// ListStatefulSets fetches stateful sets from the upstream API
+func (c *HTTPClient) ListStatefulSets(namespace string) ([]models.StatefulSet, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
+// GetStatefulSet fetches a stateful set from the upstream API
+func (c *HTTPClient) GetStatefulSet(namespace, name string) (*models.StatefulSet, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
+// GetResourceManifest fetches the live manifest of a resource from the upstream API
+func (c *HTTPClient) GetResourceManifest(kind, namespace, name string, reveal bool) (*models.ResourceManifest, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
+// StartPortForward opens a port-forward session
+func (c *HTTPClient) StartPortForward(req models.PortForwardRequest) (*models.PortForward, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
+// ListPortForwards lists open port-forward sessions
+func (c *HTTPClient) ListPortForwards() ([]models.PortForward, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
+// StopPortForward closes a port-forward session
+func (c *HTTPClient) StopPortForward(id string) error {
+	return fmt.Errorf("not implemented via upstream API")
+}
+
+// DialPortForward opens a connection through a port-forward session
+func (c *HTTPClient) DialPortForward(id string) (io.ReadWriteCloser, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
 // ListDeployments fetches all deployments from the upstream API
 func (c *HTTPClient) ListDeployments(namespace string) ([]models.Deployment, error) {
 	url := fmt.Sprintf("%s/api/k8s/%s/deployments", c.baseURL, namespace)
@@ -162,7 +261,7 @@ func (c *HTTPClient) ListDeployments(namespace string) ([]models.Deployment, err
 }
 
 // GetDeploymentEvents fetches events for a specific deployment
-func (c *HTTPClient) GetDeploymentEvents(namespace, deploymentName string) ([]models.Event, error) {
+func (c *HTTPClient) GetDeploymentEvents(namespace, deploymentName string) ([]models.EventTimeEntry, error) {
 	url := fmt.Sprintf("%s/api/k8s/%s/events", c.baseURL, namespace)
 
 	resp, err := c.httpClient.Get(url)
@@ -175,24 +274,11 @@ func (c *HTTPClient) GetDeploymentEvents(namespace, deploymentName string) ([]mo
 		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	// Upstream returns []EventTimeEntry, convert to []Event
-	var eventEntries []models.EventTimeEntry
-	if err := json.NewDecoder(resp.Body).Decode(&eventEntries); err != nil {
+	var events []models.EventTimeEntry
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
 		return nil, fmt.Errorf("failed to decode events: %w", err)
 	}
 
-	// Convert EventTimeEntry to Event
-	events := make([]models.Event, 0, len(eventEntries))
-	for _, entry := range eventEntries {
-		events = append(events, models.Event{
-			Type:      entry.Type,
-			Reason:    entry.Reason,
-			Message:   entry.Message,
-			Timestamp: entry.LastObservedTime,
-			Count:     entry.Count,
-		})
-	}
-
 	return events, nil
 }
 
@@ -202,6 +288,37 @@ func (c *HTTPClient) DeleteDeployment(namespace, deploymentName string) error {
 	return fmt.Errorf("not implemented via upstream API")
 }
 
+// ScaleDeployment changes the replica count of a specific deployment
+func (c *HTTPClient) ScaleDeployment(namespace, deploymentName string, replicas int) error {
+	// This would need to call the upstream API's scale endpoint
+	return fmt.Errorf("not implemented via upstream API")
+}
+
+// RestartDeployment triggers a rolling restart of a specific deployment
+func (c *HTTPClient) RestartDeployment(namespace, deploymentName string) error {
+	return fmt.Errorf("not implemented via upstream API")
+}
+
+// PauseDeployment pauses rollouts of a specific deployment
+func (c *HTTPClient) PauseDeployment(namespace, deploymentName string) error {
+	return fmt.Errorf("not implemented via upstream API")
+}
+
+// ResumeDeployment resumes rollouts of a specific deployment
+func (c *HTTPClient) ResumeDeployment(namespace, deploymentName string) error {
+	return fmt.Errorf("not implemented via upstream API")
+}
+
+// GetDeploymentHistory fetches the rollout history of a specific deployment
+func (c *HTTPClient) GetDeploymentHistory(namespace, deploymentName string) ([]models.DeploymentRevision, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
+// RollbackDeployment rolls a specific deployment back to a previous revision
+func (c *HTTPClient) RollbackDeployment(namespace, deploymentName string, revision int64) error {
+	return fmt.Errorf("not implemented via upstream API")
+}
+
 // GetPodMetrics fetches resource metrics for all pods in a namespace
 func (c *HTTPClient) GetPodMetrics(namespace string) ([]models.PodMetrics, error) {
 	url := fmt.Sprintf("%s/api/k8s/%s/metrics", c.baseURL, namespace)
@@ -228,6 +345,11 @@ func (c *HTTPClient) GetPodMetrics(namespace string) ([]models.PodMetrics, error
 	return metrics, nil
 }
 
+// GetClusterCapacity fetches node capacity from the upstream API
+func (c *HTTPClient) GetClusterCapacity() (*models.ClusterCapacity, error) {
+	return nil, fmt.Errorf("not implemented via upstream API")
+}
+
 // GetResourceStats fetches statistics for a resource type
 func (c *HTTPClient) GetResourceStats(resourceType, namespace string) (*models.ResourceStats, error) {
 	// This would aggregate data from various endpoints
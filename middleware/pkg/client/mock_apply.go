@@ -0,0 +1,132 @@
+package client
+
+import (
+	"fmt"
+	"imperm-middleware/pkg/models"
+	"strings"
+)
+
+// ApplyManifest records the documents of a manifest against a mock environment
+func (m *MockClient) ApplyManifest(envName string, req models.ApplyManifestRequest) (*models.ApplyResult, error) {
+	var env *models.Environment
+	for i := range m.environments {
+		if m.environments[i].Name == envName {
+			env = &m.environments[i]
+		}
+	}
+	if env == nil {
+		return nil, fmt.Errorf("environment %s not found", envName)
+	}
+
+	docs := splitMockManifest(req.Manifest)
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("manifest contains no objects")
+	}
+
+	if m.applied == nil {
+		m.applied = make(map[string]string)
+	}
+
+	result := &models.ApplyResult{Environment: envName, Namespace: env.Namespace, DryRun: req.DryRun}
+	for i, doc := range docs {
+		obj := models.AppliedObject{
+			APIVersion: mockManifestField(doc, "apiVersion"),
+			Kind:       mockManifestField(doc, "kind"),
+			Name:       mockManifestName(doc),
+		}
+		switch {
+		case obj.APIVersion == "" || obj.Kind == "":
+			obj.Action = models.ApplyFailed
+			obj.Error = fmt.Sprintf("document %d: apiVersion and kind are required", i+1)
+		case obj.Name == "":
+			obj.Action = models.ApplyFailed
+			obj.Error = "metadata.name is required for server-side apply"
+		default:
+			key := env.Namespace + "/" + obj.Kind + "/" + obj.Name
+			live, exists := m.applied[key]
+			switch {
+			case !exists:
+				obj.Action = models.ApplyCreated
+			case live == doc:
+				obj.Action = models.ApplyUnchanged
+			default:
+				obj.Action = models.ApplyConfigured
+			}
+			if obj.Action != models.ApplyUnchanged {
+				obj.Diff = mockDiff(obj.Name, live, doc)
+			}
+			if !req.DryRun {
+				m.applied[key] = doc
+			}
+		}
+		result.Objects = append(result.Objects, obj)
+	}
+	return result, nil
+}
+
+// splitMockManifest splits multi-document YAML, dropping empty documents
+func splitMockManifest(manifest string) []string {
+	var docs []string
+	var current []string
+	flush := func() {
+		doc := strings.TrimSpace(strings.Join(current, "\n"))
+		if doc != "" {
+			docs = append(docs, doc+"\n")
+		}
+		current = nil
+	}
+	for _, line := range strings.Split(manifest, "\n") {
+		if strings.HasPrefix(line, "---") {
+			flush()
+			continue
+		}
+		current = append(current, line)
+	}
+	flush()
+	return docs
+}
+
+// mockManifestField returns a top-level scalar field of a YAML document
+func mockManifestField(doc, field string) string {
+	for _, line := range strings.Split(doc, "\n") {
+		if value, ok := strings.CutPrefix(line, field+":"); ok {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
+
+// mockManifestName returns metadata.name of a YAML document
+func mockManifestName(doc string) string {
+	inMetadata := false
+	for _, line := range strings.Split(doc, "\n") {
+		if strings.HasPrefix(line, "metadata:") {
+			inMetadata = true
+			continue
+		}
+		if inMetadata {
+			if line != "" && !strings.HasPrefix(line, " ") {
+				break
+			}
+			if value, ok := strings.CutPrefix(line, "  name:"); ok {
+				return strings.Trim(strings.TrimSpace(value), `"'`)
+			}
+		}
+	}
+	return ""
+}
+
+// mockDiff renders a whole-document replacement as a unified diff
+func mockDiff(name, from, to string) string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- live/%s\n+++ applied/%s\n", name, name)
+	if from != "" {
+		for _, line := range strings.Split(strings.TrimSuffix(from, "\n"), "\n") {
+			out.WriteString("-" + line + "\n")
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(to, "\n"), "\n") {
+		out.WriteString("+" + line + "\n")
+	}
+	return out.String()
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"fmt"
+	"imperm-middleware/pkg/models"
+)
+
+// What every mock node offers, and what its system pods already request
+const (
+	mockNodes             = 3
+	mockNodeCPUMillis     = 4000
+	mockNodeMemoryBytes   = 16 * 1024 * 1024 * 1024
+	mockNodePods          = 110
+	mockSystemCPUMillis   = 850
+	mockSystemMemoryBytes = 1024 * 1024 * 1024
+	mockSystemPods        = 8
+)
+
+func (m *MockClient) GetClusterCapacity() (*models.ClusterCapacity, error) {
+	return mockClusterCapacity(m.environments), nil
+}
+
+// mockClusterCapacity spreads the mock environments' pods over a few
+// identically sized nodes
+func mockClusterCapacity(environments []models.Environment) *models.ClusterCapacity {
+	capacity := &models.ClusterCapacity{}
+	index := make(map[string]int)
+	for i := 1; i <= mockNodes; i++ {
+		name := fmt.Sprintf("mock-node-%d", i)
+		index[name] = len(capacity.Nodes)
+		capacity.Nodes = append(capacity.Nodes, models.NodeCapacity{
+			Name:                   name,
+			AllocatableCPUMillis:   mockNodeCPUMillis,
+			AllocatableMemoryBytes: mockNodeMemoryBytes,
+			AllocatablePods:        mockNodePods,
+			RequestedCPUMillis:     mockSystemCPUMillis,
+			RequestedMemoryBytes:   mockSystemMemoryBytes,
+			RequestedPods:          mockSystemPods,
+		})
+	}
+
+	for _, env := range environments {
+		for _, pod := range env.Pods {
+			i, ok := index[pod.Node]
+			if !ok {
+				continue
+			}
+			node := &capacity.Nodes[i]
+			node.RequestedCPUMillis += mockMillis(pod.CPURequest)
+			node.RequestedMemoryBytes += mockBytes(pod.MemoryRequest)
+			node.RequestedPods++
+		}
+	}
+	return capacity
+}
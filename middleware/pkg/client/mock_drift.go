@@ -0,0 +1,67 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"imperm-middleware/pkg/models"
+)
+
+// mockDrift simulates a logger scaled with kubectl behind Terraform's back
+func mockDrift(now time.Time) map[string]models.DriftStatus {
+	return map[string]models.DriftStatus{
+		"staging-env-1": {
+			Environment: "staging-env-1",
+			Drifted:     true,
+			Resources:   []string{"module.environment.kubernetes_deployment.constant_logger[0]"},
+			CheckedAt:   now.Add(-3 * time.Minute),
+		},
+	}
+}
+
+// driftOf returns the simulated drift check of an environment, nil when never checked
+func (m *MockClient) driftOf(name string) *models.DriftStatus {
+	status, ok := m.drift[name]
+	if !ok {
+		return nil
+	}
+	return &status
+}
+
+// StartDriftChecks does nothing; the mock's drift is simulated up front
+func (m *MockClient) StartDriftChecks(interval time.Duration, concurrency int) {}
+
+// DriftResults returns the simulated drift check of every environment
+func (m *MockClient) DriftResults() ([]models.DriftStatus, error) {
+	results := make([]models.DriftStatus, 0, len(m.drift))
+	for _, status := range m.drift {
+		results = append(results, status)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Environment < results[j].Environment })
+	return results, nil
+}
+
+// CheckDrift returns the simulated drift of an environment, checked just now
+func (m *MockClient) CheckDrift(name string) (*models.DriftStatus, error) {
+	if !m.hasEnvironment(name) {
+		return nil, fmt.Errorf("environment %s not found", name)
+	}
+	status, ok := m.drift[name]
+	if !ok {
+		status = models.DriftStatus{Environment: name, Resources: []string{}}
+	}
+	status.CheckedAt = time.Now()
+	m.drift[name] = status
+	return &status, nil
+}
+
+// ReconcileEnvironment simulates re-applying an environment, clearing its drift
+func (m *MockClient) ReconcileEnvironment(name string) error {
+	if !m.hasEnvironment(name) {
+		return fmt.Errorf("environment %s not found", name)
+	}
+	m.drift[name] = models.DriftStatus{Environment: name, Resources: []string{}, CheckedAt: time.Now()}
+	m.recordEvent(name, "Normal", "ReconcileCompleted", "reconcile completed")
+	return nil
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"fmt"
+	"imperm-middleware/pkg/models"
+	"time"
+)
+
+// GetEnvironmentEvents builds a timeline from the simulated workloads and the
+// operations run through the mock
+func (m *MockClient) GetEnvironmentEvents(envName string, filter models.EventFilter) ([]models.TimelineEvent, error) {
+	events := append([]models.TimelineEvent{}, m.opEvents[envName]...)
+
+	for _, env := range m.environments {
+		if env.Name != envName {
+			continue
+		}
+
+		events = append(events, mockEvent(env.Age, "Normal", "Created", models.EventSourceImperm, "Environment", env.Name, "environment namespace created"))
+
+		for _, dep := range env.Deployments {
+			events = append(events, mockEvent(dep.Age, "Normal", "ScalingReplicaSet", models.EventSourceKubernetes, "Deployment", dep.Name,
+				fmt.Sprintf("Scaled up replica set %s-5002 to %d", dep.Name, dep.Replicas)))
+		}
+
+		for _, pod := range env.Pods {
+			events = append(events,
+				mockEvent(pod.Age, "Normal", "Scheduled", models.EventSourceKubernetes, "Pod", pod.Name,
+					fmt.Sprintf("Successfully assigned %s/%s to %s", pod.Namespace, pod.Name, pod.Node)),
+				mockEvent(pod.Age.Add(5*time.Second), "Normal", "Pulled", models.EventSourceKubernetes, "Pod", pod.Name,
+					"Container image already present on machine"),
+				mockEvent(pod.Age.Add(7*time.Second), "Normal", "Started", models.EventSourceKubernetes, "Pod", pod.Name,
+					"Started container"),
+			)
+			for _, container := range pod.Containers {
+				if container.Reason != "CrashLoopBackOff" {
+					continue
+				}
+				backOff := mockEvent(container.LastFinished, "Warning", "BackOff", models.EventSourceKubernetes, "Pod", pod.Name,
+					fmt.Sprintf("Back-off restarting failed container %s in pod %s", container.Name, pod.Name))
+				backOff.Count = container.RestartCount
+				events = append(events, backOff)
+			}
+		}
+		return models.FilterTimeline(events, filter), nil
+	}
+
+	// Destroyed environments keep the events of their operations
+	if len(events) == 0 {
+		return nil, fmt.Errorf("environment %s not found", envName)
+	}
+	return models.FilterTimeline(events, filter), nil
+}
+
+// recordEvent adds an imperm operation event to an environment's timeline
+func (m *MockClient) recordEvent(envName, eventType, reason, message string) {
+	if m.opEvents == nil {
+		m.opEvents = make(map[string][]models.TimelineEvent)
+	}
+	m.opEvents[envName] = append(m.opEvents[envName],
+		mockEvent(time.Now(), eventType, reason, models.EventSourceImperm, "Environment", envName, message))
+}
+
+func mockEvent(when time.Time, eventType, reason, source, kind, name, message string) models.TimelineEvent {
+	return models.TimelineEvent{
+		Time:    when,
+		Type:    eventType,
+		Reason:  reason,
+		Message: message,
+		Source:  source,
+		Kind:    kind,
+		Name:    name,
+		Count:   1,
+	}
+}
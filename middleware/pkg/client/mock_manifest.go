@@ -0,0 +1,265 @@
+package client
+
+import (
+	"fmt"
+	"imperm-middleware/pkg/models"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GetResourceManifest renders a mock object as YAML with a describe summary
+func (m *MockClient) GetResourceManifest(kind, namespace, name string, reveal bool) (*models.ResourceManifest, error) {
+	var (
+		apiVersion, objectKind string
+		created                time.Time
+		body                   strings.Builder
+		describe               models.ResourceDescription
+	)
+	labels := map[string]string{"app.kubernetes.io/managed-by": "imperm"}
+
+	switch kind {
+	case models.KindPod:
+		pod, err := m.findPod(namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		apiVersion, objectKind, created = "v1", "Pod", pod.Age
+		app := mockAppLabel(pod.Name)
+		labels["app"] = app
+		body.WriteString("spec:\n  containers:\n  - name: app\n    image: nginx:1.25\n    ports:\n    - containerPort: 8080\n      protocol: TCP\n")
+		fmt.Fprintf(&body, "  nodeName: %s\n", pod.Node)
+		fmt.Fprintf(&body, "status:\n  phase: %s\n  podIP: %s\n  qosClass: %s\n", pod.Phase, pod.PodIP, pod.QOSClass)
+
+		describe = models.ResourceDescription{
+			Fields: []models.DescribeField{
+				{Name: "Status", Value: pod.Status},
+				{Name: "Node", Value: pod.Node},
+				{Name: "IP", Value: pod.PodIP},
+				{Name: "QoS Class", Value: pod.QOSClass},
+			},
+			OwnerReferences: []models.OwnerReference{{Kind: "ReplicaSet", Name: app + "-5002", Controller: true}},
+			Containers: []models.ContainerDescription{{
+				Name:         "app",
+				Image:        "nginx:1.25",
+				Ports:        []string{"8080/TCP"},
+				State:        "Running",
+				Ready:        pod.Status == "Running",
+				RestartCount: pod.Restarts,
+				Requests:     "cpu=100m, memory=128Mi",
+				Limits:       "cpu=500m, memory=256Mi",
+				Mounts:       []string{"/etc/app from config (ro)"},
+			}},
+			Volumes: []models.VolumeDescription{{Name: "config", Type: "ConfigMap", Source: "app-config"}},
+		}
+		for _, condition := range []string{"Initialized", "Ready", "ContainersReady", "PodScheduled"} {
+			describe.Conditions = append(describe.Conditions, models.ResourceCondition{Type: condition, Status: "True", LastTransitionTime: pod.Age})
+		}
+
+	case models.KindDeployment:
+		dep, err := m.findDeployment(namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		apiVersion, objectKind, created = "apps/v1", "Deployment", dep.Age
+		labels["app"] = dep.Name
+		fmt.Fprintf(&body, "spec:\n  replicas: %d\n  paused: %t\n  selector:\n    matchLabels:\n      app: %s\n", dep.Replicas, dep.Paused, dep.Name)
+		fmt.Fprintf(&body, "  template:\n    spec:\n      containers:\n      - name: app\n        image: nginx:1.25\n")
+		fmt.Fprintf(&body, "status:\n  replicas: %d\n  updatedReplicas: %d\n  availableReplicas: %d\n", dep.Replicas, dep.UpToDate, dep.Available)
+
+		describe = models.ResourceDescription{
+			Fields: []models.DescribeField{
+				{Name: "Replicas", Value: fmt.Sprintf("%d desired | %d updated | %d total | %d available | %d unavailable",
+					dep.Replicas, dep.UpToDate, dep.Replicas, dep.Available, dep.Replicas-dep.Available)},
+				{Name: "Strategy", Value: "RollingUpdate"},
+				{Name: "Selector", Value: "app=" + dep.Name},
+				{Name: "Paused", Value: fmt.Sprintf("%t", dep.Paused)},
+			},
+			Conditions: []models.ResourceCondition{
+				{Type: "Available", Status: "True", Reason: "MinimumReplicasAvailable", Message: "Deployment has minimum availability.", LastTransitionTime: dep.Age},
+				{Type: "Progressing", Status: "True", Reason: "NewReplicaSetAvailable", LastTransitionTime: dep.Age},
+			},
+			Containers: []models.ContainerDescription{{Name: "app", Image: "nginx:1.25", Ports: []string{"8080/TCP"}}},
+		}
+
+	case models.KindService:
+		svc, err := m.GetService(namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		apiVersion, objectKind, created = "v1", "Service", svc.Age
+		fmt.Fprintf(&body, "spec:\n  type: %s\n  clusterIP: %s\n  ports:\n", svc.Type, svc.ClusterIP)
+		ports := make([]string, 0, len(svc.Ports))
+		for _, p := range svc.Ports {
+			fmt.Fprintf(&body, "  - name: %s\n    port: %d\n    protocol: %s\n    targetPort: %s\n", p.Name, p.Port, p.Protocol, p.TargetPort)
+			ports = append(ports, fmt.Sprintf("%d/%s → %s", p.Port, p.Protocol, p.TargetPort))
+		}
+		body.WriteString("  selector:\n" + mockYAMLMap(svc.Selector, "    "))
+
+		describe.Fields = []models.DescribeField{
+			{Name: "Type", Value: svc.Type},
+			{Name: "IP", Value: svc.ClusterIP},
+			{Name: "External IP", Value: svc.ExternalIP},
+			{Name: "Ports", Value: strings.Join(ports, ", ")},
+		}
+
+	case models.KindConfigMap:
+		cm, err := m.GetConfigMap(namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		apiVersion, objectKind, created = "v1", "ConfigMap", cm.Age
+		body.WriteString("data:\n" + mockYAMLMap(cm.Data, "  "))
+		for _, key := range mockSortedKeys(cm.Data) {
+			describe.Fields = append(describe.Fields, models.DescribeField{Name: key, Value: fmt.Sprintf("%d bytes", len(cm.Data[key]))})
+		}
+
+	case models.KindSecret:
+		secret, err := m.GetSecret(namespace, name, true)
+		if err != nil {
+			return nil, err
+		}
+		apiVersion, objectKind, created = "v1", "Secret", secret.Age
+		data := make(map[string]string, len(secret.Data))
+		describe.Fields = []models.DescribeField{{Name: "Type", Value: secret.Type}}
+		for _, key := range mockSortedKeys(secret.Data) {
+			data[key] = models.SecretMask
+			if reveal {
+				data[key] = secret.Data[key]
+			}
+			describe.Fields = append(describe.Fields, models.DescribeField{Name: key, Value: fmt.Sprintf("%d bytes", len(secret.Data[key]))})
+		}
+		fmt.Fprintf(&body, "type: %s\ndata:\n%s", secret.Type, mockYAMLMap(data, "  "))
+
+	case models.KindIngress:
+		ing, err := m.GetIngress(namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		apiVersion, objectKind, created = "networking.k8s.io/v1", "Ingress", ing.Age
+		fmt.Fprintf(&body, "spec:\n  ingressClassName: %s\n  rules:\n", ing.Class)
+		describe.Fields = []models.DescribeField{{Name: "Class", Value: ing.Class}, {Name: "Address", Value: ing.Address}}
+		for _, rule := range ing.Rules {
+			fmt.Fprintf(&body, "  - host: %s\n    http:\n      paths:\n      - path: %s\n        backend:\n          service:\n            name: %s\n            port:\n              number: %s\n",
+				rule.Host, rule.Path, rule.Service, rule.Port)
+			describe.Fields = append(describe.Fields, models.DescribeField{Name: "Rule", Value: fmt.Sprintf("%s%s → %s:%s", rule.Host, rule.Path, rule.Service, rule.Port)})
+		}
+
+	case models.KindJob:
+		job, err := m.GetJob(namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		apiVersion, objectKind, created = "batch/v1", "Job", job.Age
+		fmt.Fprintf(&body, "spec:\n  completions: 1\n  template:\n    spec:\n      restartPolicy: Never\n      containers:\n      - name: %s\n        image: busybox:1.36\n", job.Name)
+		fmt.Fprintf(&body, "status:\n  active: %d\n  succeeded: %d\n  failed: %d\n", job.Active, job.Succeeded, job.Failed)
+		describe = models.ResourceDescription{
+			Fields: []models.DescribeField{
+				{Name: "Completions", Value: job.Completions},
+				{Name: "Pods Statuses", Value: fmt.Sprintf("%d Active / %d Succeeded / %d Failed", job.Active, job.Succeeded, job.Failed)},
+			},
+			Containers: []models.ContainerDescription{{Name: job.Name, Image: "busybox:1.36"}},
+		}
+		if job.Status == "Complete" || job.Status == "Failed" {
+			describe.Conditions = []models.ResourceCondition{{Type: job.Status, Status: "True", LastTransitionTime: job.Age}}
+		}
+
+	case models.KindStatefulSet:
+		sts, err := m.GetStatefulSet(namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		apiVersion, objectKind, created = "apps/v1", "StatefulSet", sts.Age
+		fmt.Fprintf(&body, "spec:\n  replicas: %d\n  serviceName: %s\n  template:\n    spec:\n      containers:\n      - name: %s\n        image: postgres:16\n", sts.Replicas, sts.ServiceName, sts.Name)
+		fmt.Fprintf(&body, "status:\n  replicas: %d\n  readyReplicas: %d\n", sts.Replicas, sts.ReadyReplicas)
+		describe = models.ResourceDescription{
+			Fields: []models.DescribeField{
+				{Name: "Replicas", Value: fmt.Sprintf("%d desired | %d total | %d ready", sts.Replicas, sts.Replicas, sts.ReadyReplicas)},
+				{Name: "Service", Value: sts.ServiceName},
+			},
+			Containers: []models.ContainerDescription{{Name: sts.Name, Image: "postgres:16", Ports: []string{"5432/TCP"}}},
+			Volumes:    []models.VolumeDescription{{Name: "data", Type: "VolumeClaimTemplate", Source: "storage=1Gi"}},
+		}
+
+	case models.KindNamespace:
+		var env *models.Environment
+		for i := range m.environments {
+			if m.environments[i].Namespace == name {
+				env = &m.environments[i]
+			}
+		}
+		if env == nil {
+			return nil, fmt.Errorf("namespace %s not found", name)
+		}
+		apiVersion, objectKind, created = "v1", "Namespace", env.Age
+		namespace = ""
+		body.WriteString("status:\n  phase: Active\n")
+		describe.Fields = []models.DescribeField{{Name: "Status", Value: "Active"}}
+
+	default:
+		return nil, fmt.Errorf("unsupported resource kind %q", kind)
+	}
+
+	var manifest strings.Builder
+	fmt.Fprintf(&manifest, "apiVersion: %s\nkind: %s\nmetadata:\n  name: %s\n", apiVersion, objectKind, name)
+	if namespace != "" {
+		fmt.Fprintf(&manifest, "  namespace: %s\n", namespace)
+	}
+	fmt.Fprintf(&manifest, "  creationTimestamp: %q\n  labels:\n%s", created.UTC().Format(time.RFC3339), mockYAMLMap(labels, "    "))
+	manifest.WriteString(body.String())
+
+	describe.Labels = labels
+	return &models.ResourceManifest{
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+		YAML:      manifest.String(),
+		Describe:  describe,
+	}, nil
+}
+
+// findPod returns a mock pod by namespace and name
+func (m *MockClient) findPod(namespace, podName string) (*models.Pod, error) {
+	for _, env := range m.environments {
+		for i := range env.Pods {
+			if env.Pods[i].Namespace == namespace && env.Pods[i].Name == podName {
+				return &env.Pods[i], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("pod %s not found in namespace %s", podName, namespace)
+}
+
+// mockAppLabel derives the owning deployment name from a generated pod name
+func mockAppLabel(podName string) string {
+	if i := strings.LastIndex(podName, "-"); i > 0 {
+		return podName[:i]
+	}
+	return podName
+}
+
+// mockYAMLMap renders a string map as YAML entries, using block scalars for multi-line values
+func mockYAMLMap(values map[string]string, indent string) string {
+	var out strings.Builder
+	for _, key := range mockSortedKeys(values) {
+		value := values[key]
+		if strings.Contains(value, "\n") {
+			out.WriteString(indent + key + ": |\n")
+			for _, line := range strings.Split(strings.TrimRight(value, "\n"), "\n") {
+				out.WriteString(indent + "  " + line + "\n")
+			}
+			continue
+		}
+		fmt.Fprintf(&out, "%s%s: %q\n", indent, key, value)
+	}
+	return out.String()
+}
+
+func mockSortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"fmt"
+	"hash/fnv"
+	"imperm-middleware/pkg/models"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limits every mock pod runs with
+const (
+	mockCPULimitMillis   = 500
+	mockMemoryLimitBytes = 512 * 1024 * 1024
+)
+
+func (m *MockClient) GetPodMetrics(namespace string) ([]models.PodMetrics, error) {
+	now := time.Now()
+
+	// Return mock metrics for pods in the namespace
+	var metrics []models.PodMetrics
+	for _, env := range m.environments {
+		if namespace == "" || env.Namespace == namespace {
+			for _, pod := range env.Pods {
+				cpu := mockUsage(pod.Name, parseMockQuantity(pod.CPU, "m", 1), now)
+				memory := mockUsage(pod.Name+"/memory", parseMockQuantity(pod.Memory, "Mi", 1024*1024), now)
+				metrics = append(metrics, models.PodMetrics{
+					Name:                 pod.Name,
+					Namespace:            pod.Namespace,
+					CPULimit:             fmt.Sprintf("%dm", mockCPULimitMillis),
+					CPUUsed:              fmt.Sprintf("%dm", cpu),
+					CPUUsedPercentage:    float64(cpu) / mockCPULimitMillis * 100,
+					MemoryLimit:          fmt.Sprintf("%dMi", mockMemoryLimitBytes/(1024*1024)),
+					MemoryUsed:           fmt.Sprintf("%dMi", memory/(1024*1024)),
+					MemoryUsedPercentage: float64(memory) / mockMemoryLimitBytes * 100,
+					CPUUsedMillis:        cpu,
+					CPULimitMillis:       mockCPULimitMillis,
+					MemoryUsedBytes:      memory,
+					MemoryLimitBytes:     mockMemoryLimitBytes,
+				})
+			}
+		}
+	}
+	return metrics, nil
+}
+
+// mockUsage lets a pod's usage drift around its base value over a few minutes,
+// each pod out of phase with the others, so that sampled history has some shape
+func mockUsage(seed string, base int64, now time.Time) int64 {
+	h := fnv.New32a()
+	h.Write([]byte(seed))
+	phase := float64(h.Sum32()%360) * math.Pi / 180
+
+	wave := math.Sin(float64(now.Unix())/90 + phase)
+	return int64(float64(base) * (1 + 0.3*wave))
+}
+
+// parseMockQuantity reads the simple quantities the mock data uses, e.g. "150m" or "256Mi"
+func parseMockQuantity(value, suffix string, scale int64) int64 {
+	n, err := strconv.ParseInt(strings.TrimSuffix(value, suffix), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n * scale
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"fmt"
+	"imperm-middleware/pkg/models"
+	"strconv"
+	"strings"
+)
+
+// mockQuotaUsage adds up the requests and limits of an environment's pods
+// against the quota its options ask for, like the quota controller would
+func mockQuotaUsage(env models.Environment, options *models.DeploymentOptions) []models.QuotaUsage {
+	if options == nil {
+		return nil
+	}
+	quota := options.Quota()
+	if quota.IsEmpty() {
+		return nil
+	}
+
+	var cpuRequests, cpuLimits, memoryRequests, memoryLimits int64
+	for _, pod := range env.Pods {
+		cpuRequests += mockMillis(pod.CPURequest)
+		cpuLimits += mockMillis(pod.CPULimit)
+		memoryRequests += mockBytes(pod.MemoryRequest)
+		memoryLimits += mockBytes(pod.MemoryLimit)
+	}
+
+	var usages []models.QuotaUsage
+	add := func(resource, hard string, used, hardValue int64, format func(int64) string) {
+		if hard == "" {
+			return
+		}
+		usage := models.QuotaUsage{Resource: resource, Used: format(used), Hard: hard}
+		if hardValue > 0 {
+			usage.Percentage = float64(used) / float64(hardValue) * 100
+		}
+		usages = append(usages, usage)
+	}
+	formatMillis := func(v int64) string { return fmt.Sprintf("%dm", v) }
+	formatBytes := func(v int64) string { return fmt.Sprintf("%dMi", v/(1024*1024)) }
+	formatCount := func(v int64) string { return strconv.FormatInt(v, 10) }
+
+	add("limits.cpu", quota.CPULimits, cpuLimits, mockMillis(quota.CPULimits), formatMillis)
+	add("limits.memory", quota.MemoryLimits, memoryLimits, mockBytes(quota.MemoryLimits), formatBytes)
+	add("pods", quota.Pods, int64(len(env.Pods)), mockMillis(quota.Pods)/1000, formatCount)
+	add("requests.cpu", quota.CPURequests, cpuRequests, mockMillis(quota.CPURequests), formatMillis)
+	add("requests.memory", quota.MemoryRequests, memoryRequests, mockBytes(quota.MemoryRequests), formatBytes)
+	return usages
+}
+
+// mockMillis reads a CPU quantity such as "250m" or "2" as millicores
+func mockMillis(value string) int64 {
+	if strings.HasSuffix(value, "m") {
+		return parseMockQuantity(value, "m", 1)
+	}
+	return parseMockQuantity(value, "", 1000)
+}
+
+// mockBytes reads a memory quantity such as "256Mi" or "4Gi" as bytes
+func mockBytes(value string) int64 {
+	if strings.HasSuffix(value, "Gi") {
+		return parseMockQuantity(value, "Gi", 1024*1024*1024)
+	}
+	return parseMockQuantity(value, "Mi", 1024*1024)
+}
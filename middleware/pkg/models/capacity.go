@@ -0,0 +1,50 @@
+package models
+
+// ClusterCapacity is what the cluster's schedulable nodes offer and what the
+// pods already scheduled on them have requested
+type ClusterCapacity struct {
+	Nodes []NodeCapacity `json:"nodes"`
+}
+
+// NodeCapacity is the allocatable capacity of one schedulable node and the
+// requests of the pods running on it
+type NodeCapacity struct {
+	Name                   string `json:"name"`
+	AllocatableCPUMillis   int64  `json:"allocatableCpuMillis"`
+	AllocatableMemoryBytes int64  `json:"allocatableMemoryBytes"`
+	AllocatablePods        int64  `json:"allocatablePods"`
+	RequestedCPUMillis     int64  `json:"requestedCpuMillis"`
+	RequestedMemoryBytes   int64  `json:"requestedMemoryBytes"`
+	RequestedPods          int64  `json:"requestedPods"`
+}
+
+// CapacityEstimate is whether an environment's replicas would fit in the
+// cluster and what they would cost
+type CapacityEstimate struct {
+	Fits    bool     `json:"fits"`
+	Reasons []string `json:"reasons"` // Why the replicas wouldn't fit, empty when they do
+
+	// What the new environment would reserve
+	RequestedCPUMillis   int64 `json:"requestedCpuMillis"`
+	RequestedMemoryBytes int64 `json:"requestedMemoryBytes"`
+	RequestedPods        int64 `json:"requestedPods"`
+	LimitCPUMillis       int64 `json:"limitCpuMillis"`
+	LimitMemoryBytes     int64 `json:"limitMemoryBytes"`
+
+	// What the cluster's schedulable nodes have left
+	Nodes                int   `json:"nodes"`
+	AvailableCPUMillis   int64 `json:"availableCpuMillis"`
+	AvailableMemoryBytes int64 `json:"availableMemoryBytes"`
+	AvailablePods        int64 `json:"availablePods"`
+
+	Cost CostEstimate `json:"cost"`
+}
+
+// CostEstimate prices an environment's requests at the server's configured rates
+type CostEstimate struct {
+	Currency    string  `json:"currency"`
+	PerCPUMonth float64 `json:"perCpuMonth"` // Rate for one CPU requested for a month
+	PerGiBMonth float64 `json:"perGibMonth"` // Rate for one GiB of memory requested for a month
+	Monthly     float64 `json:"monthly"`
+	Hourly      float64 `json:"hourly"`
+}
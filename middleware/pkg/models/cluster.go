@@ -0,0 +1,8 @@
+package models
+
+// Cluster is one of the Kubernetes clusters an imperm server manages
+type Cluster struct {
+	Name    string `json:"name"`
+	Context string `json:"context"` // Kubeconfig context, empty for the current one or in-cluster config
+	Default bool   `json:"default"` // Whether requests without a cluster parameter go to this cluster
+}
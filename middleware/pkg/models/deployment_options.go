@@ -1,5 +1,7 @@
 package models
 
+import "strings"
+
 // DeploymentOptions contains configuration for creating environments
 type DeploymentOptions struct {
 	Name      string            `json:"name"`
@@ -10,3 +12,138 @@ type DeploymentOptions struct {
 func (d *DeploymentOptions) HasVariables() bool {
 	return len(d.Variables) > 0
 }
+
+// CloneEnvironmentRequest names the new environment of a clone and the
+// variables that should differ from the source environment
+type CloneEnvironmentRequest struct {
+	Name      string            `json:"name"`
+	Variables map[string]string `json:"variables"` // Overrides; an empty value removes the variable
+}
+
+// Clone copies the options for a new environment, applying overrides on top
+// of the copied variables
+func (d *DeploymentOptions) Clone(name string, overrides map[string]string) *DeploymentOptions {
+	clone := &DeploymentOptions{
+		Name:      name,
+		Variables: make(map[string]string, len(d.Variables)+len(overrides)),
+	}
+	for key, value := range d.Variables {
+		clone.Variables[key] = value
+	}
+	for key, value := range overrides {
+		if value == "" {
+			delete(clone.Variables, key)
+			continue
+		}
+		clone.Variables[key] = value
+	}
+	return clone
+}
+
+// Variables that size an environment's ResourceQuota and LimitRange. They are
+// regular Terraform variables of the namespace module, so they are cloned and
+// overridden like any other; unset ones leave the resource unlimited.
+const (
+	VarQuotaCPURequests    = "quota_cpu_requests"
+	VarQuotaMemoryRequests = "quota_memory_requests"
+	VarQuotaCPULimits      = "quota_cpu_limits"
+	VarQuotaMemoryLimits   = "quota_memory_limits"
+	VarQuotaPods           = "quota_pods"
+
+	VarLimitDefaultCPURequest    = "limit_default_cpu_request"
+	VarLimitDefaultMemoryRequest = "limit_default_memory_request"
+	VarLimitDefaultCPU           = "limit_default_cpu"
+	VarLimitDefaultMemory        = "limit_default_memory"
+)
+
+// guardrailVariables are the variables that only size the quota and limit range
+var guardrailVariables = map[string]bool{
+	VarQuotaCPURequests:          true,
+	VarQuotaMemoryRequests:       true,
+	VarQuotaCPULimits:            true,
+	VarQuotaMemoryLimits:         true,
+	VarQuotaPods:                 true,
+	VarLimitDefaultCPURequest:    true,
+	VarLimitDefaultMemoryRequest: true,
+	VarLimitDefaultCPU:           true,
+	VarLimitDefaultMemory:        true,
+}
+
+// HasWorkloadVariables reports whether any variable asks for something to run
+// in the environment, rather than only sizing its quota and limit range
+func (d *DeploymentOptions) HasWorkloadVariables() bool {
+	for key := range d.Variables {
+		if !guardrailVariables[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// ResourceQuota is the hard cap on what an environment's namespace may use, as quantities like "2" or "4Gi"
+type ResourceQuota struct {
+	CPURequests    string `json:"cpuRequests,omitempty"`
+	MemoryRequests string `json:"memoryRequests,omitempty"`
+	CPULimits      string `json:"cpuLimits,omitempty"`
+	MemoryLimits   string `json:"memoryLimits,omitempty"`
+	Pods           string `json:"pods,omitempty"`
+}
+
+// IsEmpty reports whether the quota limits nothing
+func (q ResourceQuota) IsEmpty() bool {
+	return q == ResourceQuota{}
+}
+
+// LimitRange holds the requests and limits given to containers that declare none
+type LimitRange struct {
+	DefaultCPURequest    string `json:"defaultCpuRequest,omitempty"`
+	DefaultMemoryRequest string `json:"defaultMemoryRequest,omitempty"`
+	DefaultCPU           string `json:"defaultCpu,omitempty"`
+	DefaultMemory        string `json:"defaultMemory,omitempty"`
+}
+
+// IsEmpty reports whether the limit range sets no defaults
+func (l LimitRange) IsEmpty() bool {
+	return l == LimitRange{}
+}
+
+// Quota returns the resource quota the options ask for
+func (d *DeploymentOptions) Quota() ResourceQuota {
+	return ResourceQuota{
+		CPURequests:    d.Variable(VarQuotaCPURequests),
+		MemoryRequests: d.Variable(VarQuotaMemoryRequests),
+		CPULimits:      d.Variable(VarQuotaCPULimits),
+		MemoryLimits:   d.Variable(VarQuotaMemoryLimits),
+		Pods:           d.Variable(VarQuotaPods),
+	}
+}
+
+// LimitRange returns the container defaults the options ask for
+func (d *DeploymentOptions) LimitRange() LimitRange {
+	return LimitRange{
+		DefaultCPURequest:    d.Variable(VarLimitDefaultCPURequest),
+		DefaultMemoryRequest: d.Variable(VarLimitDefaultMemoryRequest),
+		DefaultCPU:           d.Variable(VarLimitDefaultCPU),
+		DefaultMemory:        d.Variable(VarLimitDefaultMemory),
+	}
+}
+
+// Variable returns a variable's value without any HCL string quotes
+func (d *DeploymentOptions) Variable(key string) string {
+	return strings.Trim(strings.TrimSpace(d.Variables[key]), `"`)
+}
+
+// ApplyDefaults sets every default variable the options don't set themselves
+func (d *DeploymentOptions) ApplyDefaults(defaults map[string]string) {
+	if len(defaults) == 0 {
+		return
+	}
+	if d.Variables == nil {
+		d.Variables = make(map[string]string, len(defaults))
+	}
+	for key, value := range defaults {
+		if _, ok := d.Variables[key]; !ok {
+			d.Variables[key] = value
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// DriftStatus is the outcome of the last drift check of an environment,
+// comparing its Terraform state with what is actually in the cluster
+type DriftStatus struct {
+	Environment string    `json:"environment"`
+	Drifted     bool      `json:"drifted"`
+	Resources   []string  `json:"resources"` // Addresses of the resources changed outside Terraform
+	CheckedAt   time.Time `json:"checked_at"`
+	Error       string    `json:"error,omitempty"` // Why the check failed, if it did
+}
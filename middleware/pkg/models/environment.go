@@ -1,27 +1,90 @@
 package models
 
-import "time"
+import (
+	"sort"
+	"strings"
+	"time"
+)
 
 // Environment represents a Kubernetes environment
 type Environment struct {
 	Name        string
+	Cluster     string // Name of the cluster the environment runs in
 	Namespace   string
 	Status      string
 	Age         time.Time
 	Pods        []Pod
 	Deployments []Deployment
+	Health      EnvironmentHealth
+	Quota       []QuotaUsage // Usage against the namespace's resource quotas, empty without one
+	Managed     bool         // Whether the namespace matches the server's discovery selector
+	Drift       *DriftStatus // Last drift check, nil when never checked
+
+	Interrupted *InterruptedOperation // Operation cut short by a server restart, nil when there is none
+}
+
+// Health statuses of an environment
+const (
+	HealthHealthy      = "Healthy"
+	HealthProvisioning = "Provisioning" // Workloads are still starting up
+	HealthDegraded     = "Degraded"     // Running, but with problems worth a look
+	HealthFailing      = "Failing"      // Workloads can't run
+)
+
+// EnvironmentHealth rolls up the state of an environment's workloads
+type EnvironmentHealth struct {
+	Status  string   `json:"status"`
+	Reasons []string `json:"reasons"` // Why the environment isn't healthy, most severe first
+}
+
+// QuotaUsage is how much of one quota-limited resource an environment uses
+type QuotaUsage struct {
+	Resource   string  `json:"resource"` // Quota resource name, e.g. "requests.cpu" or "pods"
+	Used       string  `json:"used"`
+	Hard       string  `json:"hard"`
+	Percentage float64 `json:"percentage"`
 }
 
 // Pod represents a Kubernetes pod
 type Pod struct {
 	Name      string
 	Namespace string
-	Status    string
+	Status    string // Effective status as kubectl shows it, e.g. "CrashLoopBackOff" or "Init:0/1"
+	Phase     string // Pod phase: Pending, Running, Succeeded, Failed or Unknown
 	Ready     string
 	Restarts  int
 	Age       time.Time
-	CPU       string // e.g., "100m", "1.5"
-	Memory    string // e.g., "256Mi", "1.5Gi"
+	CPU       string // Usage, or requests without metrics-server, e.g. "100m"
+	Memory    string // Usage, or requests without metrics-server, e.g. "256Mi"
+
+	// Effective requests and limits, including init containers and pod overhead; empty when unset
+	CPURequest    string
+	CPULimit      string
+	MemoryRequest string
+	MemoryLimit   string
+
+	Node       string
+	PodIP      string
+	QOSClass   string
+	Owner      string // Controlling owner as Kind/Name, e.g. "ReplicaSet/app-5d8f"
+	Containers []PodContainer
+}
+
+// PodContainer is the state of one container of a pod
+type PodContainer struct {
+	Name         string
+	Image        string
+	Init         bool
+	Ready        bool
+	State        string // Running, Waiting or Terminated
+	Reason       string // Waiting or terminated reason, e.g. "CrashLoopBackOff"
+	Message      string
+	RestartCount int
+
+	// Previous termination, set once the container has restarted
+	LastReason   string // e.g. "OOMKilled", "Error"
+	LastExitCode *int
+	LastFinished time.Time
 }
 
 // Deployment represents a Kubernetes deployment
@@ -29,9 +92,26 @@ type Deployment struct {
 	Name      string
 	Namespace string
 	Ready     string
+	Replicas  int // Desired replica count from the deployment spec
 	UpToDate  int
 	Available int
 	Age       time.Time
+
+	// Rollout state
+	Paused          bool
+	Revision        int64 // Current rollout revision
+	RolloutComplete bool  // All replicas updated and available for the latest spec
+}
+
+// DeploymentRevision represents one entry in a deployment's rollout history
+type DeploymentRevision struct {
+	Revision    int64     `json:"revision"`
+	ReplicaSet  string    `json:"replicaSet"`
+	Images      []string  `json:"images"`
+	ChangeCause string    `json:"changeCause"`
+	Replicas    int       `json:"replicas"`
+	Current     bool      `json:"current"`
+	Created     time.Time `json:"created"`
 }
 
 // EnvironmentHistory represents a historical environment launch
@@ -42,15 +122,6 @@ type EnvironmentHistory struct {
 	WithOptions bool
 }
 
-// Event represents a Kubernetes event
-type Event struct {
-	Type      string    // Normal, Warning
-	Reason    string    // e.g., "Pulled", "Created", "Started"
-	Message   string    // Full event message
-	Timestamp time.Time // When the event occurred
-	Count     int       // Number of times this event occurred
-}
-
 // EventTimeEntry represents a Kubernetes event with time tracking
 type EventTimeEntry struct {
 	Type              string    `json:"type"`
@@ -62,15 +133,95 @@ type EventTimeEntry struct {
 	LastObservedTime  time.Time `json:"lastObservedTime"`
 }
 
+// Sources of environment timeline events
+const (
+	EventSourceKubernetes = "kubernetes" // Events recorded in the environment's namespace
+	EventSourceImperm     = "imperm"     // imperm's own operations on the environment
+)
+
+// TimelineEvent is one entry of an environment's event timeline
+type TimelineEvent struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"` // Normal or Warning
+	Reason  string    `json:"reason"`
+	Message string    `json:"message"`
+	Source  string    `json:"source"` // kubernetes or imperm
+	Kind    string    `json:"kind"`   // Involved object kind; Environment for imperm operations
+	Name    string    `json:"name"`   // Involved object name
+	Count   int       `json:"count"`
+}
+
+// EnvironmentFilter chooses which namespaces are listed as environments
+type EnvironmentFilter struct {
+	Unmanaged bool `json:"unmanaged"` // Also list namespaces the discovery selector doesn't match
+}
+
+// EventFilter narrows an environment timeline; empty fields match everything
+type EventFilter struct {
+	Type string `json:"type"` // Normal or Warning
+	Kind string `json:"kind"` // Involved object kind
+}
+
+// Matches reports whether an event passes the filter
+func (f EventFilter) Matches(event TimelineEvent) bool {
+	if f.Type != "" && !strings.EqualFold(f.Type, event.Type) {
+		return false
+	}
+	if f.Kind != "" && !strings.EqualFold(f.Kind, event.Kind) {
+		return false
+	}
+	return true
+}
+
+// FilterTimeline keeps the events matching the filter, newest first
+func FilterTimeline(events []TimelineEvent, filter EventFilter) []TimelineEvent {
+	timeline := make([]TimelineEvent, 0, len(events))
+	for _, event := range events {
+		if filter.Matches(event) {
+			timeline = append(timeline, event)
+		}
+	}
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Time.After(timeline[j].Time)
+	})
+	return timeline
+}
+
 // PodMetrics represents resource usage metrics for a pod
 type PodMetrics struct {
 	Name                 string  `json:"name"`
+	Namespace            string  `json:"namespace"`
 	CPULimit             string  `json:"cpuLimit"`
 	CPUUsed              string  `json:"cpuUsed"`
 	CPUUsedPercentage    float64 `json:"cpuUsedPercentage"`
 	MemoryLimit          string  `json:"memoryLimit"`
 	MemoryUsed           string  `json:"memoryUsed"`
 	MemoryUsedPercentage float64 `json:"memoryUsedPercentage"`
+
+	// Raw values behind the formatted ones, for aggregation
+	CPUUsedMillis    int64 `json:"cpuUsedMillis"`
+	CPULimitMillis   int64 `json:"cpuLimitMillis"`
+	MemoryUsedBytes  int64 `json:"memoryUsedBytes"`
+	MemoryLimitBytes int64 `json:"memoryLimitBytes"`
+}
+
+// MetricsSample is the CPU and memory usage of a pod or namespace at one point in time
+type MetricsSample struct {
+	Time                 time.Time `json:"time"`
+	CPUUsedMillis        int64     `json:"cpuUsedMillis"`
+	CPULimitMillis       int64     `json:"cpuLimitMillis"`
+	CPUUsedPercentage    float64   `json:"cpuUsedPercentage"` // Zero when no limit is set
+	MemoryUsedBytes      int64     `json:"memoryUsedBytes"`
+	MemoryLimitBytes     int64     `json:"memoryLimitBytes"`
+	MemoryUsedPercentage float64   `json:"memoryUsedPercentage"` // Zero when no limit is set
+}
+
+// MetricsHistory is the sampled usage of a pod, or of a whole namespace when Pod is empty, oldest first
+type MetricsHistory struct {
+	Namespace string          `json:"namespace"`
+	Pod       string          `json:"pod,omitempty"`
+	Interval  int             `json:"intervalSeconds"` // Seconds between samples
+	Samples   []MetricsSample `json:"samples"`
 }
 
 // ResourceStats represents statistics for a resource
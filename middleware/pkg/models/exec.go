@@ -0,0 +1,26 @@
+package models
+
+// ExecRequest describes a command to run inside a pod container
+type ExecRequest struct {
+	Namespace string   `json:"namespace"`
+	Pod       string   `json:"pod"`
+	Container string   `json:"container"` // Empty selects the pod's first container
+	Command   []string `json:"command"`
+	TTY       bool     `json:"tty"`
+}
+
+// TerminalSize is the size of the local terminal attached to an exec session
+type TerminalSize struct {
+	Width  uint16 `json:"width"`
+	Height uint16 `json:"height"`
+}
+
+// Exec stream channels. Every WebSocket frame on /api/pods/exec starts with
+// one of these bytes followed by the payload.
+const (
+	ExecChannelStdin  byte = 0
+	ExecChannelStdout byte = 1
+	ExecChannelStderr byte = 2
+	ExecChannelStatus byte = 3 // Sent once when the command exits; payload is the error text, empty on success
+	ExecChannelResize byte = 4 // Payload is a JSON encoded TerminalSize
+)
@@ -0,0 +1,82 @@
+package models
+
+import "time"
+
+// Resource kinds accepted by the manifest endpoint
+const (
+	KindPod         = "pod"
+	KindDeployment  = "deployment"
+	KindService     = "service"
+	KindConfigMap   = "configmap"
+	KindSecret      = "secret"
+	KindIngress     = "ingress"
+	KindJob         = "job"
+	KindStatefulSet = "statefulset"
+	KindNamespace   = "namespace"
+)
+
+// ResourceManifest is the live object of a resource as YAML, with managed
+// fields stripped and secret values masked, plus a describe-style summary
+type ResourceManifest struct {
+	Kind      string              `json:"kind"`
+	Namespace string              `json:"namespace"`
+	Name      string              `json:"name"`
+	YAML      string              `json:"yaml"`
+	Describe  ResourceDescription `json:"describe"`
+}
+
+// ResourceDescription holds the sections kubectl describe would print
+type ResourceDescription struct {
+	Fields          []DescribeField        `json:"fields"` // Kind-specific fields in display order
+	Labels          map[string]string      `json:"labels"`
+	Annotations     map[string]string      `json:"annotations"`
+	OwnerReferences []OwnerReference       `json:"ownerReferences"`
+	Conditions      []ResourceCondition    `json:"conditions"`
+	Containers      []ContainerDescription `json:"containers"`
+	Volumes         []VolumeDescription    `json:"volumes"`
+}
+
+// DescribeField is a single "Name: value" line
+type DescribeField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// OwnerReference points at the object that owns a resource
+type OwnerReference struct {
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Controller bool   `json:"controller"`
+}
+
+// ResourceCondition is one entry of an object's status conditions
+type ResourceCondition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason"`
+	Message            string    `json:"message"`
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+}
+
+// ContainerDescription describes a container of a pod or pod template.
+// State fields are only set for running pods.
+type ContainerDescription struct {
+	Name         string   `json:"name"`
+	Image        string   `json:"image"`
+	Init         bool     `json:"init"`
+	Ports        []string `json:"ports"` // e.g. "8080/TCP"
+	State        string   `json:"state"` // Running, Waiting, Terminated
+	Reason       string   `json:"reason"`
+	Ready        bool     `json:"ready"`
+	RestartCount int      `json:"restartCount"`
+	Requests     string   `json:"requests"`
+	Limits       string   `json:"limits"`
+	Mounts       []string `json:"mounts"` // "path from volume (ro)"
+}
+
+// VolumeDescription describes a volume and its source
+type VolumeDescription struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`   // e.g. ConfigMap, Secret, EmptyDir, PersistentVolumeClaim
+	Source string `json:"source"` // Name of the referenced object, if any
+}
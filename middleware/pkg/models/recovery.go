@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// InterruptedOperation is an operation on an environment that never finished
+// because the server stopped in the middle of it, found on startup
+type InterruptedOperation struct {
+	Environment  string    `json:"environment"`
+	Operation    string    `json:"operation"`       // create, destroy, adopt, ...; "unknown" when there is no record of it
+	Phase        string    `json:"phase,omitempty"` // Step it was in, e.g. "apply"
+	StartedAt    time.Time `json:"started_at"`
+	DetectedAt   time.Time `json:"detected_at"`
+	StaleLock    bool      `json:"stale_lock"`    // Terraform's state lock was left behind
+	PartialState bool      `json:"partial_state"` // Terraform stopped while changing resources, so the state may be incomplete
+}
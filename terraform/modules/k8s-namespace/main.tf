@@ -11,10 +11,7 @@ resource "kubernetes_namespace" "environment" {
   metadata {
     name = var.namespace_name
 
-    labels = {
-      managed-by  = "imperm"
-      environment = var.namespace_name
-    }
+    labels = merge({ environment = var.namespace_name }, var.namespace_labels)
 
     annotations = {
       created-at = timestamp()
@@ -27,3 +27,13 @@ output "json_logger_created" {
   description = "Whether JSON logger was created"
   value       = var.json_logger > 0
 }
+
+output "resource_quota_created" {
+  description = "Whether a resource quota was created"
+  value       = length(kubernetes_resource_quota.environment) > 0
+}
+
+output "limit_range_created" {
+  description = "Whether a limit range was created"
+  value       = length(kubernetes_limit_range.environment) > 0
+}
@@ -0,0 +1,64 @@
+# Guardrails keeping an environment from eating the cluster. Empty variables
+# are left out; without any the objects are not created at all.
+
+locals {
+  quota_hard = {
+    for name, value in {
+      "requests.cpu"    = var.quota_cpu_requests
+      "requests.memory" = var.quota_memory_requests
+      "limits.cpu"      = var.quota_cpu_limits
+      "limits.memory"   = var.quota_memory_limits
+      "pods"            = var.quota_pods
+    } : name => value if value != ""
+  }
+
+  limit_default = {
+    for name, value in {
+      cpu    = var.limit_default_cpu
+      memory = var.limit_default_memory
+    } : name => value if value != ""
+  }
+
+  limit_default_request = {
+    for name, value in {
+      cpu    = var.limit_default_cpu_request
+      memory = var.limit_default_memory_request
+    } : name => value if value != ""
+  }
+}
+
+resource "kubernetes_resource_quota" "environment" {
+  count = length(local.quota_hard) > 0 ? 1 : 0
+
+  metadata {
+    name      = "imperm-quota"
+    namespace = kubernetes_namespace.environment.metadata[0].name
+    labels = {
+      managed-by = "imperm"
+    }
+  }
+
+  spec {
+    hard = local.quota_hard
+  }
+}
+
+resource "kubernetes_limit_range" "environment" {
+  count = length(local.limit_default) + length(local.limit_default_request) > 0 ? 1 : 0
+
+  metadata {
+    name      = "imperm-limits"
+    namespace = kubernetes_namespace.environment.metadata[0].name
+    labels = {
+      managed-by = "imperm"
+    }
+  }
+
+  spec {
+    limit {
+      type            = "Container"
+      default         = local.limit_default
+      default_request = local.limit_default_request
+    }
+  }
+}
@@ -8,6 +8,12 @@ variable "namespace_name" {
   }
 }
 
+variable "namespace_labels" {
+  description = "Labels that make imperm's discovery selector find the namespace; set by the server"
+  type        = map(string)
+  default     = { managed-by = "imperm" }
+}
+
 variable "constant_logger" {
   description = "Deploy Options - Number of constant logger replicas (logs every 2s)"
   type        = number
@@ -63,3 +69,58 @@ variable "service_type" {
   type        = string
   default     = "ClusterIP"
 }
+
+# Quota Options
+variable "quota_cpu_requests" {
+  description = "Quota Options - Total CPU the environment may request (e.g. 2), empty for no limit"
+  type        = string
+  default     = ""
+}
+
+variable "quota_memory_requests" {
+  description = "Quota Options - Total memory the environment may request (e.g. 4Gi), empty for no limit"
+  type        = string
+  default     = ""
+}
+
+variable "quota_cpu_limits" {
+  description = "Quota Options - Total CPU limits of the environment (e.g. 4), empty for no limit"
+  type        = string
+  default     = ""
+}
+
+variable "quota_memory_limits" {
+  description = "Quota Options - Total memory limits of the environment (e.g. 8Gi), empty for no limit"
+  type        = string
+  default     = ""
+}
+
+variable "quota_pods" {
+  description = "Quota Options - Maximum number of pods (e.g. 30), empty for no limit"
+  type        = string
+  default     = ""
+}
+
+variable "limit_default_cpu_request" {
+  description = "Quota Options - CPU request for containers that set none (e.g. 50m)"
+  type        = string
+  default     = ""
+}
+
+variable "limit_default_memory_request" {
+  description = "Quota Options - Memory request for containers that set none (e.g. 64Mi)"
+  type        = string
+  default     = ""
+}
+
+variable "limit_default_cpu" {
+  description = "Quota Options - CPU limit for containers that set none (e.g. 250m)"
+  type        = string
+  default     = ""
+}
+
+variable "limit_default_memory" {
+  description = "Quota Options - Memory limit for containers that set none (e.g. 256Mi)"
+  type        = string
+  default     = ""
+}
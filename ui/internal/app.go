@@ -1,10 +1,13 @@
 package ui
 
 import (
+	"fmt"
+
 	"imperm-ui/internal/control"
 	"imperm-ui/internal/observe"
 	sharedui "imperm-ui/internal/ui"
 	"imperm-ui/pkg/client"
+	"imperm-ui/pkg/models"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -25,6 +28,15 @@ type Model struct {
 	width       int
 	height      int
 	initialized bool
+
+	// Clusters the server manages; the switcher is hidden unless there are several
+	clusters     []models.Cluster
+	clusterIndex int
+}
+
+type clustersLoadedMsg struct {
+	clusters []models.Cluster
+	err      error
 }
 
 func NewModel(client client.Client) *Model {
@@ -42,6 +54,7 @@ func (m *Model) Init() tea.Cmd {
 	var cmds []tea.Cmd
 	cmds = append(cmds, m.controlTab.Init())
 	cmds = append(cmds, m.observeTab.Init())
+	cmds = append(cmds, m.loadClusters)
 
 	m.initialized = true
 	return tea.Batch(cmds...)
@@ -53,9 +66,23 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// While a tab has a prompt open, keys belong to the prompt
+		if m.currentTab == tabObserve && m.observeTab.InputActive() && msg.String() != "ctrl+c" {
+			_, cmd = m.observeTab.Update(msg)
+			return m, cmd
+		}
+		if m.currentTab == tabControl && m.controlTab.InputActive() && msg.String() != "ctrl+c" {
+			_, cmd = m.controlTab.Update(msg)
+			return m, cmd
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
+			// Close port forwards so their server-side sessions don't linger
+			m.observeTab.Close()
 			return m, tea.Quit
+		case "ctrl+k":
+			return m, m.nextCluster()
 		case "tab":
 			// Switch tabs
 			if m.currentTab == tabControl {
@@ -68,6 +95,18 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+	case clustersLoadedMsg:
+		// Servers without cluster support simply don't get a switcher
+		if msg.err == nil {
+			m.clusters = msg.clusters
+			for i, cluster := range m.clusters {
+				if cluster.Default {
+					m.clusterIndex = i
+				}
+			}
+		}
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -83,10 +122,13 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// TickMsg should only go to the active tab to reduce unnecessary processing
 	switch msg.(type) {
 	case tea.KeyMsg:
-		// Forward key messages to both tabs (for global shortcuts)
-		_, cmd = m.controlTab.Update(msg)
-		cmds = append(cmds, cmd)
-		_, cmd = m.observeTab.Update(msg)
+		// Forward key messages to the active tab only, so actions such as
+		// exec or delete never fire on a tab that isn't visible
+		if m.currentTab == tabControl {
+			_, cmd = m.controlTab.Update(msg)
+		} else {
+			_, cmd = m.observeTab.Update(msg)
+		}
 		cmds = append(cmds, cmd)
 	default:
 		// For other messages (including TickMsg), only forward to active tab
@@ -102,6 +144,28 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+func (m *Model) loadClusters() tea.Msg {
+	clusters, err := m.client.ListClusters()
+	return clustersLoadedMsg{clusters: clusters, err: err}
+}
+
+// nextCluster switches every later request to the next cluster. The observe
+// tab starts over, since its selection and port forwards belong to the old
+// one, and the control tab stops following the old cluster's operation.
+func (m *Model) nextCluster() tea.Cmd {
+	if len(m.clusters) < 2 {
+		return nil
+	}
+	m.clusterIndex = (m.clusterIndex + 1) % len(m.clusters)
+	m.client.SetCluster(m.clusters[m.clusterIndex].Name)
+	m.controlTab.ClusterChanged()
+
+	m.observeTab.Close()
+	m.observeTab = observe.NewTab(m.client)
+	m.observeTab.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	return m.observeTab.Init()
+}
+
 func (m *Model) View() string {
 	if !m.initialized {
 		return "Initializing..."
@@ -134,6 +198,22 @@ func (m *Model) View() string {
 		observeTabStyle.Render(" Observe "),
 	)
 
+	// Cluster switcher
+	if len(m.clusters) > 1 {
+		clusterStyle := lipgloss.NewStyle().
+			Foreground(sharedui.ColorPrimary).
+			Background(sharedui.ColorBorderDark).
+			Bold(true).
+			Padding(0, 2).
+			MarginLeft(2)
+		cluster := m.clusters[m.clusterIndex]
+		tabs = lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			tabs,
+			clusterStyle.Render(fmt.Sprintf("⎈ %s (%d/%d)", cluster.Name, m.clusterIndex+1, len(m.clusters))),
+		)
+	}
+
 	// Add observe info when on observe tab
 	var tabBarContent string
 	if m.currentTab == tabObserve && m.observeTab != nil {
@@ -172,7 +252,11 @@ func (m *Model) View() string {
 		Foreground(sharedui.ColorTextDimmer).
 		Padding(0, 1)
 
-	footer := footerStyle.Render("[Tab] Switch  [q] Quit")
+	help := "[Tab] Switch  [q] Quit"
+	if len(m.clusters) > 1 {
+		help = "[Tab] Switch  [Ctrl+K] Cluster  [q] Quit"
+	}
+	footer := footerStyle.Render(help)
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -12,6 +12,12 @@ const (
 
 	// ResourceRefreshInterval is how often to refresh resource lists in observe tab
 	ResourceRefreshInterval = 10 * time.Second // Increased from 5s to reduce API calls and CPU usage
+
+	// RolloutPollInterval is how often to poll a deployment while a rollout is in progress
+	RolloutPollInterval = 1 * time.Second
+
+	// RolloutTimeout is how long to track a rollout before giving up
+	RolloutTimeout = 5 * time.Minute
 )
 
 // Layout constants
@@ -0,0 +1,347 @@
+package control
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"imperm-ui/internal/config"
+	"imperm-ui/internal/ui"
+	"imperm-ui/pkg/models"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func newApplyInput() textinput.Model {
+	ti := textinput.New()
+	ti.CharLimit = 1024
+	ti.Width = 50
+	return ti
+}
+
+// InputActive reports whether the apply flow is capturing keyboard input
+func (t *Tab) InputActive() bool {
+	return t.currentScreen == screenApplyManifest && t.applyStep != applyStepReview
+}
+
+// startApplyManifest opens the apply flow, asking for the environment first
+func (t *Tab) startApplyManifest() tea.Cmd {
+	t.currentScreen = screenApplyManifest
+	t.applyStep = applyStepEnvironment
+	t.applyResult = nil
+	t.applyScroll = 0
+
+	env := t.applyEnv
+	if env == "" {
+		env = t.currentOperation
+	}
+	t.applyInput.Placeholder = "environment-name"
+	t.applyInput.SetValue(env)
+	t.applyInput.CursorEnd()
+	return t.applyInput.Focus()
+}
+
+func (t *Tab) updateApplyManifest(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch t.applyStep {
+	case applyStepEnvironment:
+		switch msg.String() {
+		case "enter":
+			env := strings.TrimSpace(t.applyInput.Value())
+			if env == "" {
+				return t, nil
+			}
+			t.applyEnv = env
+			t.applyStep = applyStepFile
+			t.applyInput.Placeholder = "path/to/manifest.yaml"
+			t.applyInput.SetValue(t.applyFile)
+			t.applyInput.CursorEnd()
+		case "esc":
+			t.closeApplyManifest()
+		default:
+			t.applyInput, cmd = t.applyInput.Update(msg)
+		}
+
+	case applyStepFile:
+		switch msg.String() {
+		case "enter":
+			path := expandHome(strings.TrimSpace(t.applyInput.Value()))
+			if path == "" {
+				return t, nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return t, t.setStatus("error", "❌ Failed to read manifest: %v", err)
+			}
+			t.applyFile = path
+			t.applyStep = applyStepReview
+			t.applyInput.Blur()
+			// Always show the diff before anything is persisted
+			return t, t.applyManifest(string(data), true)
+		case "tab":
+			t.applyInput.SetValue(completePath(t.applyInput.Value()))
+			t.applyInput.CursorEnd()
+		case "esc":
+			// Back to the environment name
+			t.applyStep = applyStepEnvironment
+			t.applyInput.Placeholder = "environment-name"
+			t.applyInput.SetValue(t.applyEnv)
+			t.applyInput.CursorEnd()
+		default:
+			t.applyInput, cmd = t.applyInput.Update(msg)
+		}
+
+	case applyStepReview:
+		switch msg.String() {
+		case "up", "k":
+			if t.applyScroll > 0 {
+				t.applyScroll--
+			}
+		case "down", "j":
+			t.applyScroll++
+		case "a":
+			if t.applyRunning || t.applyResult == nil {
+				return t, nil
+			}
+			// Apply what was reviewed, even if the file changed since
+			return t, t.applyManifest(t.applyDryRun, false)
+		case "r":
+			// Re-run the dry run, e.g. after editing the file
+			if t.applyRunning {
+				return t, nil
+			}
+			data, err := os.ReadFile(t.applyFile)
+			if err != nil {
+				return t, t.setStatus("error", "❌ Failed to read manifest: %v", err)
+			}
+			return t, t.applyManifest(string(data), true)
+		case "esc":
+			t.closeApplyManifest()
+		}
+	}
+
+	return t, cmd
+}
+
+func (t *Tab) closeApplyManifest() {
+	t.currentScreen = screenMainActions
+	t.applyInput.Blur()
+	t.applyDryRun = ""
+	t.applyResult = nil
+	t.applyScroll = 0
+}
+
+// applyManifest sends the manifest to the environment, as a dry run or for real
+func (t *Tab) applyManifest(manifest string, dryRun bool) tea.Cmd {
+	t.applyRunning = true
+	envName := t.applyEnv
+	return func() tea.Msg {
+		result, err := t.client.ApplyManifest(envName, models.ApplyManifestRequest{
+			Manifest: manifest,
+			DryRun:   dryRun,
+		})
+		return manifestAppliedMsg{manifest: manifest, result: result, err: err, dryRun: dryRun}
+	}
+}
+
+func (t *Tab) handleManifestApplied(msg manifestAppliedMsg) tea.Cmd {
+	t.applyRunning = false
+	if msg.err != nil {
+		return t.setStatus("error", "❌ Failed to apply manifest to '%s': %v", t.applyEnv, msg.err)
+	}
+
+	t.applyResult = msg.result
+	t.applyScroll = 0
+	if msg.dryRun {
+		t.applyDryRun = msg.manifest
+	}
+
+	switch {
+	case msg.result.Failed():
+		return t.setStatus("error", "❌ Some objects failed to apply")
+	case msg.dryRun:
+		return t.setStatus("success", "✓ Dry run complete, press [a] to apply")
+	default:
+		return t.setStatus("success", "✓ Applied %d object(s) to '%s'", len(msg.result.Objects), t.applyEnv)
+	}
+}
+
+func (t *Tab) viewApplyManifest() string {
+	layout := ui.CalculateSplitLayout(t.width, t.height)
+
+	// Left panel - Steps
+	var leftPanel strings.Builder
+	leftPanel.WriteString(ui.TitleStyle.Render("Apply Manifest"))
+	leftPanel.WriteString("\n")
+	leftPanel.WriteString(ui.RenderStatusMessage(t.statusMessage, t.statusType))
+	leftPanel.WriteString("\n\n")
+
+	switch t.applyStep {
+	case applyStepEnvironment:
+		leftPanel.WriteString(ui.FormLabelStyle.Render("Environment:"))
+		leftPanel.WriteString("\n")
+		leftPanel.WriteString(t.applyInput.View())
+		leftPanel.WriteString("\n")
+		leftPanel.WriteString(ui.HelpStyle.Render("[Enter] Next  [Esc] Cancel"))
+	case applyStepFile:
+		leftPanel.WriteString(ui.FieldStyle.Render(fmt.Sprintf("Environment: %s", ui.ValueStyle.Render(t.applyEnv))))
+		leftPanel.WriteString("\n\n")
+		leftPanel.WriteString(ui.FormLabelStyle.Render("Manifest file:"))
+		leftPanel.WriteString("\n")
+		leftPanel.WriteString(t.applyInput.View())
+		leftPanel.WriteString("\n")
+		leftPanel.WriteString(ui.HelpStyle.Render("[Tab] Complete  [Enter] Dry Run  [Esc] Back"))
+	case applyStepReview:
+		leftPanel.WriteString(ui.FieldStyle.Render(fmt.Sprintf("Environment: %s", ui.ValueStyle.Render(t.applyEnv))))
+		leftPanel.WriteString("\n")
+		leftPanel.WriteString(ui.FieldStyle.Render(fmt.Sprintf("Manifest: %s", ui.ValueStyle.Render(t.applyFile))))
+		leftPanel.WriteString("\n")
+		if t.applyResult != nil {
+			leftPanel.WriteString("\n")
+			leftPanel.WriteString(renderApplySummary(t.applyResult))
+		}
+		leftPanel.WriteString("\n")
+		leftPanel.WriteString(ui.HelpStyle.Render("[a] Apply  [r] Dry Run Again  [↑↓/jk] Scroll  [Esc] Done"))
+	}
+
+	// Right panel - Diff
+	var rightPanel strings.Builder
+	title := "Diff"
+	if t.applyResult != nil && !t.applyResult.DryRun {
+		title = "Applied Changes"
+	}
+	rightPanel.WriteString(ui.TitleStyle.Render(title))
+	rightPanel.WriteString("\n\n")
+
+	switch {
+	case t.applyRunning:
+		rightPanel.WriteString(ui.InfoStyle.Render("Applying..."))
+	case t.applyResult == nil:
+		rightPanel.WriteString(ui.InfoStyle.Render("Choose a manifest to see what it would change"))
+	default:
+		lines := applyDiffLines(t.applyResult)
+
+		availableLines := t.height - config.ContentHeightOffset
+		if availableLines < config.MinLogLines {
+			availableLines = config.MinLogLines
+		}
+		maxScroll := len(lines) - availableLines
+		if maxScroll < 0 {
+			maxScroll = 0
+		}
+		if t.applyScroll > maxScroll {
+			t.applyScroll = maxScroll
+		}
+		end := t.applyScroll + availableLines
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		lineStyle := lipgloss.NewStyle().MaxWidth(layout.RightWidth - config.LogWidthAdjustment)
+		for _, line := range lines[t.applyScroll:end] {
+			rightPanel.WriteString(lineStyle.Render(line))
+			rightPanel.WriteString("\n")
+		}
+		if len(lines) > availableLines {
+			rightPanel.WriteString(lipgloss.NewStyle().
+				Foreground(ui.ColorTextDimmer).
+				Render(fmt.Sprintf("[%d-%d/%d]", t.applyScroll+1, end, len(lines))))
+		}
+	}
+
+	return ui.RenderSplitPanels(layout, leftPanel.String(), rightPanel.String(), true)
+}
+
+// renderApplySummary lists each object with the action the apply took
+func renderApplySummary(result *models.ApplyResult) string {
+	var b strings.Builder
+	for _, obj := range result.Objects {
+		color := ui.ColorTextDim
+		switch obj.Action {
+		case models.ApplyCreated:
+			color = ui.ColorSuccess
+		case models.ApplyConfigured:
+			color = ui.ColorWarning
+		case models.ApplyFailed:
+			color = ui.ColorError
+		}
+		action := lipgloss.NewStyle().Foreground(color).Render(obj.Action)
+		b.WriteString(fmt.Sprintf("  %s/%s %s\n", obj.Kind, obj.Name, action))
+		if obj.Error != "" {
+			b.WriteString(lipgloss.NewStyle().Foreground(ui.ColorError).Render("    " + obj.Error))
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// applyDiffLines flattens every object's diff into coloured lines
+func applyDiffLines(result *models.ApplyResult) []string {
+	added := lipgloss.NewStyle().Foreground(ui.ColorSuccess)
+	removed := lipgloss.NewStyle().Foreground(ui.ColorError)
+	hunk := lipgloss.NewStyle().Foreground(ui.ColorPrimary)
+	header := lipgloss.NewStyle().Bold(true).Foreground(ui.ColorText)
+
+	var lines []string
+	for _, obj := range result.Objects {
+		lines = append(lines, header.Render(fmt.Sprintf("%s/%s (%s)", obj.Kind, obj.Name, obj.Action)))
+		if obj.Diff == "" {
+			lines = append(lines, ui.InfoStyle.Render("  no changes"), "")
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(obj.Diff, "\n"), "\n") {
+			switch {
+			case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+				line = ui.InfoStyle.Render(line)
+			case strings.HasPrefix(line, "@@"):
+				line = hunk.Render(line)
+			case strings.HasPrefix(line, "+"):
+				line = added.Render(line)
+			case strings.HasPrefix(line, "-"):
+				line = removed.Render(line)
+			}
+			lines = append(lines, line)
+		}
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// expandHome replaces a leading ~ with the user's home directory
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	return path
+}
+
+// completePath extends a partial path to the longest prefix shared by the
+// files it matches, adding a trailing slash for a single directory match
+func completePath(partial string) string {
+	matches, err := filepath.Glob(expandHome(partial) + "*")
+	if err != nil || len(matches) == 0 {
+		return partial
+	}
+
+	prefix := matches[0]
+	for _, match := range matches[1:] {
+		for !strings.HasPrefix(match, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	if len(matches) == 1 {
+		if info, err := os.Stat(prefix); err == nil && info.IsDir() {
+			prefix += string(filepath.Separator)
+		}
+	}
+	if len(prefix) < len(expandHome(partial)) {
+		return partial
+	}
+	return prefix
+}
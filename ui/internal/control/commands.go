@@ -38,6 +38,15 @@ func (t *Tab) createEnvironment(envName string, options *models.DeploymentOption
 	}
 }
 
+// loadEstimate asks whether the configured options would fit in the cluster
+func (t *Tab) loadEstimate() tea.Cmd {
+	options := t.getDeploymentOptions(t.getEnvironmentName())
+	return func() tea.Msg {
+		estimate, err := t.client.EstimateEnvironment(options)
+		return estimateLoadedMsg{estimate: estimate, err: err}
+	}
+}
+
 // loadOperationLogs loads the operation logs for the current operation
 func (t *Tab) loadOperationLogs() tea.Msg {
 	if t.currentOperation == "" {
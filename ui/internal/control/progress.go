@@ -0,0 +1,130 @@
+package control
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"imperm-ui/internal/ui"
+	"imperm-ui/pkg/models"
+)
+
+// maxProgressResources is how many resources the progress section lists before eliding the rest
+const maxProgressResources = 8
+
+// progressBarWidth is the width of the resource progress bar, without its brackets
+const progressBarWidth = 20
+
+// dimStyle renders the secondary details of the progress section
+var dimStyle = lipgloss.NewStyle().Foreground(ui.ColorTextDim)
+
+// renderOperationProgress renders a progress bar of the operation's resources,
+// the state of each and its diagnostics, returning the text and how many
+// lines it takes
+func renderOperationProgress(progress *models.OperationProgress, width int) (string, int) {
+	if progress == nil || (progress.Total == 0 && len(progress.Diagnostics) == 0) {
+		return "", 0
+	}
+
+	var lines []string
+	if progress.Total > 0 {
+		bar := ui.RenderProgressBar(progress.Done, progress.Total, progressBarWidth)
+		counts := fmt.Sprintf("%d/%d resources", progress.Done, progress.Total)
+		if progress.Errored > 0 {
+			counts += lipgloss.NewStyle().Foreground(ui.ColorError).Render(fmt.Sprintf(", %d failed", progress.Errored))
+		}
+		lines = append(lines, lipgloss.NewStyle().Foreground(ui.ColorPrimary).Render(bar)+" "+ui.ValueStyle.Render(counts))
+
+		// With too many to list, resources still being worked on come before the finished ones
+		shown := progress.Resources
+		if len(shown) > maxProgressResources {
+			shown = append(unfinishedResources(shown), finishedResources(shown)...)[:maxProgressResources]
+		}
+		for _, r := range shown {
+			lines = append(lines, renderResourceProgress(r, width))
+		}
+		if len(progress.Resources) > len(shown) {
+			lines = append(lines, dimStyle.Render(fmt.Sprintf("  ... and %d more", len(progress.Resources)-len(shown))))
+		}
+	}
+
+	for _, diag := range progress.Diagnostics {
+		lines = append(lines, renderDiagnostic(diag, width)...)
+	}
+
+	return strings.Join(lines, "\n") + "\n\n", len(lines) + 1
+}
+
+// renderResourceProgress renders one resource's state as an icon, its address and action
+func renderResourceProgress(r models.ResourceProgress, width int) string {
+	icon, color := "·", ui.ColorTextDim
+	switch r.Status {
+	case models.ResourceApplying:
+		icon, color = "⟳", ui.ColorRunning
+	case models.ResourceComplete:
+		icon, color = "✓", ui.ColorSuccess
+	case models.ResourceErrored:
+		icon, color = "✗", ui.ColorError
+	}
+
+	detail := r.Action
+	if r.Elapsed > 0 {
+		detail += fmt.Sprintf(" %ds", r.Elapsed)
+	}
+	address := truncateLeft(r.Address, width-len(detail)-6)
+	return "  " + lipgloss.NewStyle().Foreground(color).Render(icon+" "+address) + " " + dimStyle.Render(detail)
+}
+
+// renderDiagnostic renders a terraform warning or error with where it points in the configuration
+func renderDiagnostic(diag models.Diagnostic, width int) []string {
+	color, label := ui.ColorWarning, "Warning"
+	if diag.Severity == "error" {
+		color, label = ui.ColorError, "Error"
+	}
+
+	heading := label + ": " + diag.Summary
+	if diag.Range != nil {
+		heading += fmt.Sprintf(" (%s:%d)", filepath.Base(diag.Range.Filename), diag.Range.StartLine)
+	}
+	lines := []string{lipgloss.NewStyle().Foreground(color).Bold(true).Render(truncateLeft(heading, width))}
+	if diag.Address != "" {
+		lines = append(lines, "  "+ui.ValueStyle.Render(truncateLeft(diag.Address, width-2)))
+	}
+	if diag.Detail != "" {
+		for _, line := range strings.Split(diag.Detail, "\n") {
+			lines = append(lines, "  "+dimStyle.Render(line))
+		}
+	}
+	return lines
+}
+
+func unfinishedResources(resources []models.ResourceProgress) []models.ResourceProgress {
+	var unfinished []models.ResourceProgress
+	for _, r := range resources {
+		if r.Status != models.ResourceComplete {
+			unfinished = append(unfinished, r)
+		}
+	}
+	return unfinished
+}
+
+func finishedResources(resources []models.ResourceProgress) []models.ResourceProgress {
+	var finished []models.ResourceProgress
+	for _, r := range resources {
+		if r.Status == models.ResourceComplete {
+			finished = append(finished, r)
+		}
+	}
+	return finished
+}
+
+// truncateLeft shortens s to width by dropping its start, where resource
+// addresses repeat the module path
+func truncateLeft(s string, width int) string {
+	runes := []rune(s)
+	if width <= 3 || len(runes) <= width {
+		return s
+	}
+	return "..." + string(runes[len(runes)-width+3:])
+}
@@ -29,6 +29,7 @@ func NewTab(client client.Client) *Tab {
 			"Retain Environment",
 			"Get Environment",
 			"Delete Environment",
+			"Apply Manifest",
 		},
 		textInput:        ti,
 		inputMode:        false,
@@ -36,6 +37,7 @@ func NewTab(client client.Client) *Tab {
 		selectedCategory: 0,
 		optionCategories: categories,
 		selectedField:    0,
+		applyInput:       newApplyInput(),
 	}
 }
 
@@ -43,6 +45,26 @@ func (t *Tab) Init() tea.Cmd {
 	return tickCmd()
 }
 
+// ClusterChanged forgets what belongs to the previous cluster: the operation
+// whose logs are followed, the capacity estimate and the apply flow's
+// environment. The deployment options are kept for the new cluster.
+func (t *Tab) ClusterChanged() {
+	t.currentOperation = ""
+	t.operationLogs = nil
+	t.operationStatus = ""
+	t.operationProgress = nil
+	t.logPanelFocused = false
+	t.logScrollOffset = 0
+
+	t.estimate = nil
+	t.estimateErr = nil
+
+	if t.currentScreen == screenApplyManifest {
+		t.closeApplyManifest()
+	}
+	t.applyEnv = ""
+}
+
 // Helper methods for form management
 
 func (t *Tab) initializeFieldInputs() {
@@ -5,6 +5,9 @@ import (
 	"strings"
 )
 
+// serverVariables are module variables the server sets, which aren't options
+var serverVariables = map[string]bool{"namespace_labels": true}
+
 // loadOptionsFromTerraform loads option categories from Terraform modules
 func loadOptionsFromTerraform() []optionCategory {
 	// Try multiple paths for the Terraform variables file
@@ -39,6 +42,11 @@ func loadOptionsFromTerraform() []optionCategory {
 		fields := make([]optionField, 0, len(vars))
 
 		for _, v := range vars {
+			// The server labels namespaces itself, from its discovery selector
+			if serverVariables[v.Name] {
+				continue
+			}
+
 			// Extract just the description part after " - "
 			desc := v.Description
 			parts := strings.SplitN(desc, " - ", 2)
@@ -53,6 +61,9 @@ func loadOptionsFromTerraform() []optionCategory {
 			})
 		}
 
+		if len(fields) == 0 {
+			continue
+		}
 		categories = append(categories, optionCategory{
 			name:   catName,
 			fields: fields,
@@ -91,5 +102,15 @@ func getFallbackOptions() []optionCategory {
 				{name: "service_type", placeholder: "Kubernetes service type (default: ClusterIP)"},
 			},
 		},
+		{
+			name: "QuotaOptions",
+			fields: []optionField{
+				{name: "quota_cpu_requests", placeholder: "Total CPU requests (default: server team default)"},
+				{name: "quota_memory_requests", placeholder: "Total memory requests (default: server team default)"},
+				{name: "quota_cpu_limits", placeholder: "Total CPU limits (default: server team default)"},
+				{name: "quota_memory_limits", placeholder: "Total memory limits (default: server team default)"},
+				{name: "quota_pods", placeholder: "Maximum number of pods (default: server team default)"},
+			},
+		},
 	}
 }
@@ -14,6 +14,16 @@ const (
 	screenMainActions screenType = iota
 	screenOptionCategories
 	screenOptionForm
+	screenApplyManifest
+)
+
+// applyStep is the stage of the apply-manifest flow
+type applyStep int
+
+const (
+	applyStepEnvironment applyStep = iota
+	applyStepFile
+	applyStepReview
 )
 
 type optionCategory struct {
@@ -44,14 +54,29 @@ type Tab struct {
 	fieldInputs          []textinput.Model
 
 	// Operation logs
-	currentOperation string
-	operationLogs    []string
-	operationStatus  string
+	currentOperation  string
+	operationLogs     []string
+	operationStatus   string
+	operationProgress *models.OperationProgress // Resource progress and diagnostics, nil until reported
 
 	// Log panel focus and scrolling
 	logPanelFocused bool
 	logScrollOffset int
 
+	// Capacity and cost estimate of the configured options
+	estimate    *models.CapacityEstimate
+	estimateErr error
+
+	// Manifest apply flow
+	applyStep    applyStep
+	applyEnv     string
+	applyInput   textinput.Model
+	applyFile    string
+	applyDryRun  string // Manifest the shown dry run was made from, which [a] applies
+	applyResult  *models.ApplyResult
+	applyRunning bool
+	applyScroll  int
+
 	// Status message
 	statusMessage string
 	statusTime    time.Time
@@ -68,3 +93,15 @@ type environmentCreatedMsg struct {
 	envName string
 	err     error
 }
+
+type estimateLoadedMsg struct {
+	estimate *models.CapacityEstimate
+	err      error
+}
+
+type manifestAppliedMsg struct {
+	manifest string
+	result   *models.ApplyResult
+	err      error
+	dryRun   bool
+}
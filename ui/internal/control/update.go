@@ -26,6 +26,7 @@ func (t *Tab) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			t.operationLogs = logs
 			t.operationStatus = msg.logs.Status
+			t.operationProgress = msg.logs.Progress
 			// Logs are now persistent and won't be cleared automatically
 		}
 
@@ -47,6 +48,13 @@ func (t *Tab) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return t, t.setStatus("error", "❌ Failed to create environment '%s': %v", msg.envName, msg.err)
 		}
 
+	case estimateLoadedMsg:
+		t.estimate = msg.estimate
+		t.estimateErr = msg.err
+
+	case manifestAppliedMsg:
+		return t, t.handleManifestApplied(msg)
+
 	case tea.KeyMsg:
 		switch t.currentScreen {
 		case screenMainActions:
@@ -55,6 +63,8 @@ func (t *Tab) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return t.updateOptionCategories(msg)
 		case screenOptionForm:
 			return t.updateOptionForm(msg)
+		case screenApplyManifest:
+			return t.updateApplyManifest(msg)
 		}
 	}
 
@@ -72,6 +82,7 @@ func (t *Tab) updateMainActions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				t.currentOperation = envName
 				t.operationLogs = []string{}
 				t.operationStatus = "running"
+				t.operationProgress = nil
 				t.textInput.Reset()
 				t.inputMode = false
 				// Create with nil options (no loggers)
@@ -125,12 +136,15 @@ func (t *Tab) updateMainActions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			case 1: // Build Environment with Options
 				t.currentScreen = screenOptionCategories
 				t.selectedCategory = 0
+				return t, t.loadEstimate()
 			case 2: // Retain Environment
 				return t, t.setStatus("error", "⚠️  Unsupported operation: Retain Environment")
 			case 3: // Get Environment
 				return t, t.setStatus("error", "⚠️  Unsupported operation: Get Environment")
 			case 4: // Delete Environment
 				return t, t.setStatus("error", "⚠️  Unsupported operation: Delete Environment")
+			case 5: // Apply Manifest
+				return t, t.startApplyManifest()
 			}
 		}
 	}
@@ -164,6 +178,7 @@ func (t *Tab) updateOptionCategories(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		t.currentOperation = envName
 		t.operationLogs = []string{}
 		t.operationStatus = "running"
+		t.operationProgress = nil
 		t.currentScreen = screenMainActions
 		return t, tea.Batch(t.createEnvironment(envName, options), t.setStatus("success", "✓ Started creating environment '%s'", envName))
 	}
@@ -199,6 +214,7 @@ func (t *Tab) updateOptionForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Save and go back to category selection
 		t.saveFieldValues()
 		t.currentScreen = screenOptionCategories
+		return t, t.loadEstimate()
 	default:
 		// Update the focused input (allows typing hjkl and other characters)
 		if t.selectedField < len(t.fieldInputs) {
@@ -52,6 +52,45 @@ func (t *Tab) renderConfiguredOptionsPanel(showLiveInputs bool) string {
 	return panel.String()
 }
 
+// renderEstimatePanel renders whether the configured options fit in the cluster and what they would cost
+func (t *Tab) renderEstimatePanel() string {
+	var panel strings.Builder
+	panel.WriteString(ui.TitleStyle.Render("Capacity Estimate"))
+	panel.WriteString("\n\n")
+
+	switch {
+	case t.estimateErr != nil:
+		panel.WriteString(ui.ErrorStyle.Render("Estimate unavailable: " + t.estimateErr.Error()))
+		return panel.String()
+	case t.estimate == nil:
+		panel.WriteString(ui.HelpStyle.Render("Estimating..."))
+		return panel.String()
+	}
+
+	e := t.estimate
+	if e.Fits {
+		panel.WriteString(ui.SuccessStyle.Render("✓ Fits in the cluster"))
+	} else {
+		panel.WriteString(ui.ErrorStyle.Render("✗ Does not fit in the cluster"))
+	}
+	panel.WriteString("\n")
+	for _, reason := range e.Reasons {
+		panel.WriteString(ui.ErrorStyle.Render("  • "+reason) + "\n")
+	}
+	panel.WriteString("\n")
+
+	panel.WriteString(ui.FieldStyle.Render(fmt.Sprintf("Requests: %s", ui.ValueStyle.Render(fmt.Sprintf("%dm CPU, %dMi memory, %d pods",
+		e.RequestedCPUMillis, e.RequestedMemoryBytes/(1024*1024), e.RequestedPods)))) + "\n")
+	panel.WriteString(ui.FieldStyle.Render(fmt.Sprintf("Limits: %s", ui.ValueStyle.Render(fmt.Sprintf("%dm CPU, %dMi memory",
+		e.LimitCPUMillis, e.LimitMemoryBytes/(1024*1024))))) + "\n")
+	panel.WriteString(ui.FieldStyle.Render(fmt.Sprintf("Available: %s", ui.ValueStyle.Render(fmt.Sprintf("%dm CPU, %dMi memory, %d pods on %d nodes",
+		e.AvailableCPUMillis, e.AvailableMemoryBytes/(1024*1024), e.AvailablePods, e.Nodes)))) + "\n")
+	panel.WriteString(ui.FieldStyle.Render(fmt.Sprintf("Cost: %s", ui.ValueStyle.Render(fmt.Sprintf("%.2f %s/month (%.4f/hour)",
+		e.Cost.Monthly, e.Cost.Currency, e.Cost.Hourly)))) + "\n")
+
+	return panel.String()
+}
+
 func (t *Tab) View() string {
 	if t.width == 0 {
 		return "Loading..."
@@ -64,6 +103,8 @@ func (t *Tab) View() string {
 		return t.viewOptionCategories()
 	case screenOptionForm:
 		return t.viewOptionForm()
+	case screenApplyManifest:
+		return t.viewApplyManifest()
 	}
 
 	return "Unknown screen"
@@ -145,13 +186,17 @@ func (t *Tab) viewMainActions() string {
 			),
 		))
 
+		// Resource progress and diagnostics of terraform -json output, above the raw logs
+		progress, progressLines := renderOperationProgress(t.operationProgress, layout.RightWidth-config.LogWidthAdjustment)
+		rightPanel.WriteString(progress)
+
 		// Show logs (auto-scroll to bottom, showing most recent)
 		logStyle := lipgloss.NewStyle().
 			Foreground(ui.ColorText).
 			Width(layout.RightWidth - config.LogWidthAdjustment)
 
-		// Calculate available height for logs (subtract title, status, padding)
-		availableLines := t.height - config.ContentHeightOffset
+		// Calculate available height for logs (subtract title, status, progress, padding)
+		availableLines := t.height - config.ContentHeightOffset - progressLines
 		if availableLines < config.MinLogLines {
 			availableLines = config.MinLogLines
 		}
@@ -224,8 +269,8 @@ func (t *Tab) viewOptionCategories() string {
 	leftPanel.WriteString("\n")
 	leftPanel.WriteString(ui.HelpStyle.Render("[↑↓/jk] Navigate  [Enter] Configure  [c] Create  [Esc] Back"))
 
-	// Right panel - Configured options
-	rightPanelContent := t.renderConfiguredOptionsPanel(false)
+	// Right panel - Configured options and what they would take from the cluster
+	rightPanelContent := t.renderConfiguredOptionsPanel(false) + "\n\n" + t.renderEstimatePanel()
 
 	// Combine panels
 	layout := ui.CalculateSplitLayout(t.width, t.height)
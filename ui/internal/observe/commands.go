@@ -11,7 +11,7 @@ import (
 )
 
 func (t *Tab) loadResources() tea.Msg {
-	envs, err := t.client.ListEnvironments()
+	envs, err := t.client.ListEnvironments(models.EnvironmentFilter{Unmanaged: t.showUnmanaged})
 	if err != nil {
 		return messages.ErrMsg{Err: err}
 	}
@@ -26,11 +26,32 @@ func (t *Tab) loadResources() tea.Msg {
 		return messages.ErrMsg{Err: err}
 	}
 
-	return resourcesLoadedMsg{
+	msg := resourcesLoadedMsg{
 		environments: envs,
 		pods:         pods,
 		deployments:  deployments,
 	}
+
+	// Other resource types are only fetched while their table is shown
+	switch t.currentResource {
+	case ResourceServices:
+		msg.services, err = t.client.ListServices(t.filterNamespace)
+	case ResourceConfigMaps:
+		msg.configMaps, err = t.client.ListConfigMaps(t.filterNamespace)
+	case ResourceSecrets:
+		msg.secrets, err = t.client.ListSecrets(t.filterNamespace)
+	case ResourceIngresses:
+		msg.ingresses, err = t.client.ListIngresses(t.filterNamespace)
+	case ResourceJobs:
+		msg.jobs, err = t.client.ListJobs(t.filterNamespace)
+	case ResourceStatefulSets:
+		msg.statefulSets, err = t.client.ListStatefulSets(t.filterNamespace)
+	}
+	if err != nil {
+		return messages.ErrMsg{Err: err}
+	}
+
+	return msg
 }
 
 func (t *Tab) loadLogs() tea.Cmd {
@@ -58,10 +79,10 @@ func (t *Tab) loadEvents() tea.Cmd {
 	return func() tea.Msg {
 		resource := t.getSelectedResource()
 		if resource == nil {
-			return eventsLoadedMsg{events: []models.Event{}}
+			return eventsLoadedMsg{events: []models.EventTimeEntry{}}
 		}
 
-		var events []models.Event
+		var events []models.EventTimeEntry
 		var err error
 
 		switch r := resource.(type) {
@@ -70,9 +91,10 @@ func (t *Tab) loadEvents() tea.Cmd {
 		case models.Deployment:
 			events, err = t.client.GetDeploymentEvents(r.Namespace, r.Name)
 		case models.Environment:
-			// For environments, we could aggregate events from all pods/deployments
-			// For now, return empty
-			events = []models.Event{}
+			timeline, err := t.client.GetEnvironmentEvents(r.Name, t.timelineFilter)
+			return timelineLoadedMsg{env: r.Name, events: timeline, err: err}
+		default:
+			events = []models.EventTimeEntry{}
 		}
 
 		if err != nil {
@@ -93,6 +115,18 @@ func (t *Tab) loadStats() tea.Cmd {
 			resourceType = "pods"
 		case ResourceDeployments:
 			resourceType = "deployments"
+		case ResourceServices:
+			resourceType = "services"
+		case ResourceConfigMaps:
+			resourceType = "configmaps"
+		case ResourceSecrets:
+			resourceType = "secrets"
+		case ResourceIngresses:
+			resourceType = "ingresses"
+		case ResourceJobs:
+			resourceType = "jobs"
+		case ResourceStatefulSets:
+			resourceType = "statefulsets"
 		}
 
 		stats, err := t.client.GetResourceStats(resourceType, t.filterNamespace)
@@ -104,6 +138,28 @@ func (t *Tab) loadStats() tea.Cmd {
 	}
 }
 
+// metricsTarget picks the pod or namespace whose usage history the Stats view shows
+func (t *Tab) metricsTarget() (namespace, pod string) {
+	switch r := t.getSelectedResource().(type) {
+	case models.Pod:
+		return r.Namespace, r.Name
+	case models.Environment:
+		return r.Namespace, ""
+	}
+	return t.filterNamespace, ""
+}
+
+func (t *Tab) loadMetricsHistory() tea.Cmd {
+	namespace, pod := t.metricsTarget()
+	if namespace == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		history, err := t.client.GetMetricsHistory(namespace, pod)
+		return metricsHistoryLoadedMsg{key: namespace + "/" + pod, history: history, err: err}
+	}
+}
+
 func (t *Tab) deleteSelectedResource() tea.Cmd {
 	return func() tea.Msg {
 		// Perform deletion asynchronously
@@ -144,6 +200,226 @@ func (t *Tab) deleteSelectedResource() tea.Cmd {
 	}
 }
 
+// runDeploymentAction performs a change to a deployment and reports the outcome
+func (t *Tab) runDeploymentAction(dep models.Deployment, action deploymentAction, target int, detail string, fn func() error) tea.Cmd {
+	return func() tea.Msg {
+		return deploymentActionMsg{
+			namespace:  dep.Namespace,
+			deployment: dep.Name,
+			action:     action,
+			target:     target,
+			detail:     detail,
+			err:        fn(),
+		}
+	}
+}
+
+// scaleDeployment changes the replica count of a deployment
+func (t *Tab) scaleDeployment(dep models.Deployment, replicas int) tea.Cmd {
+	return t.runDeploymentAction(dep, actionScale, replicas, fmt.Sprintf("to %d replicas", replicas), func() error {
+		return t.client.ScaleDeployment(dep.Namespace, dep.Name, replicas)
+	})
+}
+
+// restartDeployment triggers a rolling restart of a deployment
+func (t *Tab) restartDeployment(dep models.Deployment) tea.Cmd {
+	return t.runDeploymentAction(dep, actionRestart, dep.Replicas, "", func() error {
+		return t.client.RestartDeployment(dep.Namespace, dep.Name)
+	})
+}
+
+// togglePauseDeployment pauses a running rollout or resumes a paused one
+func (t *Tab) togglePauseDeployment(dep models.Deployment) tea.Cmd {
+	if dep.Paused {
+		return t.runDeploymentAction(dep, actionResume, dep.Replicas, "", func() error {
+			return t.client.ResumeDeployment(dep.Namespace, dep.Name)
+		})
+	}
+	return t.runDeploymentAction(dep, actionPause, dep.Replicas, "", func() error {
+		return t.client.PauseDeployment(dep.Namespace, dep.Name)
+	})
+}
+
+// rollbackDeployment rolls a deployment back to a revision (0 for the previous one)
+func (t *Tab) rollbackDeployment(dep models.Deployment, revision int64) tea.Cmd {
+	detail := "to previous revision"
+	if revision > 0 {
+		detail = fmt.Sprintf("to revision %d", revision)
+	}
+	return t.runDeploymentAction(dep, actionRollback, dep.Replicas, detail, func() error {
+		return t.client.RollbackDeployment(dep.Namespace, dep.Name, revision)
+	})
+}
+
+// loadDeploymentHistory loads the rollout history of the selected deployment
+func (t *Tab) loadDeploymentHistory() tea.Cmd {
+	return func() tea.Msg {
+		dep, ok := t.getSelectedResource().(models.Deployment)
+		if !ok {
+			return nil
+		}
+
+		history, err := t.client.GetDeploymentHistory(dep.Namespace, dep.Name)
+		if err != nil {
+			return messages.ErrMsg{Err: err}
+		}
+
+		return historyLoadedMsg{deployment: dep.Namespace + "/" + dep.Name, history: history}
+	}
+}
+
+// manifestTarget maps a resource shown in the tab to its manifest kind and key
+func manifestTarget(resource interface{}) (kind, namespace, name string, ok bool) {
+	switch r := resource.(type) {
+	case models.Environment:
+		return models.KindNamespace, r.Namespace, r.Namespace, true
+	case models.Pod:
+		return models.KindPod, r.Namespace, r.Name, true
+	case models.Deployment:
+		return models.KindDeployment, r.Namespace, r.Name, true
+	case models.Service:
+		return models.KindService, r.Namespace, r.Name, true
+	case models.ConfigMap:
+		return models.KindConfigMap, r.Namespace, r.Name, true
+	case models.Secret:
+		return models.KindSecret, r.Namespace, r.Name, true
+	case models.Ingress:
+		return models.KindIngress, r.Namespace, r.Name, true
+	case models.Job:
+		return models.KindJob, r.Namespace, r.Name, true
+	case models.StatefulSet:
+		return models.KindStatefulSet, r.Namespace, r.Name, true
+	}
+	return "", "", "", false
+}
+
+// loadManifest loads the live YAML and describe summary of the selected resource
+func (t *Tab) loadManifest() tea.Cmd {
+	resource := t.getSelectedResource()
+	kind, namespace, name, ok := manifestTarget(resource)
+	if !ok {
+		return nil
+	}
+
+	// Secret values only appear in the YAML once revealed in the table
+	reveal := false
+	if secret, isSecret := resource.(models.Secret); isSecret {
+		reveal = t.isRevealed(secret)
+	}
+
+	return func() tea.Msg {
+		manifest, err := t.client.GetResourceManifest(kind, namespace, name, reveal)
+		return manifestLoadedMsg{key: kind + "/" + namespace + "/" + name, manifest: manifest, err: err}
+	}
+}
+
+// loadForwards refreshes port-forward states from the server
+func (t *Tab) loadForwards() tea.Cmd {
+	return func() tea.Msg {
+		// Local forwards are still listed when the server can't be reached
+		t.forwards.Refresh()
+		return forwardsLoadedMsg{forwards: t.forwards.List()}
+	}
+}
+
+// startPortForward forwards a local port to a port on the given pod or service
+func (t *Tab) startPortForward(kind, namespace, name string, remotePort, localPort int) tea.Cmd {
+	return func() tea.Msg {
+		forward, err := t.forwards.Start(models.PortForwardRequest{
+			Namespace: namespace,
+			Kind:      kind,
+			Name:      name,
+			Port:      remotePort,
+		}, localPort)
+		return portForwardStartedMsg{forward: forward, err: err}
+	}
+}
+
+// revealSecret fetches the decoded values of a secret
+func (t *Tab) revealSecret(secret models.Secret) tea.Cmd {
+	return func() tea.Msg {
+		revealed, err := t.client.GetSecret(secret.Namespace, secret.Name, true)
+		return secretRevealedMsg{secret: revealed, err: err}
+	}
+}
+
+// cloneEnvironment creates a copy of an environment from its stored options
+func (t *Tab) cloneEnvironment(source string, req models.CloneEnvironmentRequest) tea.Cmd {
+	return func() tea.Msg {
+		options, err := t.client.CloneEnvironment(source, req)
+		return environmentClonedMsg{source: source, options: options, err: err}
+	}
+}
+
+// adoptEnvironment brings an existing namespace under imperm management
+func (t *Tab) adoptEnvironment(name string) tea.Cmd {
+	return func() tea.Msg {
+		options, err := t.client.AdoptEnvironment(name)
+		return environmentAdoptedMsg{name: name, options: options, err: err}
+	}
+}
+
+// reconcileEnvironment re-applies an environment's Terraform configuration
+func (t *Tab) reconcileEnvironment(name string) tea.Cmd {
+	return func() tea.Msg {
+		return environmentReconciledMsg{name: name, err: t.client.ReconcileEnvironment(name)}
+	}
+}
+
+// resumeEnvironment finishes an environment's interrupted operation
+func (t *Tab) resumeEnvironment(name string) tea.Cmd {
+	return func() tea.Msg {
+		return environmentRecoveredMsg{name: name, err: t.client.ResumeEnvironment(name)}
+	}
+}
+
+// rollbackEnvironment undoes an environment's interrupted operation
+func (t *Tab) rollbackEnvironment(name string) tea.Cmd {
+	return func() tea.Msg {
+		return environmentRecoveredMsg{name: name, rollback: true, err: t.client.RollbackEnvironment(name)}
+	}
+}
+
+// stopPortForward closes a port forward and its local listener
+func (t *Tab) stopPortForward(id string) tea.Cmd {
+	return func() tea.Msg {
+		return portForwardStoppedMsg{id: id, err: t.forwards.Stop(id)}
+	}
+}
+
+// rolloutTick schedules the next rollout progress poll
+func (t *Tab) rolloutTick() tea.Cmd {
+	return tea.Tick(config.RolloutPollInterval, func(time.Time) tea.Msg {
+		return rolloutTickMsg{}
+	})
+}
+
+// loadRolloutProgress fetches the current replica counts of the watched deployment
+func (t *Tab) loadRolloutProgress(namespace, deploymentName string) tea.Cmd {
+	return func() tea.Msg {
+		deployments, err := t.client.ListDeployments(namespace)
+		if err != nil {
+			return messages.ErrMsg{Err: err}
+		}
+
+		for _, dep := range deployments {
+			if dep.Name == deploymentName {
+				return rolloutProgressMsg{
+					namespace:  namespace,
+					deployment: deploymentName,
+					found:      true,
+					replicas:   dep.Replicas,
+					upToDate:   dep.UpToDate,
+					available:  dep.Available,
+					complete:   dep.RolloutComplete,
+				}
+			}
+		}
+
+		return rolloutProgressMsg{namespace: namespace, deployment: deploymentName}
+	}
+}
+
 // setStatus sets a status message and returns a command to clear it after a delay
 func (t *Tab) setStatus(msgType, format string, args ...interface{}) tea.Cmd {
 	t.statusMessage = fmt.Sprintf(format, args...)
@@ -171,9 +447,16 @@ func (t *Tab) loadDataForCurrentView() tea.Cmd {
 	case RightPanelEvents:
 		return t.loadEvents()
 	case RightPanelStats:
-		return t.loadStats()
+		return tea.Batch(t.loadStats(), t.loadMetricsHistory())
+	case RightPanelForwards:
+		return t.loadForwards()
+	case RightPanelYAML:
+		return t.loadManifest()
 	default:
-		// Details view doesn't need to load data
-		return nil
+		// Details view shows the describe summary, plus rollout history for deployments
+		if t.currentResource == ResourceDeployments {
+			return tea.Batch(t.loadManifest(), t.loadDeploymentHistory())
+		}
+		return t.loadManifest()
 	}
 }
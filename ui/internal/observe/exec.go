@@ -0,0 +1,96 @@
+package observe
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
+	"github.com/muesli/cancelreader"
+	"imperm-ui/pkg/client"
+	"imperm-ui/pkg/models"
+)
+
+// execShellCommand starts bash when the container has it and falls back to sh
+var execShellCommand = []string{"/bin/sh", "-c", "command -v bash >/dev/null 2>&1 && exec bash || exec sh"}
+
+// podExecCommand attaches the local terminal to a shell in a pod container.
+// It implements tea.ExecCommand so Bubble Tea releases the terminal while the
+// session runs and restores the UI afterwards.
+type podExecCommand struct {
+	client client.Client
+	req    models.ExecRequest
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func (c *podExecCommand) SetStdin(r io.Reader)  { c.stdin = r }
+func (c *podExecCommand) SetStdout(w io.Writer) { c.stdout = w }
+func (c *podExecCommand) SetStderr(w io.Writer) { c.stderr = w }
+
+// Run puts the terminal in raw mode and streams it to the pod until the shell exits
+func (c *podExecCommand) Run() error {
+	stdin, ok := c.stdin.(*os.File)
+	if !ok {
+		stdin = os.Stdin
+	}
+	fd := stdin.Fd()
+	if !term.IsTerminal(fd) {
+		return fmt.Errorf("exec requires an interactive terminal")
+	}
+
+	target := c.req.Pod
+	if c.req.Container != "" {
+		target += "/" + c.req.Container
+	}
+	fmt.Fprintf(c.stdout, "Attaching to %s in %s (exit the shell to return)...\r\n", target, c.req.Namespace)
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to set raw terminal mode: %w", err)
+	}
+	defer term.Restore(fd, state)
+
+	// A cancelable reader lets us stop reading stdin once the session ends,
+	// so no keystrokes are swallowed after Bubble Tea takes the terminal back
+	input, err := cancelreader.NewReader(stdin)
+	if err != nil {
+		return fmt.Errorf("failed to read terminal input: %w", err)
+	}
+	defer input.Close()
+	defer input.Cancel()
+
+	sizes := make(chan models.TerminalSize, 1)
+	if width, height, err := term.GetSize(fd); err == nil {
+		sizes <- models.TerminalSize{Width: uint16(width), Height: uint16(height)}
+	}
+	stopResize := watchTerminalResize(fd, sizes)
+	defer stopResize()
+
+	return c.client.ExecPod(context.Background(), c.req, client.ExecStreams{
+		Stdin:  input,
+		Stdout: c.stdout,
+		Stderr: c.stderr,
+		Resize: sizes,
+	})
+}
+
+// execInPod suspends the UI and opens a shell in the given pod container
+func (t *Tab) execInPod(pod models.Pod, container string) tea.Cmd {
+	cmd := &podExecCommand{
+		client: t.client,
+		req: models.ExecRequest{
+			Namespace: pod.Namespace,
+			Pod:       pod.Name,
+			Container: container,
+			Command:   execShellCommand,
+			TTY:       true,
+		},
+	}
+	return tea.Exec(cmd, func(err error) tea.Msg {
+		return execFinishedMsg{pod: pod.Name, err: err}
+	})
+}
@@ -0,0 +1,42 @@
+//go:build !windows
+
+package observe
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/charmbracelet/x/term"
+	"imperm-ui/pkg/models"
+)
+
+// watchTerminalResize forwards SIGWINCH terminal size changes to sizes until stopped
+func watchTerminalResize(fd uintptr, sizes chan<- models.TerminalSize) func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGWINCH)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-signals:
+				width, height, err := term.GetSize(fd)
+				if err != nil {
+					continue
+				}
+				select {
+				case sizes <- models.TerminalSize{Width: uint16(width), Height: uint16(height)}:
+				default: // Previous size not sent yet; the next signal will catch up
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
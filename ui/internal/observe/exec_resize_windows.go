@@ -0,0 +1,11 @@
+//go:build windows
+
+package observe
+
+import "imperm-ui/pkg/models"
+
+// watchTerminalResize is a no-op on Windows, which has no SIGWINCH; the
+// session keeps the size it started with
+func watchTerminalResize(fd uintptr, sizes chan<- models.TerminalSize) func() {
+	return func() {}
+}
@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"imperm-ui/internal/ui"
 	"imperm-ui/pkg/models"
+	"sort"
 	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 func (t *Tab) renderDetailsView() string {
@@ -18,21 +22,61 @@ func (t *Tab) renderDetailsView() string {
 	switch r := resource.(type) {
 	case models.Environment:
 		details.WriteString(ui.LabelStyle.Render("Name:") + " " + ui.ValueStyle.Render(r.Name) + "\n")
+		if r.Cluster != "" {
+			details.WriteString(ui.LabelStyle.Render("Cluster:") + " " + ui.ValueStyle.Render(r.Cluster) + "\n")
+		}
 		details.WriteString(ui.LabelStyle.Render("Namespace:") + " " + ui.ValueStyle.Render(r.Namespace) + "\n")
+		if !r.Managed {
+			details.WriteString(ui.LabelStyle.Render("Managed:") + " " + ui.ValueStyle.Render("no, not created by imperm") + "\n")
+		}
 		details.WriteString(ui.LabelStyle.Render("Status:") + " " + ui.ValueStyle.Render(r.Status) + "\n")
+		healthStyle := lipgloss.NewStyle().Foreground(healthColor(r.Health.Status)).Bold(true)
+		details.WriteString(ui.LabelStyle.Render("Health:") + " " + healthStyle.Render(r.Health.Status) + "\n")
 		details.WriteString(ui.LabelStyle.Render("Age:") + " " + ui.ValueStyle.Render(formatAge(r.Age)) + "\n")
 		details.WriteString(ui.LabelStyle.Render("Pods:") + " " + ui.ValueStyle.Render(fmt.Sprintf("%d", len(r.Pods))) + "\n")
 		details.WriteString(ui.LabelStyle.Render("Deployments:") + " " + ui.ValueStyle.Render(fmt.Sprintf("%d", len(r.Deployments))) + "\n")
+		if len(r.Health.Reasons) > 0 {
+			details.WriteString("\n" + ui.StatLabelStyle.Render("Health Reasons") + "\n")
+			for _, reason := range r.Health.Reasons {
+				details.WriteString("  • " + ui.ValueStyle.Render(reason) + "\n")
+			}
+		}
+		if r.Interrupted != nil {
+			details.WriteString("\n" + renderInterrupted(*r.Interrupted))
+		}
+		if r.Drift != nil {
+			details.WriteString("\n" + renderDrift(*r.Drift))
+		}
 
 	case models.Pod:
 		details.WriteString(ui.LabelStyle.Render("Name:") + " " + ui.ValueStyle.Render(r.Name) + "\n")
 		details.WriteString(ui.LabelStyle.Render("Namespace:") + " " + ui.ValueStyle.Render(r.Namespace) + "\n")
-		details.WriteString(ui.LabelStyle.Render("Status:") + " " + ui.ValueStyle.Render(r.Status) + "\n")
+		statusStyle := lipgloss.NewStyle().Foreground(podStatusColor(r.Status)).Bold(true)
+		details.WriteString(ui.LabelStyle.Render("Status:") + " " + statusStyle.Render(r.Status) + "\n")
+		if r.Phase != "" && r.Phase != r.Status {
+			details.WriteString(ui.LabelStyle.Render("Phase:") + " " + ui.ValueStyle.Render(r.Phase) + "\n")
+		}
 		details.WriteString(ui.LabelStyle.Render("Ready:") + " " + ui.ValueStyle.Render(r.Ready) + "\n")
 		details.WriteString(ui.LabelStyle.Render("Restarts:") + " " + ui.ValueStyle.Render(fmt.Sprintf("%d", r.Restarts)) + "\n")
-		details.WriteString(ui.LabelStyle.Render("CPU:") + " " + ui.ValueStyle.Render(r.CPU) + "\n")
-		details.WriteString(ui.LabelStyle.Render("Memory:") + " " + ui.ValueStyle.Render(r.Memory) + "\n")
+		details.WriteString(ui.LabelStyle.Render("CPU:") + " " + ui.ValueStyle.Render(r.CPU) + resourceBounds(r.CPURequest, r.CPULimit) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Memory:") + " " + ui.ValueStyle.Render(r.Memory) + resourceBounds(r.MemoryRequest, r.MemoryLimit) + "\n")
 		details.WriteString(ui.LabelStyle.Render("Age:") + " " + ui.ValueStyle.Render(formatAge(r.Age)) + "\n")
+		if r.Node != "" {
+			details.WriteString(ui.LabelStyle.Render("Node:") + " " + ui.ValueStyle.Render(r.Node) + "\n")
+		}
+		if r.PodIP != "" {
+			details.WriteString(ui.LabelStyle.Render("Pod IP:") + " " + ui.ValueStyle.Render(r.PodIP) + "\n")
+		}
+		if r.QOSClass != "" {
+			details.WriteString(ui.LabelStyle.Render("QoS Class:") + " " + ui.ValueStyle.Render(r.QOSClass) + "\n")
+		}
+		if r.Owner != "" {
+			details.WriteString(ui.LabelStyle.Render("Owner:") + " " + ui.ValueStyle.Render(r.Owner) + "\n")
+		}
+		if len(r.Containers) > 0 {
+			details.WriteString("\n" + ui.StatLabelStyle.Render("Containers") + "\n")
+			details.WriteString(renderPodContainers(r.Containers))
+		}
 
 	case models.Deployment:
 		details.WriteString(ui.LabelStyle.Render("Name:") + " " + ui.ValueStyle.Render(r.Name) + "\n")
@@ -41,9 +85,317 @@ func (t *Tab) renderDetailsView() string {
 		details.WriteString(ui.LabelStyle.Render("Up-to-Date:") + " " + ui.ValueStyle.Render(fmt.Sprintf("%d", r.UpToDate)) + "\n")
 		details.WriteString(ui.LabelStyle.Render("Available:") + " " + ui.ValueStyle.Render(fmt.Sprintf("%d", r.Available)) + "\n")
 		details.WriteString(ui.LabelStyle.Render("Age:") + " " + ui.ValueStyle.Render(formatAge(r.Age)) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Revision:") + " " + ui.ValueStyle.Render(fmt.Sprintf("%d", r.Revision)) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Paused:") + " " + ui.ValueStyle.Render(fmt.Sprintf("%t", r.Paused)) + "\n")
+		details.WriteString("\n")
+		details.WriteString(t.renderRolloutHistory(r))
+
+	case models.Service:
+		details.WriteString(ui.LabelStyle.Render("Name:") + " " + ui.ValueStyle.Render(r.Name) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Namespace:") + " " + ui.ValueStyle.Render(r.Namespace) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Type:") + " " + ui.ValueStyle.Render(r.Type) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Cluster IP:") + " " + ui.ValueStyle.Render(r.ClusterIP) + "\n")
+		if r.ExternalIP != "" {
+			details.WriteString(ui.LabelStyle.Render("External IP:") + " " + ui.ValueStyle.Render(r.ExternalIP) + "\n")
+		}
+		details.WriteString(ui.LabelStyle.Render("Selector:") + " " + ui.ValueStyle.Render(formatLabels(r.Selector)) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Age:") + " " + ui.ValueStyle.Render(formatAge(r.Age)) + "\n")
+		details.WriteString("\n" + ui.StatLabelStyle.Render("Ports") + "\n")
+		for _, p := range r.Ports {
+			line := fmt.Sprintf("  %d/%s → %s", p.Port, p.Protocol, p.TargetPort)
+			if p.Name != "" {
+				line = fmt.Sprintf("  %s: %d/%s → %s", p.Name, p.Port, p.Protocol, p.TargetPort)
+			}
+			if p.NodePort != 0 {
+				line += fmt.Sprintf(" (node port %d)", p.NodePort)
+			}
+			details.WriteString(line + "\n")
+		}
+
+	case models.ConfigMap:
+		details.WriteString(ui.LabelStyle.Render("Name:") + " " + ui.ValueStyle.Render(r.Name) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Namespace:") + " " + ui.ValueStyle.Render(r.Namespace) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Age:") + " " + ui.ValueStyle.Render(formatAge(r.Age)) + "\n")
+		details.WriteString("\n" + ui.StatLabelStyle.Render("Data") + "\n")
+		details.WriteString(renderDataEntries(r.Data))
+		for _, key := range r.BinaryKeys {
+			details.WriteString(ui.LabelStyle.Render(key+":") + " " + ui.ValueStyle.Render("<binary>") + "\n")
+		}
+
+	case models.Secret:
+		secret := r
+		if t.isRevealed(r) {
+			secret = *t.revealedSecret
+		}
+		details.WriteString(ui.LabelStyle.Render("Name:") + " " + ui.ValueStyle.Render(secret.Name) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Namespace:") + " " + ui.ValueStyle.Render(secret.Namespace) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Type:") + " " + ui.ValueStyle.Render(secret.Type) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Age:") + " " + ui.ValueStyle.Render(formatAge(secret.Age)) + "\n")
+		if secret.Revealed {
+			details.WriteString("\n" + ui.StatLabelStyle.Render("Data") + " " + ui.WarningStyle.Margin(0).Render("(revealed)") + "\n")
+		} else {
+			details.WriteString("\n" + ui.StatLabelStyle.Render("Data") + " (press V to reveal)\n")
+		}
+		details.WriteString(renderDataEntries(secret.Data))
+
+	case models.Ingress:
+		details.WriteString(ui.LabelStyle.Render("Name:") + " " + ui.ValueStyle.Render(r.Name) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Namespace:") + " " + ui.ValueStyle.Render(r.Namespace) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Class:") + " " + ui.ValueStyle.Render(r.Class) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Address:") + " " + ui.ValueStyle.Render(r.Address) + "\n")
+		details.WriteString(ui.LabelStyle.Render("TLS:") + " " + ui.ValueStyle.Render(fmt.Sprintf("%t", r.TLS)) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Age:") + " " + ui.ValueStyle.Render(formatAge(r.Age)) + "\n")
+		details.WriteString("\n" + ui.StatLabelStyle.Render("Rules") + "\n")
+		for _, rule := range r.Rules {
+			host := rule.Host
+			if host == "" {
+				host = "*"
+			}
+			details.WriteString(fmt.Sprintf("  %s%s → %s:%s\n", host, rule.Path, rule.Service, rule.Port))
+		}
+
+	case models.Job:
+		details.WriteString(ui.LabelStyle.Render("Name:") + " " + ui.ValueStyle.Render(r.Name) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Namespace:") + " " + ui.ValueStyle.Render(r.Namespace) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Status:") + " " + ui.ValueStyle.Render(r.Status) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Completions:") + " " + ui.ValueStyle.Render(r.Completions) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Pods:") + " " + ui.ValueStyle.Render(fmt.Sprintf("%d active / %d succeeded / %d failed", r.Active, r.Succeeded, r.Failed)) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Duration:") + " " + ui.ValueStyle.Render(formatJobDuration(r)) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Age:") + " " + ui.ValueStyle.Render(formatAge(r.Age)) + "\n")
+
+	case models.StatefulSet:
+		details.WriteString(ui.LabelStyle.Render("Name:") + " " + ui.ValueStyle.Render(r.Name) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Namespace:") + " " + ui.ValueStyle.Render(r.Namespace) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Ready:") + " " + ui.ValueStyle.Render(r.Ready) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Updated:") + " " + ui.ValueStyle.Render(fmt.Sprintf("%d", r.UpdatedReplicas)) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Service:") + " " + ui.ValueStyle.Render(r.ServiceName) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Current Revision:") + " " + ui.ValueStyle.Render(r.CurrentRevision) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Update Revision:") + " " + ui.ValueStyle.Render(r.UpdateRevision) + "\n")
+		details.WriteString(ui.LabelStyle.Render("Age:") + " " + ui.ValueStyle.Render(formatAge(r.Age)) + "\n")
+	}
+
+	details.WriteString("\n" + ui.StatLabelStyle.Render("Describe") + "\n")
+	manifest, status := t.selectedManifest()
+	if manifest == nil {
+		details.WriteString(status)
+	} else {
+		details.WriteString(renderDescribe(manifest.Describe))
+	}
+
+	return t.scrollContent(details.String())
+}
+
+// selectedManifest returns the loaded manifest if it belongs to the selected
+// resource, or a status line to show instead
+func (t *Tab) selectedManifest() (*models.ResourceManifest, string) {
+	kind, namespace, name, ok := manifestTarget(t.getSelectedResource())
+	if !ok {
+		return nil, "No manifest available for this resource"
+	}
+	if t.manifestKey != kind+"/"+namespace+"/"+name {
+		return nil, "Loading manifest..."
+	}
+	if t.manifestErr != nil {
+		return nil, ui.ErrorStyle.Margin(0).Render(fmt.Sprintf("Failed to load manifest: %v", t.manifestErr))
+	}
+	return t.currentManifest, ""
+}
+
+// renderDescribe renders the describe summary of a resource like kubectl describe
+func renderDescribe(d models.ResourceDescription) string {
+	var out strings.Builder
+
+	for _, field := range d.Fields {
+		value := field.Value
+		if value == "" {
+			value = "<none>"
+		}
+		out.WriteString(ui.LabelStyle.Render(field.Name+":") + " " + ui.ValueStyle.Render(value) + "\n")
+	}
+
+	out.WriteString(ui.LabelStyle.Render("Labels:") + " " + ui.ValueStyle.Render(formatLabels(d.Labels)) + "\n")
+	out.WriteString(ui.LabelStyle.Render("Annotations:") + " " + ui.ValueStyle.Render(formatLabels(d.Annotations)) + "\n")
+
+	if len(d.OwnerReferences) > 0 {
+		owners := make([]string, 0, len(d.OwnerReferences))
+		for _, ref := range d.OwnerReferences {
+			owner := ref.Kind + "/" + ref.Name
+			if ref.Controller {
+				owner += " (controller)"
+			}
+			owners = append(owners, owner)
+		}
+		out.WriteString(ui.LabelStyle.Render("Controlled By:") + " " + ui.ValueStyle.Render(strings.Join(owners, ", ")) + "\n")
+	}
+
+	if len(d.Conditions) > 0 {
+		out.WriteString("\n" + ui.StatLabelStyle.Render("Conditions") + "\n")
+		out.WriteString(fmt.Sprintf("  %-22s %-8s %s\n", "TYPE", "STATUS", "REASON"))
+		for _, cond := range d.Conditions {
+			out.WriteString(fmt.Sprintf("  %-22s %-8s %s\n", cond.Type, cond.Status, cond.Reason))
+			if cond.Message != "" && cond.Status != "True" {
+				out.WriteString("    " + cond.Message + "\n")
+			}
+		}
+	}
+
+	if len(d.Containers) > 0 {
+		out.WriteString("\n" + ui.StatLabelStyle.Render("Containers") + "\n")
+		for _, c := range d.Containers {
+			name := c.Name
+			if c.Init {
+				name += " (init)"
+			}
+			out.WriteString("  " + ui.ValueStyle.Render(name) + "\n")
+			out.WriteString("    Image:     " + c.Image + "\n")
+			if len(c.Ports) > 0 {
+				out.WriteString("    Ports:     " + strings.Join(c.Ports, ", ") + "\n")
+			}
+			if c.State != "" {
+				state := c.State
+				if c.Reason != "" {
+					state += " (" + c.Reason + ")"
+				}
+				out.WriteString("    State:     " + state + "\n")
+				out.WriteString(fmt.Sprintf("    Ready:     %t\n", c.Ready))
+				out.WriteString(fmt.Sprintf("    Restarts:  %d\n", c.RestartCount))
+			}
+			if c.Requests != "" {
+				out.WriteString("    Requests:  " + c.Requests + "\n")
+			}
+			if c.Limits != "" {
+				out.WriteString("    Limits:    " + c.Limits + "\n")
+			}
+			for _, mount := range c.Mounts {
+				out.WriteString("    Mount:     " + mount + "\n")
+			}
+		}
+	}
+
+	if len(d.Volumes) > 0 {
+		out.WriteString("\n" + ui.StatLabelStyle.Render("Volumes") + "\n")
+		for _, vol := range d.Volumes {
+			line := fmt.Sprintf("  %-20s %s", vol.Name, vol.Type)
+			if vol.Source != "" {
+				line += " (" + vol.Source + ")"
+			}
+			out.WriteString(line + "\n")
+		}
+	}
+
+	return out.String()
+}
+
+// renderYAMLView shows the live manifest of the selected resource
+func (t *Tab) renderYAMLView() string {
+	if t.getSelectedResource() == nil {
+		return "Select a resource to view its YAML"
+	}
+
+	manifest, status := t.selectedManifest()
+	if manifest == nil {
+		return status
+	}
+	return t.scrollContent(strings.TrimRight(manifest.YAML, "\n"))
+}
+
+// scrollContent returns the window of content lines starting at the scroll
+// offset that fits in the right panel
+func (t *Tab) scrollContent(content string) string {
+	lines := strings.Split(content, "\n")
+
+	availableLines := t.height - 18
+	if availableLines < 5 {
+		availableLines = 5
+	}
+	if len(lines) <= availableLines {
+		return content
+	}
+
+	start := t.scrollOffset
+	if start > len(lines)-availableLines {
+		start = len(lines) - availableLines
+	}
+	if start < 0 {
+		start = 0
+	}
+	end := start + availableLines
+
+	return strings.Join(lines[start:end], "\n") + fmt.Sprintf("\n[%d-%d/%d lines]", start+1, end, len(lines))
+}
+
+// renderDataEntries renders config map or secret data sorted by key
+func renderDataEntries(data map[string]string) string {
+	if len(data) == 0 {
+		return "No data\n"
+	}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var entries strings.Builder
+	for _, key := range keys {
+		value := data[key]
+		if strings.Contains(value, "\n") {
+			// Indent multi-line values such as config files under their key
+			entries.WriteString(ui.LabelStyle.Render(key+":") + "\n")
+			for _, line := range strings.Split(strings.TrimRight(value, "\n"), "\n") {
+				entries.WriteString("  " + line + "\n")
+			}
+			continue
+		}
+		entries.WriteString(ui.LabelStyle.Render(key+":") + " " + ui.ValueStyle.Render(value) + "\n")
+	}
+	return entries.String()
+}
+
+// formatLabels renders a label map as "k=v,k2=v2" with sorted keys
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "<none>"
+	}
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// renderRolloutHistory renders the revision list for a deployment, newest first
+func (t *Tab) renderRolloutHistory(dep models.Deployment) string {
+	var history strings.Builder
+	history.WriteString(ui.StatLabelStyle.Render("Rollout History") + "\n")
+
+	if t.historyDeployment != dep.Namespace+"/"+dep.Name {
+		history.WriteString("Loading history...\n")
+		return history.String()
+	}
+
+	if len(t.currentHistory) == 0 {
+		history.WriteString("No revisions found\n")
+		return history.String()
 	}
 
-	return details.String()
+	for i := len(t.currentHistory) - 1; i >= 0; i-- {
+		rev := t.currentHistory[i]
+
+		marker := "  "
+		if rev.Current {
+			marker = ui.SuccessStyle.Margin(0).Render("● ")
+		}
+
+		line := fmt.Sprintf("%srev %-4d %-8s %s", marker, rev.Revision, formatAge(rev.Created), strings.Join(rev.Images, ", "))
+		if rev.ChangeCause != "" {
+			line += "  (" + rev.ChangeCause + ")"
+		}
+		history.WriteString(line + "\n")
+	}
+
+	return history.String()
 }
 
 func (t *Tab) renderLogsView() string {
@@ -120,12 +472,30 @@ func (t *Tab) renderLogsView() string {
 	return result.String()
 }
 
+// resourceBounds renders a pod's request and limit for one resource, e.g. " (request 100m, limit 500m)"
+func resourceBounds(request, limit string) string {
+	if request == "" && limit == "" {
+		return ""
+	}
+	if request == "" {
+		request = "-"
+	}
+	if limit == "" {
+		limit = "-"
+	}
+	return ui.InfoStyle.Render(fmt.Sprintf(" (request %s, limit %s)", request, limit))
+}
+
 func (t *Tab) renderEventsView() string {
 	resource := t.getSelectedResource()
 	if resource == nil {
 		return "Select a resource to view events"
 	}
 
+	if env, ok := resource.(models.Environment); ok {
+		return t.renderTimeline(env)
+	}
+
 	if t.currentEvents == nil {
 		return "Loading events..."
 	}
@@ -134,45 +504,84 @@ func (t *Tab) renderEventsView() string {
 		return "No events found"
 	}
 
-	// Calculate available lines to prevent box overflow
-	availableLines := t.height - 18
-	if availableLines < 5 {
-		availableLines = 5
+	var out strings.Builder
+	for _, event := range t.currentEvents {
+		color := ui.ColorSuccess
+		if event.Type == "Warning" {
+			color = ui.ColorWarning
+		}
+
+		reason := event.Reason
+		if event.Count > 1 {
+			reason += fmt.Sprintf(" (x%d)", event.Count)
+		}
+
+		// Repeated events show when they started as well as when they were last seen
+		seen := formatAge(event.LastObservedTime)
+		if event.Count > 1 && !event.FirstObservedTime.Equal(event.LastObservedTime) {
+			seen += " (first " + formatAge(event.FirstObservedTime) + ")"
+		}
+
+		out.WriteString(lipgloss.NewStyle().Foreground(color).Render(fmt.Sprintf("● %-7s", event.Type)) + " " +
+			lipgloss.NewStyle().Foreground(ui.ColorTextDim).Render(seen) + "  " +
+			ui.ValueStyle.Render(reason))
+		if event.From != "" {
+			out.WriteString("  " + ui.LabelStyle.Render("from "+event.From))
+		}
+		out.WriteString("\n")
+		out.WriteString("    " + event.Message + "\n")
 	}
 
-	// Limit events to fit in available space (each event is ~2 lines with wrapping)
-	maxEvents := availableLines / 2
-	if maxEvents < 1 {
-		maxEvents = 1
+	return t.scrollContent(strings.TrimSuffix(out.String(), "\n"))
+}
+
+func (t *Tab) renderTimeline(env models.Environment) string {
+	var out strings.Builder
+
+	typeFilter := t.timelineFilter.Type
+	if typeFilter == "" {
+		typeFilter = "All"
+	}
+	kindFilter := t.timelineFilter.Kind
+	if kindFilter == "" {
+		kindFilter = "All"
 	}
+	out.WriteString(ui.LabelStyle.Render("Type:") + " " + ui.ValueStyle.Render(typeFilter) + "  " +
+		ui.LabelStyle.Render("Kind:") + " " + ui.ValueStyle.Render(kindFilter) + "\n\n")
 
-	var events strings.Builder
-	eventsToShow := t.currentEvents
-	if len(eventsToShow) > maxEvents {
-		eventsToShow = eventsToShow[:maxEvents]
+	if t.timelineEnv != env.Name {
+		out.WriteString("Loading events...")
+		return out.String()
+	}
+	if len(t.currentTimeline) == 0 {
+		out.WriteString("No events found")
+		return out.String()
 	}
 
-	// Render events using theme colors
-	for _, event := range eventsToShow {
-		eventColor := ui.ColorSuccess
+	for _, event := range t.currentTimeline {
+		color := ui.ColorSuccess
 		if event.Type == "Warning" {
-			eventColor = ui.ColorWarning
+			color = ui.ColorWarning
+		}
+		marker := "●"
+		if event.Source == models.EventSourceImperm {
+			marker = "◆"
 		}
 
-		// Calculate time ago
-		timeAgo := formatAge(event.Timestamp)
-
-		// Format: "● Type  TimeAgo  Message"
-		events.WriteString(fmt.Sprintf("\033[38;5;%sm● %-8s\033[0m\033[38;5;%sm%s\033[0m  %s\n",
-			eventColor, event.Type, ui.ColorTextDim, timeAgo, event.Message))
-	}
+		header := fmt.Sprintf("%s %-7s", marker, event.Type)
+		object := event.Kind + "/" + event.Name
+		reason := event.Reason
+		if event.Count > 1 {
+			reason += fmt.Sprintf(" (x%d)", event.Count)
+		}
 
-	// Show truncation indicator
-	if len(t.currentEvents) > maxEvents {
-		events.WriteString(fmt.Sprintf("\n[Showing %d of %d events]", maxEvents, len(t.currentEvents)))
+		out.WriteString(lipgloss.NewStyle().Foreground(color).Render(header) + " " +
+			lipgloss.NewStyle().Foreground(ui.ColorTextDim).Render(fmt.Sprintf("%4s", formatAge(event.Time))) + "  " +
+			ui.ValueStyle.Render(reason) + "  " + ui.LabelStyle.Render(object) + "\n")
+		out.WriteString("    " + event.Message + "\n")
 	}
 
-	return events.String()
+	return t.scrollContent(strings.TrimSuffix(out.String(), "\n"))
 }
 
 func (t *Tab) renderStatsView() string {
@@ -198,7 +607,247 @@ func (t *Tab) renderStatsView() string {
 		stats.WriteString(ui.StatLabelStyle.Render("Total Deployments: ") + ui.StatValueStyle.Render(fmt.Sprintf("%d", t.currentStats.TotalCount)) + "\n\n")
 		stats.WriteString(ui.StatLabelStyle.Render("Total Replicas: ") + ui.StatValueStyle.Render(fmt.Sprintf("%d", t.currentStats.TotalReplicas)) + "\n")
 		stats.WriteString(ui.StatLabelStyle.Render("Available: ") + ui.StatValueStyle.Render(fmt.Sprintf("%d", t.currentStats.AvailableReplicas)) + "\n")
+
+	default:
+		stats.WriteString(ui.StatLabelStyle.Render("Total "+t.resourceTitle()+": ") + ui.StatValueStyle.Render(fmt.Sprintf("%d", t.currentStats.TotalCount)) + "\n")
 	}
 
+	stats.WriteString(t.renderQuota())
+	stats.WriteString(t.renderUsage())
+
 	return stats.String()
 }
+
+// renderInterrupted describes an operation a server restart cut short
+func renderInterrupted(op models.InterruptedOperation) string {
+	var b strings.Builder
+	b.WriteString(ui.StatLabelStyle.Render("Interrupted Operation") + "\n")
+	what := op.Operation
+	if op.Phase != "" {
+		what += " during " + op.Phase
+	}
+	b.WriteString("  " + lipgloss.NewStyle().Foreground(ui.ColorError).Bold(true).Render(what) + ", " +
+		ui.ValueStyle.Render(fmt.Sprintf("started %s ago", formatAge(op.StartedAt))) + "\n")
+	if op.StaleLock {
+		b.WriteString("  • " + ui.ValueStyle.Render("state lock left behind") + "\n")
+	}
+	if op.PartialState {
+		b.WriteString("  • " + ui.ValueStyle.Render("state may be incomplete") + "\n")
+	}
+	b.WriteString("  " + ui.HelpStyle.Render("[G] Resume to finish it  [B] Roll back to destroy the environment") + "\n")
+	return b.String()
+}
+
+// renderDrift describes the last drift check of an environment
+func renderDrift(drift models.DriftStatus) string {
+	var b strings.Builder
+	b.WriteString(ui.StatLabelStyle.Render("Terraform Drift") + "\n")
+	checked := ui.ValueStyle.Render(fmt.Sprintf("checked %s ago", formatAge(drift.CheckedAt)))
+	switch {
+	case drift.Error != "":
+		b.WriteString("  " + lipgloss.NewStyle().Foreground(ui.ColorError).Render("check failed: "+drift.Error) + "\n")
+	case drift.Drifted:
+		b.WriteString("  " + lipgloss.NewStyle().Foreground(ui.ColorWarning).Bold(true).Render("Drifted") + ", " + checked + "\n")
+		for _, resource := range drift.Resources {
+			b.WriteString("  • " + ui.ValueStyle.Render(resource) + "\n")
+		}
+		b.WriteString("  " + ui.HelpStyle.Render("[D] Reconcile to re-apply the Terraform configuration") + "\n")
+	default:
+		b.WriteString("  " + lipgloss.NewStyle().Foreground(ui.ColorSuccess).Render("In sync") + ", " + checked + "\n")
+	}
+	return b.String()
+}
+
+// renderQuota shows how much of its resource quota the selected environment uses
+func (t *Tab) renderQuota() string {
+	env, ok := t.getSelectedResource().(models.Environment)
+	if !ok || len(env.Quota) == 0 {
+		return ""
+	}
+
+	var out strings.Builder
+	out.WriteString("\n" + ui.StatLabelStyle.Render("Quota of namespace "+env.Namespace) + "\n\n")
+	for _, usage := range env.Quota {
+		out.WriteString(quotaLine(usage))
+	}
+	return out.String()
+}
+
+// quotaLine renders one quota resource as a percentage bar of its hard limit
+func quotaLine(usage models.QuotaUsage) string {
+	color := ui.ColorSuccess
+	switch {
+	case usage.Percentage >= 90:
+		color = ui.ColorError
+	case usage.Percentage >= 70:
+		color = ui.ColorWarning
+	}
+	label := ui.StatLabelStyle.Render(fmt.Sprintf("%-15s", usage.Resource))
+	bar := lipgloss.NewStyle().Foreground(color).Render(ui.RenderProgressBar(int(usage.Percentage), 100, 20))
+	return fmt.Sprintf("%s %s %3.0f%%  %s / %s\n", label, bar, usage.Percentage, ui.StatValueStyle.Render(usage.Used), usage.Hard)
+}
+
+// renderUsage shows the latest CPU and memory usage against limits with a
+// sparkline of the sampled history, for the selected pod or namespace
+func (t *Tab) renderUsage() string {
+	namespace, pod := t.metricsTarget()
+	if namespace == "" {
+		return ""
+	}
+
+	var out strings.Builder
+	title := "Usage of namespace " + namespace
+	if pod != "" {
+		title = "Usage of pod " + pod
+	}
+	out.WriteString("\n" + ui.StatLabelStyle.Render(title) + "\n\n")
+
+	switch {
+	case t.metricsKey != namespace+"/"+pod:
+		out.WriteString(ui.InfoStyle.Render("Loading metrics..."))
+		return out.String()
+	case t.metricsErr != nil:
+		out.WriteString(ui.InfoStyle.Render("Metrics history unavailable: " + t.metricsErr.Error()))
+		return out.String()
+	case t.currentMetrics == nil || len(t.currentMetrics.Samples) == 0:
+		out.WriteString(ui.InfoStyle.Render("No metrics sampled yet"))
+		return out.String()
+	}
+
+	samples := t.currentMetrics.Samples
+	latest := samples[len(samples)-1]
+
+	width := ui.CalculateSplitLayout(t.width, t.height).RightWidth - 20
+	if width < 10 {
+		width = 10
+	}
+
+	cpu := make([]float64, len(samples))
+	memory := make([]float64, len(samples))
+	for i, sample := range samples {
+		cpu[i] = float64(sample.CPUUsedMillis)
+		memory[i] = float64(sample.MemoryUsedBytes)
+	}
+
+	out.WriteString(usageLine("CPU", latest.CPUUsedPercentage, latest.CPULimitMillis > 0,
+		fmt.Sprintf("%dm", latest.CPUUsedMillis), fmt.Sprintf("%dm", latest.CPULimitMillis)))
+	out.WriteString(sparklineLine(cpu, width))
+	out.WriteString(usageLine("Memory", latest.MemoryUsedPercentage, latest.MemoryLimitBytes > 0,
+		formatMebibytes(latest.MemoryUsedBytes), formatMebibytes(latest.MemoryLimitBytes)))
+	out.WriteString(sparklineLine(memory, width))
+
+	span := time.Duration(len(samples)*t.currentMetrics.Interval) * time.Second
+	out.WriteString(lipgloss.NewStyle().Foreground(ui.ColorTextDimmer).
+		Render(fmt.Sprintf("%d samples over the last %s", len(samples), span.Round(time.Second))))
+
+	return out.String()
+}
+
+// usageLine renders one resource as a percentage bar of its limit, or as a plain value without one
+func usageLine(name string, percentage float64, limited bool, used, limit string) string {
+	label := ui.StatLabelStyle.Render(fmt.Sprintf("%-7s", name))
+	if !limited {
+		return label + " " + ui.StatValueStyle.Render(used) + " " + ui.InfoStyle.Render("(no limit)") + "\n"
+	}
+
+	color := ui.ColorSuccess
+	switch {
+	case percentage >= 90:
+		color = ui.ColorError
+	case percentage >= 70:
+		color = ui.ColorWarning
+	}
+	bar := lipgloss.NewStyle().Foreground(color).Render(ui.RenderProgressBar(int(percentage), 100, 20))
+	return fmt.Sprintf("%s %s %3.0f%%  %s / %s\n", label, bar, percentage, ui.StatValueStyle.Render(used), limit)
+}
+
+func sparklineLine(values []float64, width int) string {
+	return "        " + lipgloss.NewStyle().Foreground(ui.ColorPrimary).Render(ui.RenderSparkline(values, width)) + "\n\n"
+}
+
+// formatMebibytes renders a byte count the way pod memory is shown elsewhere, e.g. "256Mi"
+func formatMebibytes(bytes int64) string {
+	return fmt.Sprintf("%dMi", bytes/(1024*1024))
+}
+
+// renderForwardsView lists the port forwards opened from this tab
+func (t *Tab) renderForwardsView() string {
+	if len(t.currentForwards) == 0 {
+		return "No active port forwards.\n\nSelect a pod or service and press [F] to forward a local port to it."
+	}
+
+	var forwards strings.Builder
+	for i, f := range t.currentForwards {
+		session := f.Session
+
+		marker := "  "
+		if i == t.forwardIndex && t.panelFocus == FocusRightPanel {
+			marker = "▶ "
+		}
+
+		statusStyle := ui.SuccessStyle
+		switch session.Status {
+		case models.PortForwardFailed:
+			statusStyle = ui.ErrorStyle
+		case models.PortForwardClosed:
+			statusStyle = ui.WarningStyle
+		}
+
+		target := fmt.Sprintf("%s/%s:%d", session.Kind, session.Name, session.Port)
+		forwards.WriteString(marker + ui.ValueStyle.Render(f.LocalAddr) + " → " + ui.ValueStyle.Render(target) + "\n")
+		forwards.WriteString("    " + statusStyle.Render("● "+session.Status) +
+			fmt.Sprintf("  %s  pod %s:%d  age %s\n", session.Namespace, session.Pod, session.TargetPort, formatAge(session.Started)))
+		forwards.WriteString(fmt.Sprintf("    Connections: %d active, %d total\n", f.ActiveConns, f.TotalConns))
+		if session.Error != "" {
+			forwards.WriteString("    " + ui.ErrorStyle.Render("Error: "+session.Error) + "\n")
+		} else if f.LastError != "" {
+			forwards.WriteString("    " + ui.ErrorStyle.Render("Last error: "+f.LastError) + "\n")
+		}
+		forwards.WriteString("\n")
+	}
+
+	return forwards.String()
+}
+
+// renderPodContainers lists each container's state with its reason and last termination
+func renderPodContainers(containers []models.PodContainer) string {
+	var out strings.Builder
+	for _, c := range containers {
+		name := c.Name
+		if c.Init {
+			name += " (init)"
+		}
+
+		state := c.State
+		if c.Reason != "" {
+			state += ": " + c.Reason
+		}
+		color := ui.ColorSuccess
+		switch {
+		case c.State == "Terminated" && c.Reason == "Completed":
+			color = ui.ColorTextDim
+		case c.State == "Waiting" && (c.Reason == "" || c.Reason == "ContainerCreating" || c.Reason == "PodInitializing"):
+			color = ui.ColorRunning
+		case c.State != "Running":
+			color = ui.ColorError
+		case !c.Ready:
+			color = ui.ColorWarning
+		}
+
+		out.WriteString("  " + ui.ValueStyle.Render(name) + "  " + lipgloss.NewStyle().Foreground(color).Render(state) + "\n")
+		out.WriteString("    " + ui.LabelStyle.Render("Image:") + " " + ui.ValueStyle.Render(c.Image) + "\n")
+		out.WriteString("    " + ui.LabelStyle.Render("Ready:") + " " + ui.ValueStyle.Render(fmt.Sprintf("%t", c.Ready)) +
+			"  " + ui.LabelStyle.Render("Restarts:") + " " + ui.ValueStyle.Render(fmt.Sprintf("%d", c.RestartCount)) + "\n")
+		if c.Message != "" {
+			out.WriteString("    " + ui.LabelStyle.Render("Message:") + " " + ui.ValueStyle.Render(c.Message) + "\n")
+		}
+		if c.LastExitCode != nil {
+			last := fmt.Sprintf("%s (exit code %d)", c.LastReason, *c.LastExitCode)
+			if !c.LastFinished.IsZero() {
+				last += ", " + formatAge(c.LastFinished) + " ago"
+			}
+			out.WriteString("    " + ui.LabelStyle.Render("Last State:") + " " + ui.ValueStyle.Render(last) + "\n")
+		}
+	}
+	return out.String()
+}
@@ -27,6 +27,8 @@ func (t *Tab) renderRightPanel(height int) string {
 		{"Logs", RightPanelLogs},
 		{"Events", RightPanelEvents},
 		{"Stats", RightPanelStats},
+		{"Forwards", RightPanelForwards},
+		{"YAML", RightPanelYAML},
 	}
 
 	// Styles for view list
@@ -108,6 +110,10 @@ func (t *Tab) renderRightPanel(height int) string {
 		panelName = "Events"
 	case RightPanelStats:
 		panelName = "Stats"
+	case RightPanelForwards:
+		panelName = "Port Forwards"
+	case RightPanelYAML:
+		panelName = "YAML"
 	}
 
 	content.WriteString(titleStyle.Render(panelName))
@@ -124,6 +130,10 @@ func (t *Tab) renderRightPanel(height int) string {
 		viewContent = t.renderEventsView()
 	case RightPanelStats:
 		viewContent = t.renderStatsView()
+	case RightPanelForwards:
+		viewContent = t.renderForwardsView()
+	case RightPanelYAML:
+		viewContent = t.renderYAMLView()
 	}
 
 	// Hard wrap the content to prevent lipgloss from truncating it
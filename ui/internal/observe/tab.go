@@ -3,6 +3,7 @@ package observe
 import (
 	tea "github.com/charmbracelet/bubbletea"
 	"imperm-ui/internal/config"
+	"imperm-ui/internal/portforward"
 	"imperm-ui/pkg/client"
 	"imperm-ui/pkg/models"
 )
@@ -19,6 +20,7 @@ func NewTab(client client.Client) *Tab {
 		refreshInterval: config.ResourceRefreshInterval,
 		isLoading:       true, // Start in loading state
 		rightPanelView:  RightPanelLogs, // Default to Logs view for auto-updating
+		forwards:        portforward.NewManager(client),
 	}
 }
 
@@ -26,6 +28,16 @@ func (t *Tab) Init() tea.Cmd {
 	return tea.Batch(t.loadResources, t.tick())
 }
 
+// Close stops the port forwards opened from this tab
+func (t *Tab) Close() {
+	t.forwards.StopAll()
+}
+
+// InputActive reports whether a prompt is capturing keyboard input
+func (t *Tab) InputActive() bool {
+	return t.promptMode != PromptNone
+}
+
 func (t *Tab) getMaxIndex() int {
 	switch t.currentResource {
 	case ResourceEnvironments:
@@ -40,6 +52,18 @@ func (t *Tab) getMaxIndex() int {
 			return len(t.selectedEnvironment.Deployments)
 		}
 		return len(t.deployments)
+	case ResourceServices:
+		return len(t.services)
+	case ResourceConfigMaps:
+		return len(t.configMaps)
+	case ResourceSecrets:
+		return len(t.secrets)
+	case ResourceIngresses:
+		return len(t.ingresses)
+	case ResourceJobs:
+		return len(t.jobs)
+	case ResourceStatefulSets:
+		return len(t.statefulSets)
 	default:
 		return 0
 	}
@@ -63,6 +87,18 @@ func (t *Tab) getSelectedResource() interface{} {
 			return t.selectedEnvironment.Deployments[t.selectedIndex]
 		}
 		return t.deployments[t.selectedIndex]
+	case ResourceServices:
+		return t.services[t.selectedIndex]
+	case ResourceConfigMaps:
+		return t.configMaps[t.selectedIndex]
+	case ResourceSecrets:
+		return t.secrets[t.selectedIndex]
+	case ResourceIngresses:
+		return t.ingresses[t.selectedIndex]
+	case ResourceJobs:
+		return t.jobs[t.selectedIndex]
+	case ResourceStatefulSets:
+		return t.statefulSets[t.selectedIndex]
 	}
 	return nil
 }
@@ -83,6 +119,34 @@ func (t *Tab) getCurrentDeployments() []models.Deployment {
 	return t.deployments
 }
 
+// resourceTitle returns the display name of the current resource type
+func (t *Tab) resourceTitle() string {
+	switch t.currentResource {
+	case ResourceEnvironments:
+		if t.showUnmanaged {
+			return "Environments (incl. unmanaged)"
+		}
+		return "Environments"
+	case ResourcePods:
+		return "Pods"
+	case ResourceDeployments:
+		return "Deployments"
+	case ResourceServices:
+		return "Services"
+	case ResourceConfigMaps:
+		return "ConfigMaps"
+	case ResourceSecrets:
+		return "Secrets"
+	case ResourceIngresses:
+		return "Ingresses"
+	case ResourceJobs:
+		return "Jobs"
+	case ResourceStatefulSets:
+		return "StatefulSets"
+	}
+	return ""
+}
+
 func (t *Tab) GetHeaderInfo() string {
 	var resourceName string
 	switch t.currentResource {
@@ -100,6 +164,11 @@ func (t *Tab) GetHeaderInfo() string {
 		} else {
 			resourceName = "Deployments"
 		}
+	default:
+		resourceName = t.resourceTitle()
+		if t.selectedEnvironment != nil {
+			resourceName = t.selectedEnvironment.Name + " > " + resourceName
+		}
 	}
 
 	autoRefreshIndicator := ""
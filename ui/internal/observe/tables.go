@@ -2,6 +2,7 @@ package observe
 
 import (
 	"fmt"
+	"imperm-ui/internal/ui"
 	"imperm-ui/pkg/models"
 	"strings"
 	"time"
@@ -14,6 +15,7 @@ type TableColumn struct {
 	Header string
 	Width  int
 	Value  func(item interface{}) string
+	Color  func(item interface{}) lipgloss.TerminalColor // Optional foreground for the cell, nil for the row's own
 }
 
 // renderGenericTable renders a table with the given columns and items
@@ -45,7 +47,13 @@ func renderGenericTable(
 		var rowCols []string
 		for _, col := range columns {
 			value := col.Value(item)
-			rowCols = append(rowCols, style.Width(col.Width).Render(value))
+			cellStyle := style.Width(col.Width)
+			if col.Color != nil {
+				if color := col.Color(item); color != nil {
+					cellStyle = cellStyle.Foreground(color)
+				}
+			}
+			rowCols = append(rowCols, cellStyle.Render(value))
 		}
 
 		table.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, rowCols...))
@@ -80,6 +88,13 @@ func (t *Tab) renderEnvironmentsTable(headerStyle, rowStyle, selectedStyle lipgl
 				env := item.(models.Environment)
 				return truncate(env.Name, 28)
 			},
+			Color: func(item interface{}) lipgloss.TerminalColor {
+				// Unmanaged namespaces are only shown for reference
+				if !item.(models.Environment).Managed {
+					return ui.ColorTextDim
+				}
+				return nil
+			},
 		},
 		{
 			Header: "NAMESPACE",
@@ -94,8 +109,50 @@ func (t *Tab) renderEnvironmentsTable(headerStyle, rowStyle, selectedStyle lipgl
 			Width:  15,
 			Value: func(item interface{}) string {
 				env := item.(models.Environment)
+				if env.Interrupted != nil {
+					return "Interrupted"
+				}
 				return env.Status
 			},
+			Color: func(item interface{}) lipgloss.TerminalColor {
+				if item.(models.Environment).Interrupted != nil {
+					return ui.ColorError
+				}
+				return nil
+			},
+		},
+		{
+			Header: "HEALTH",
+			Width:  15,
+			Value: func(item interface{}) string {
+				env := item.(models.Environment)
+				return env.Health.Status
+			},
+			Color: func(item interface{}) lipgloss.TerminalColor {
+				return healthColor(item.(models.Environment).Health.Status)
+			},
+		},
+		{
+			Header: "DRIFT",
+			Width:  10,
+			Value: func(item interface{}) string {
+				env := item.(models.Environment)
+				switch {
+				case env.Drift == nil:
+					return "-"
+				case env.Drift.Error != "":
+					return "unknown"
+				case env.Drift.Drifted:
+					return "drifted"
+				}
+				return "in sync"
+			},
+			Color: func(item interface{}) lipgloss.TerminalColor {
+				if drift := item.(models.Environment).Drift; drift != nil && drift.Drifted {
+					return ui.ColorWarning
+				}
+				return nil
+			},
 		},
 		{
 			Header: "AGE",
@@ -147,9 +204,11 @@ func (t *Tab) renderPodsTable(headerStyle, rowStyle, selectedStyle lipgloss.Styl
 			pod := item.(models.Pod)
 			return pod.Ready
 		}},
-		{Header: "STATUS", Width: 12, Value: func(item interface{}) string {
+		{Header: "STATUS", Width: 20, Value: func(item interface{}) string {
 			pod := item.(models.Pod)
-			return pod.Status
+			return truncate(pod.Status, 18)
+		}, Color: func(item interface{}) lipgloss.TerminalColor {
+			return podStatusColor(item.(models.Pod).Status)
 		}},
 		{Header: "RESTARTS", Width: 10, Value: func(item interface{}) string {
 			pod := item.(models.Pod)
@@ -218,6 +277,254 @@ func (t *Tab) renderDeploymentsTable(headerStyle, rowStyle, selectedStyle lipglo
 	return renderGenericTable(items, columns, t.selectedIndex, t.isLoading, "deployments", headerStyle, rowStyle, selectedStyle)
 }
 
+func (t *Tab) renderServicesTable(headerStyle, rowStyle, selectedStyle lipgloss.Style) string {
+	items := make([]interface{}, len(t.services))
+	for i, svc := range t.services {
+		items[i] = svc
+	}
+
+	columns := []TableColumn{
+		{Header: "NAME", Width: 28, Value: func(item interface{}) string {
+			svc := item.(models.Service)
+			return truncate(svc.Name, 26)
+		}},
+		{Header: "NAMESPACE", Width: 15, Value: func(item interface{}) string {
+			svc := item.(models.Service)
+			return truncate(svc.Namespace, 13)
+		}},
+		{Header: "TYPE", Width: 14, Value: func(item interface{}) string {
+			svc := item.(models.Service)
+			return svc.Type
+		}},
+		{Header: "CLUSTER-IP", Width: 16, Value: func(item interface{}) string {
+			svc := item.(models.Service)
+			return svc.ClusterIP
+		}},
+		{Header: "EXTERNAL-IP", Width: 16, Value: func(item interface{}) string {
+			svc := item.(models.Service)
+			if svc.ExternalIP == "" {
+				return "<none>"
+			}
+			return truncate(svc.ExternalIP, 14)
+		}},
+		{Header: "PORTS", Width: 20, Value: func(item interface{}) string {
+			svc := item.(models.Service)
+			return truncate(formatServicePorts(svc.Ports), 18)
+		}},
+		{Header: "AGE", Width: 8, Value: func(item interface{}) string {
+			svc := item.(models.Service)
+			return formatAge(svc.Age)
+		}},
+	}
+
+	return renderGenericTable(items, columns, t.selectedIndex, t.isLoading, "services", headerStyle, rowStyle, selectedStyle)
+}
+
+func (t *Tab) renderConfigMapsTable(headerStyle, rowStyle, selectedStyle lipgloss.Style) string {
+	items := make([]interface{}, len(t.configMaps))
+	for i, cm := range t.configMaps {
+		items[i] = cm
+	}
+
+	columns := []TableColumn{
+		{Header: "NAME", Width: 35, Value: func(item interface{}) string {
+			cm := item.(models.ConfigMap)
+			return truncate(cm.Name, 33)
+		}},
+		{Header: "NAMESPACE", Width: 20, Value: func(item interface{}) string {
+			cm := item.(models.ConfigMap)
+			return truncate(cm.Namespace, 18)
+		}},
+		{Header: "DATA", Width: 8, Value: func(item interface{}) string {
+			cm := item.(models.ConfigMap)
+			return fmt.Sprintf("%d", len(cm.Data)+len(cm.BinaryKeys))
+		}},
+		{Header: "AGE", Width: 15, Value: func(item interface{}) string {
+			cm := item.(models.ConfigMap)
+			return formatAge(cm.Age)
+		}},
+	}
+
+	return renderGenericTable(items, columns, t.selectedIndex, t.isLoading, "config maps", headerStyle, rowStyle, selectedStyle)
+}
+
+func (t *Tab) renderSecretsTable(headerStyle, rowStyle, selectedStyle lipgloss.Style) string {
+	items := make([]interface{}, len(t.secrets))
+	for i, secret := range t.secrets {
+		items[i] = secret
+	}
+
+	columns := []TableColumn{
+		{Header: "NAME", Width: 30, Value: func(item interface{}) string {
+			secret := item.(models.Secret)
+			return truncate(secret.Name, 28)
+		}},
+		{Header: "NAMESPACE", Width: 18, Value: func(item interface{}) string {
+			secret := item.(models.Secret)
+			return truncate(secret.Namespace, 16)
+		}},
+		{Header: "TYPE", Width: 28, Value: func(item interface{}) string {
+			secret := item.(models.Secret)
+			return truncate(secret.Type, 26)
+		}},
+		{Header: "DATA", Width: 8, Value: func(item interface{}) string {
+			secret := item.(models.Secret)
+			return fmt.Sprintf("%d", len(secret.Data))
+		}},
+		{Header: "AGE", Width: 10, Value: func(item interface{}) string {
+			secret := item.(models.Secret)
+			return formatAge(secret.Age)
+		}},
+	}
+
+	return renderGenericTable(items, columns, t.selectedIndex, t.isLoading, "secrets", headerStyle, rowStyle, selectedStyle)
+}
+
+func (t *Tab) renderIngressesTable(headerStyle, rowStyle, selectedStyle lipgloss.Style) string {
+	items := make([]interface{}, len(t.ingresses))
+	for i, ing := range t.ingresses {
+		items[i] = ing
+	}
+
+	columns := []TableColumn{
+		{Header: "NAME", Width: 25, Value: func(item interface{}) string {
+			ing := item.(models.Ingress)
+			return truncate(ing.Name, 23)
+		}},
+		{Header: "NAMESPACE", Width: 15, Value: func(item interface{}) string {
+			ing := item.(models.Ingress)
+			return truncate(ing.Namespace, 13)
+		}},
+		{Header: "CLASS", Width: 10, Value: func(item interface{}) string {
+			ing := item.(models.Ingress)
+			if ing.Class == "" {
+				return "<none>"
+			}
+			return truncate(ing.Class, 8)
+		}},
+		{Header: "HOSTS", Width: 25, Value: func(item interface{}) string {
+			ing := item.(models.Ingress)
+			if len(ing.Hosts) == 0 {
+				return "*"
+			}
+			return truncate(strings.Join(ing.Hosts, ","), 23)
+		}},
+		{Header: "ADDRESS", Width: 16, Value: func(item interface{}) string {
+			ing := item.(models.Ingress)
+			return truncate(ing.Address, 14)
+		}},
+		{Header: "PORTS", Width: 8, Value: func(item interface{}) string {
+			ing := item.(models.Ingress)
+			if ing.TLS {
+				return "80, 443"
+			}
+			return "80"
+		}},
+		{Header: "AGE", Width: 8, Value: func(item interface{}) string {
+			ing := item.(models.Ingress)
+			return formatAge(ing.Age)
+		}},
+	}
+
+	return renderGenericTable(items, columns, t.selectedIndex, t.isLoading, "ingresses", headerStyle, rowStyle, selectedStyle)
+}
+
+func (t *Tab) renderJobsTable(headerStyle, rowStyle, selectedStyle lipgloss.Style) string {
+	items := make([]interface{}, len(t.jobs))
+	for i, job := range t.jobs {
+		items[i] = job
+	}
+
+	columns := []TableColumn{
+		{Header: "NAME", Width: 30, Value: func(item interface{}) string {
+			job := item.(models.Job)
+			return truncate(job.Name, 28)
+		}},
+		{Header: "NAMESPACE", Width: 18, Value: func(item interface{}) string {
+			job := item.(models.Job)
+			return truncate(job.Namespace, 16)
+		}},
+		{Header: "STATUS", Width: 12, Value: func(item interface{}) string {
+			job := item.(models.Job)
+			return job.Status
+		}},
+		{Header: "COMPLETIONS", Width: 13, Value: func(item interface{}) string {
+			job := item.(models.Job)
+			return job.Completions
+		}},
+		{Header: "DURATION", Width: 10, Value: func(item interface{}) string {
+			job := item.(models.Job)
+			return formatJobDuration(job)
+		}},
+		{Header: "AGE", Width: 10, Value: func(item interface{}) string {
+			job := item.(models.Job)
+			return formatAge(job.Age)
+		}},
+	}
+
+	return renderGenericTable(items, columns, t.selectedIndex, t.isLoading, "jobs", headerStyle, rowStyle, selectedStyle)
+}
+
+func (t *Tab) renderStatefulSetsTable(headerStyle, rowStyle, selectedStyle lipgloss.Style) string {
+	items := make([]interface{}, len(t.statefulSets))
+	for i, sts := range t.statefulSets {
+		items[i] = sts
+	}
+
+	columns := []TableColumn{
+		{Header: "NAME", Width: 35, Value: func(item interface{}) string {
+			sts := item.(models.StatefulSet)
+			return truncate(sts.Name, 33)
+		}},
+		{Header: "NAMESPACE", Width: 20, Value: func(item interface{}) string {
+			sts := item.(models.StatefulSet)
+			return truncate(sts.Namespace, 18)
+		}},
+		{Header: "READY", Width: 10, Value: func(item interface{}) string {
+			sts := item.(models.StatefulSet)
+			return sts.Ready
+		}},
+		{Header: "SERVICE", Width: 20, Value: func(item interface{}) string {
+			sts := item.(models.StatefulSet)
+			return truncate(sts.ServiceName, 18)
+		}},
+		{Header: "AGE", Width: 15, Value: func(item interface{}) string {
+			sts := item.(models.StatefulSet)
+			return formatAge(sts.Age)
+		}},
+	}
+
+	return renderGenericTable(items, columns, t.selectedIndex, t.isLoading, "stateful sets", headerStyle, rowStyle, selectedStyle)
+}
+
+// formatServicePorts renders ports like kubectl, e.g. "80:30080/TCP,443/TCP"
+func formatServicePorts(ports []models.ServicePort) string {
+	if len(ports) == 0 {
+		return "<none>"
+	}
+	parts := make([]string, 0, len(ports))
+	for _, p := range ports {
+		part := fmt.Sprintf("%d", p.Port)
+		if p.NodePort != 0 {
+			part += fmt.Sprintf(":%d", p.NodePort)
+		}
+		parts = append(parts, part+"/"+p.Protocol)
+	}
+	return strings.Join(parts, ",")
+}
+
+// formatJobDuration returns how long a job ran, or has been running
+func formatJobDuration(job models.Job) string {
+	if job.StartTime == nil {
+		return "-"
+	}
+	end := time.Now()
+	if job.EndTime != nil {
+		end = *job.EndTime
+	}
+	return formatAge(time.Now().Add(-end.Sub(*job.StartTime)))
+}
+
 func formatAge(t time.Time) string {
 	duration := time.Since(t)
 
@@ -232,6 +539,35 @@ func formatAge(t time.Time) string {
 	}
 }
 
+// healthColor picks the colour an environment health status is shown in
+func healthColor(status string) lipgloss.TerminalColor {
+	switch status {
+	case models.HealthHealthy:
+		return ui.ColorSuccess
+	case models.HealthProvisioning:
+		return ui.ColorRunning
+	case models.HealthDegraded:
+		return ui.ColorWarning
+	case models.HealthFailing:
+		return ui.ColorError
+	}
+	return ui.ColorTextDim
+}
+
+// podStatusColor picks the colour for a kubectl-style pod status
+func podStatusColor(status string) lipgloss.TerminalColor {
+	switch {
+	case status == "Running", status == "Completed", status == "Succeeded":
+		return ui.ColorSuccess
+	case status == "Pending", status == "ContainerCreating", status == "PodInitializing",
+		status == "Terminating", strings.HasPrefix(status, "Init:") && !strings.Contains(status, "Err") && !strings.Contains(status, "BackOff"):
+		return ui.ColorRunning
+	case status == "NotReady":
+		return ui.ColorWarning
+	}
+	return ui.ColorError
+}
+
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
 		return s
@@ -3,6 +3,8 @@ package observe
 import (
 	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
+	"imperm-ui/internal/portforward"
 	"imperm-ui/pkg/client"
 	"imperm-ui/pkg/models"
 )
@@ -13,6 +15,12 @@ const (
 	ResourceEnvironments resourceType = iota
 	ResourcePods
 	ResourceDeployments
+	ResourceServices
+	ResourceConfigMaps
+	ResourceSecrets
+	ResourceIngresses
+	ResourceJobs
+	ResourceStatefulSets
 )
 
 type panelFocus int
@@ -29,20 +37,67 @@ const (
 	RightPanelLogs
 	RightPanelEvents
 	RightPanelStats
+	RightPanelForwards
+	RightPanelYAML
 )
 
+// timelineKinds are the involved object kinds the environment timeline can be filtered by
+var timelineKinds = []string{"", "Environment", "Pod", "Deployment", "ReplicaSet", "StatefulSet", "Job", "Service", "Ingress"}
+
+type promptType int
+
+const (
+	PromptNone promptType = iota
+	PromptScale
+	PromptRollback
+	PromptExec
+	PromptPortForward
+	PromptClone
+)
+
+// deploymentAction names a change made to a deployment from the observe tab
+type deploymentAction string
+
+const (
+	actionScale    deploymentAction = "Scaling"
+	actionRestart  deploymentAction = "Restarting"
+	actionPause    deploymentAction = "Pausing"
+	actionResume   deploymentAction = "Resuming"
+	actionRollback deploymentAction = "Rolling back"
+)
+
+// rolloutWatch tracks a deployment after a change until its rollout settles
+type rolloutWatch struct {
+	namespace  string
+	deployment string
+	action     deploymentAction
+	target     int // Desired replica count
+	upToDate   int
+	available  int
+	started    time.Time
+	done       bool
+	failed     bool
+}
+
 type Tab struct {
 	client          client.Client
 	currentResource resourceType
 	environments    []models.Environment
 	pods            []models.Pod
 	deployments     []models.Deployment
+	services        []models.Service
+	configMaps      []models.ConfigMap
+	secrets         []models.Secret
+	ingresses       []models.Ingress
+	jobs            []models.Job
+	statefulSets    []models.StatefulSet
 	selectedIndex   int
 	width           int
 	height          int
 	lastUpdate      time.Time
 	autoRefresh     bool
 	refreshInterval time.Duration
+	showUnmanaged   bool // Also list namespaces the server's discovery doesn't manage
 
 	// Drill-down state
 	selectedEnvironment *models.Environment
@@ -57,10 +112,22 @@ type Tab struct {
 
 	// Right panel data
 	currentLogs        string
-	currentEvents      []models.Event
+	currentEvents      []models.EventTimeEntry
 	currentStats       *models.ResourceStats
 	lastPodName        string // Track last pod name for logs refresh
 	lastDeploymentName string // Track last deployment name for events refresh
+	currentHistory     []models.DeploymentRevision
+	historyDeployment  string         // Deployment the loaded history belongs to
+	revealedSecret     *models.Secret // Secret whose values were explicitly revealed
+	currentManifest    *models.ResourceManifest
+	manifestKey        string // kind/namespace/name the loaded manifest belongs to
+	manifestErr        error
+	currentTimeline    []models.TimelineEvent
+	timelineEnv        string // Environment the loaded timeline belongs to
+	timelineFilter     models.EventFilter
+	currentMetrics     *models.MetricsHistory
+	metricsKey         string // namespace/pod the loaded metrics history belongs to
+	metricsErr         error
 
 	// Error tracking
 	lastError error
@@ -73,6 +140,19 @@ type Tab struct {
 	// Loading state
 	isLoading bool
 
+	// Prompt input (e.g. replica count when scaling)
+	promptMode   promptType
+	promptInput  textinput.Model
+	promptTarget interface{} // Resource the prompt acts on, captured when opened
+
+	// Rollout progress for the last scaled deployment
+	rollout *rolloutWatch
+
+	// Port forwards opened from this tab
+	forwards        *portforward.Manager
+	currentForwards []portforward.Status
+	forwardIndex    int // Selected forward in the Forwards panel
+
 	// Caching for performance
 	cachedWrappedContent string
 	cachedPanelWidth     int
@@ -85,6 +165,12 @@ type resourcesLoadedMsg struct {
 	environments []models.Environment
 	pods         []models.Pod
 	deployments  []models.Deployment
+	services     []models.Service
+	configMaps   []models.ConfigMap
+	secrets      []models.Secret
+	ingresses    []models.Ingress
+	jobs         []models.Job
+	statefulSets []models.StatefulSet
 }
 
 type logsLoadedMsg struct {
@@ -93,11 +179,103 @@ type logsLoadedMsg struct {
 }
 
 type eventsLoadedMsg struct {
-	events []models.Event
+	events []models.EventTimeEntry
+}
+
+type timelineLoadedMsg struct {
+	env    string
+	events []models.TimelineEvent
+	err    error
 }
 
 type statsLoadedMsg struct {
 	stats *models.ResourceStats
 }
 
+type metricsHistoryLoadedMsg struct {
+	key     string
+	history *models.MetricsHistory
+	err     error
+}
+
 type resourceDeletedMsg struct{}
+
+type secretRevealedMsg struct {
+	secret *models.Secret
+	err    error
+}
+
+type deploymentActionMsg struct {
+	namespace  string
+	deployment string
+	action     deploymentAction
+	target     int    // Replica count the rollout should settle at
+	detail     string // Extra context for the status message, e.g. "to 3 replicas"
+	err        error
+}
+
+type manifestLoadedMsg struct {
+	key      string
+	manifest *models.ResourceManifest
+	err      error
+}
+
+type historyLoadedMsg struct {
+	deployment string
+	history    []models.DeploymentRevision
+}
+
+type forwardsLoadedMsg struct {
+	forwards []portforward.Status
+}
+
+type portForwardStartedMsg struct {
+	forward portforward.Status
+	err     error
+}
+
+type portForwardStoppedMsg struct {
+	id  string
+	err error
+}
+
+type environmentClonedMsg struct {
+	source  string
+	options *models.DeploymentOptions
+	err     error
+}
+
+type environmentAdoptedMsg struct {
+	name    string
+	options *models.DeploymentOptions
+	err     error
+}
+
+type environmentReconciledMsg struct {
+	name string
+	err  error
+}
+
+// environmentRecoveredMsg reports resuming or rolling back an interrupted operation
+type environmentRecoveredMsg struct {
+	name     string
+	rollback bool
+	err      error
+}
+
+type execFinishedMsg struct {
+	pod string
+	err error
+}
+
+type rolloutTickMsg struct{}
+
+type rolloutProgressMsg struct {
+	namespace  string
+	deployment string
+	found      bool
+	replicas   int
+	upToDate   int
+	available  int
+	complete   bool
+}
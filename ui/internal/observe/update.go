@@ -1,12 +1,16 @@
 package observe
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"imperm-ui/internal/config"
 	"imperm-ui/internal/messages"
+	"imperm-ui/pkg/models"
 )
 
 func (t *Tab) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -27,6 +31,12 @@ func (t *Tab) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		t.environments = msg.environments
 		t.pods = msg.pods
 		t.deployments = msg.deployments
+		t.services = msg.services
+		t.configMaps = msg.configMaps
+		t.secrets = msg.secrets
+		t.ingresses = msg.ingresses
+		t.jobs = msg.jobs
+		t.statefulSets = msg.statefulSets
 		t.lastUpdate = time.Now()
 		t.lastError = nil   // Clear any previous errors
 		t.isLoading = false // Data loaded successfully
@@ -81,9 +91,22 @@ func (t *Tab) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case eventsLoadedMsg:
 		t.currentEvents = msg.events
 
+	case timelineLoadedMsg:
+		if msg.err != nil {
+			return t, t.setStatus("error", "❌ Loading events of %s failed: %v", msg.env, msg.err)
+		}
+		t.currentTimeline = msg.events
+		t.timelineEnv = msg.env
+
 	case statsLoadedMsg:
 		t.currentStats = msg.stats
 
+	case metricsHistoryLoadedMsg:
+		// Usage history is optional, e.g. when sampling is disabled on the server
+		t.currentMetrics = msg.history
+		t.metricsKey = msg.key
+		t.metricsErr = msg.err
+
 	case messages.ErrMsg:
 		// Display error as a status message
 		t.lastError = nil   // Don't show full-screen error
@@ -93,13 +116,116 @@ func (t *Tab) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case messages.ClearStatusMsg:
 		// Clear the status message
 		t.statusMessage = ""
+		// Drop a finished rollout along with its final status
+		if t.rollout != nil && t.rollout.done {
+			t.rollout = nil
+		}
 		return t, nil
 
 	case resourceDeletedMsg:
 		// Success message already shown immediately, just reload resources
 		return t, t.loadResources
 
+	case deploymentActionMsg:
+		return t, t.handleDeploymentAction(msg)
+
+	case secretRevealedMsg:
+		if msg.err != nil {
+			return t, t.setStatus("error", "❌ Revealing secret failed: %v", msg.err)
+		}
+		t.revealedSecret = msg.secret
+		t.rightPanelView = RightPanelDetails
+		t.scrollOffset = 0
+		return t, tea.Batch(t.loadManifest(), t.setStatus("success", "✓ Revealed secret %s (press V to hide)", msg.secret.Name))
+
+	case manifestLoadedMsg:
+		t.currentManifest = msg.manifest
+		t.manifestKey = msg.key
+		t.manifestErr = msg.err
+
+	case historyLoadedMsg:
+		t.currentHistory = msg.history
+		t.historyDeployment = msg.deployment
+
+	case forwardsLoadedMsg:
+		t.currentForwards = msg.forwards
+		if t.forwardIndex >= len(t.currentForwards) {
+			t.forwardIndex = len(t.currentForwards) - 1
+		}
+		if t.forwardIndex < 0 {
+			t.forwardIndex = 0
+		}
+
+	case portForwardStartedMsg:
+		if msg.err != nil {
+			return t, t.setStatus("error", "❌ Port forward failed: %v", msg.err)
+		}
+		// Show the new forward in the Forwards panel
+		t.rightPanelView = RightPanelForwards
+		t.scrollOffset = 0
+		session := msg.forward.Session
+		return t, tea.Batch(
+			t.loadForwards(),
+			t.setStatus("success", "✓ Forwarding %s → %s/%s:%d", msg.forward.LocalAddr, session.Kind, session.Name, session.Port),
+		)
+
+	case portForwardStoppedMsg:
+		if msg.err != nil {
+			return t, tea.Batch(t.loadForwards(), t.setStatus("error", "❌ Stopping port forward failed: %v", msg.err))
+		}
+		return t, tea.Batch(t.loadForwards(), t.setStatus("success", "✓ Stopped port forward %s", msg.id))
+
+	case environmentClonedMsg:
+		if msg.err != nil {
+			return t, t.setStatus("error", "❌ Cloning %s failed: %v", msg.source, msg.err)
+		}
+		return t, tea.Batch(t.loadResources, t.setStatus("success", "✓ Cloned %s as %s", msg.source, msg.options.Name))
+
+	case environmentAdoptedMsg:
+		if msg.err != nil {
+			return t, t.setStatus("error", "❌ Adopting %s failed: %v", msg.name, msg.err)
+		}
+		return t, tea.Batch(t.loadResources, t.setStatus("success", "✓ Adopted %s with %d detected variables", msg.name, len(msg.options.Variables)))
+
+	case environmentReconciledMsg:
+		if msg.err != nil {
+			return t, t.setStatus("error", "❌ Reconciling %s failed: %v", msg.name, msg.err)
+		}
+		return t, tea.Batch(t.loadResources, t.setStatus("success", "✓ Reconciled %s with its Terraform configuration", msg.name))
+
+	case environmentRecoveredMsg:
+		if msg.rollback {
+			if msg.err != nil {
+				return t, t.setStatus("error", "❌ Rolling back %s failed: %v", msg.name, msg.err)
+			}
+			return t, tea.Batch(t.loadResources, t.setStatus("success", "✓ Rolled back %s", msg.name))
+		}
+		if msg.err != nil {
+			return t, t.setStatus("error", "❌ Resuming %s failed: %v", msg.name, msg.err)
+		}
+		return t, tea.Batch(t.loadResources, t.setStatus("success", "✓ Resumed %s", msg.name))
+
+	case execFinishedMsg:
+		if msg.err != nil {
+			return t, t.setStatus("error", "❌ Exec in %s failed: %v", msg.pod, msg.err)
+		}
+		return t, tea.Batch(t.loadResources, t.setStatus("success", "✓ Exec session in %s ended", msg.pod))
+
+	case rolloutTickMsg:
+		if t.rollout == nil || t.rollout.done {
+			return t, nil
+		}
+		return t, t.loadRolloutProgress(t.rollout.namespace, t.rollout.deployment)
+
+	case rolloutProgressMsg:
+		return t, t.updateRollout(msg)
+
 	case tea.KeyMsg:
+		// An open prompt captures all keys until confirmed or cancelled
+		if t.promptMode != PromptNone {
+			return t.updatePrompt(msg)
+		}
+
 		switch msg.String() {
 		case "left", "h":
 			if t.panelFocus == FocusTable {
@@ -122,7 +248,7 @@ func (t *Tab) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return t, t.loadDataForCurrentView()
 			} else {
 				// Cycle right through right panel views
-				if t.rightPanelView < RightPanelStats {
+				if t.rightPanelView < RightPanelYAML {
 					t.rightPanelView++
 					t.scrollOffset = 0 // Reset scroll when changing views
 					return t, t.loadDataForCurrentView()
@@ -132,9 +258,15 @@ func (t *Tab) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if t.panelFocus == FocusTable {
 				if t.selectedIndex > 0 {
 					t.selectedIndex--
+					t.revealedSecret = nil
 					// Reload data for the newly selected resource
 					return t, t.loadDataForCurrentView()
 				}
+			} else if t.rightPanelView == RightPanelForwards {
+				// Select the previous port forward
+				if t.forwardIndex > 0 {
+					t.forwardIndex--
+				}
 			} else {
 				// Scroll up in right panel
 				if t.scrollOffset > 0 {
@@ -146,9 +278,15 @@ func (t *Tab) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				maxIndex := t.getMaxIndex()
 				if t.selectedIndex < maxIndex-1 {
 					t.selectedIndex++
+					t.revealedSecret = nil
 					// Reload data for the newly selected resource
 					return t, t.loadDataForCurrentView()
 				}
+			} else if t.rightPanelView == RightPanelForwards {
+				// Select the next port forward
+				if t.forwardIndex < len(t.currentForwards)-1 {
+					t.forwardIndex++
+				}
 			} else {
 				// Scroll down in right panel
 				t.scrollOffset++
@@ -190,6 +328,29 @@ func (t *Tab) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			t.selectedIndex = 0
 			t.panelFocus = FocusTable
 			return t, t.loadDataForCurrentView()
+		case "s":
+			return t, t.switchResource(ResourceServices)
+		case "c":
+			return t, t.switchResource(ResourceConfigMaps)
+		case "t":
+			return t, t.switchResource(ResourceSecrets)
+		case "i":
+			return t, t.switchResource(ResourceIngresses)
+		case "b":
+			return t, t.switchResource(ResourceJobs)
+		case "f":
+			return t, t.switchResource(ResourceStatefulSets)
+		case "V":
+			// Reveal or hide the values of the selected secret
+			if t.panelFocus == FocusTable && t.currentResource == ResourceSecrets {
+				if secret, ok := t.getSelectedResource().(models.Secret); ok {
+					if t.isRevealed(secret) {
+						t.revealedSecret = nil
+						return t, t.loadManifest()
+					}
+					return t, t.revealSecret(secret)
+				}
+			}
 		case "r":
 			// Manual refresh - also clears errors
 			t.lastError = nil
@@ -198,7 +359,141 @@ func (t *Tab) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "a":
 			// Toggle auto-refresh
 			t.autoRefresh = !t.autoRefresh
+		case "u":
+			// Show or hide namespaces the server doesn't manage as environments
+			if t.panelFocus == FocusTable && t.currentResource == ResourceEnvironments {
+				t.showUnmanaged = !t.showUnmanaged
+				t.selectedIndex = 0
+				t.isLoading = true
+				if t.showUnmanaged {
+					return t, tea.Batch(t.loadResources, t.setStatus("success", "Showing unmanaged namespaces"))
+				}
+				return t, tea.Batch(t.loadResources, t.setStatus("success", "Hiding unmanaged namespaces"))
+			}
+		case "S":
+			// Scale the selected deployment
+			if t.panelFocus == FocusTable && t.currentResource == ResourceDeployments {
+				if dep, ok := t.getSelectedResource().(models.Deployment); ok {
+					return t, t.startPrompt(PromptScale, dep, "replicas", strconv.Itoa(dep.Replicas))
+				}
+			}
+		case "R":
+			// Rolling restart of the selected deployment
+			if t.panelFocus == FocusTable && t.currentResource == ResourceDeployments {
+				if dep, ok := t.getSelectedResource().(models.Deployment); ok {
+					return t, t.restartDeployment(dep)
+				}
+			}
+		case "P":
+			// Pause or resume rollouts of the selected deployment
+			if t.panelFocus == FocusTable && t.currentResource == ResourceDeployments {
+				if dep, ok := t.getSelectedResource().(models.Deployment); ok {
+					return t, t.togglePauseDeployment(dep)
+				}
+			}
+		case "U":
+			// Roll back the selected deployment (blank revision = previous)
+			if t.panelFocus == FocusTable && t.currentResource == ResourceDeployments {
+				if dep, ok := t.getSelectedResource().(models.Deployment); ok {
+					return t, t.startPrompt(PromptRollback, dep, "previous", "")
+				}
+			}
+		case "E":
+			// Open a shell in the selected pod (blank container = default)
+			if t.panelFocus == FocusTable && t.currentResource == ResourcePods {
+				if pod, ok := t.getSelectedResource().(models.Pod); ok {
+					return t, t.startPrompt(PromptExec, pod, "default container", "")
+				}
+			}
+		case "F":
+			// Forward a local port to the selected pod or service
+			if t.panelFocus == FocusTable && (t.currentResource == ResourcePods || t.currentResource == ResourceServices) {
+				switch r := t.getSelectedResource().(type) {
+				case models.Pod:
+					return t, t.startPrompt(PromptPortForward, r, "port or local:remote", "")
+				case models.Service:
+					value := ""
+					if len(r.Ports) > 0 {
+						value = strconv.Itoa(r.Ports[0].Port)
+					}
+					return t, t.startPrompt(PromptPortForward, r, "port or local:remote", value)
+				}
+			}
+		case "w":
+			// Cycle the environment timeline between all, Warning and Normal events
+			if t.rightPanelView == RightPanelEvents && t.currentResource == ResourceEnvironments {
+				switch t.timelineFilter.Type {
+				case "":
+					t.timelineFilter.Type = "Warning"
+				case "Warning":
+					t.timelineFilter.Type = "Normal"
+				default:
+					t.timelineFilter.Type = ""
+				}
+				t.scrollOffset = 0
+				return t, t.loadEvents()
+			}
+		case "o":
+			// Cycle the involved object kind the environment timeline shows
+			if t.rightPanelView == RightPanelEvents && t.currentResource == ResourceEnvironments {
+				next := 0
+				for i, kind := range timelineKinds {
+					if kind == t.timelineFilter.Kind {
+						next = (i + 1) % len(timelineKinds)
+					}
+				}
+				t.timelineFilter.Kind = timelineKinds[next]
+				t.scrollOffset = 0
+				return t, t.loadEvents()
+			}
+		case "C":
+			// Clone the selected environment under a new name, optionally overriding variables
+			if t.panelFocus == FocusTable && t.currentResource == ResourceEnvironments {
+				if env, ok := t.getSelectedResource().(models.Environment); ok {
+					cmd := t.startPrompt(PromptClone, env, "name [var=value ...]", env.Name+"-clone")
+					t.promptInput.CharLimit = 256
+					t.promptInput.Width = 40
+					return t, cmd
+				}
+			}
+		case "A":
+			// Adopt the selected namespace into imperm management
+			if t.panelFocus == FocusTable && t.currentResource == ResourceEnvironments {
+				if env, ok := t.getSelectedResource().(models.Environment); ok {
+					return t, tea.Batch(t.adoptEnvironment(env.Name), t.setStatus("success", "Adopting %s...", env.Name))
+				}
+			}
+		case "D":
+			// Re-apply a drifted environment's Terraform configuration
+			if t.panelFocus == FocusTable && t.currentResource == ResourceEnvironments {
+				if env, ok := t.getSelectedResource().(models.Environment); ok {
+					if env.Drift == nil || !env.Drift.Drifted {
+						return t, t.setStatus("error", "%s has no drift to reconcile", env.Name)
+					}
+					return t, tea.Batch(t.reconcileEnvironment(env.Name), t.setStatus("success", "Reconciling %s...", env.Name))
+				}
+			}
+		case "G", "B":
+			// Resume or roll back an operation a server restart interrupted
+			if t.panelFocus == FocusTable && t.currentResource == ResourceEnvironments {
+				if env, ok := t.getSelectedResource().(models.Environment); ok {
+					if env.Interrupted == nil {
+						return t, t.setStatus("error", "%s has no interrupted operation", env.Name)
+					}
+					if msg.String() == "G" {
+						return t, tea.Batch(t.resumeEnvironment(env.Name), t.setStatus("success", "Resuming %s of %s...", env.Interrupted.Operation, env.Name))
+					}
+					return t, tea.Batch(t.rollbackEnvironment(env.Name), t.setStatus("success", "Rolling back %s of %s...", env.Interrupted.Operation, env.Name))
+				}
+			}
 		case "x":
+			// Stop the selected port forward when the Forwards panel is focused
+			if t.panelFocus == FocusRightPanel && t.rightPanelView == RightPanelForwards {
+				if t.forwardIndex < len(t.currentForwards) {
+					return t, t.stopPortForward(t.currentForwards[t.forwardIndex].Session.ID)
+				}
+				return t, nil
+			}
 			// Delete selected resource
 			if t.panelFocus == FocusTable {
 				// Get the resource info to show message immediately
@@ -250,8 +545,238 @@ func (t *Tab) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			t.rightPanelView = RightPanelStats
 			t.scrollOffset = 0
 			return t, t.loadDataForCurrentView()
+		case "5":
+			// Quick switch to Forwards view (without changing focus)
+			t.rightPanelView = RightPanelForwards
+			t.scrollOffset = 0
+			return t, t.loadDataForCurrentView()
+		case "6":
+			// Quick switch to YAML view (without changing focus)
+			t.rightPanelView = RightPanelYAML
+			t.scrollOffset = 0
+			return t, t.loadDataForCurrentView()
 		}
 	}
 
 	return t, nil
 }
+
+// switchResource shows the table for another resource type, fetching its list
+func (t *Tab) switchResource(resource resourceType) tea.Cmd {
+	t.currentResource = resource
+	t.selectedIndex = 0
+	t.panelFocus = FocusTable
+	t.revealedSecret = nil
+	t.isLoading = true
+	return t.loadResources
+}
+
+// isRevealed reports whether the values of secret have been revealed
+func (t *Tab) isRevealed(secret models.Secret) bool {
+	return t.revealedSecret != nil &&
+		t.revealedSecret.Namespace == secret.Namespace &&
+		t.revealedSecret.Name == secret.Name
+}
+
+// startPrompt opens an inline prompt for the given resource
+func (t *Tab) startPrompt(mode promptType, target interface{}, placeholder, value string) tea.Cmd {
+	ti := textinput.New()
+	ti.Placeholder = placeholder
+	ti.CharLimit = 64
+	ti.Width = 20
+	ti.SetValue(value)
+	ti.CursorEnd()
+
+	t.promptInput = ti
+	t.promptMode = mode
+	t.promptTarget = target
+	return t.promptInput.Focus()
+}
+
+// updatePrompt handles key input while a prompt is open
+func (t *Tab) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		t.promptMode = PromptNone
+		t.promptTarget = nil
+		return t, nil
+	case "enter":
+		mode := t.promptMode
+		target := t.promptTarget
+		value := strings.TrimSpace(t.promptInput.Value())
+		t.promptMode = PromptNone
+		t.promptTarget = nil
+		return t, t.submitPrompt(mode, target, value)
+	}
+
+	var cmd tea.Cmd
+	t.promptInput, cmd = t.promptInput.Update(msg)
+	return t, cmd
+}
+
+// submitPrompt runs the action for a confirmed prompt
+func (t *Tab) submitPrompt(mode promptType, target interface{}, value string) tea.Cmd {
+	switch mode {
+	case PromptScale:
+		dep, ok := target.(models.Deployment)
+		if !ok {
+			return nil
+		}
+		replicas, err := strconv.Atoi(value)
+		if err != nil || replicas < 0 {
+			return t.setStatus("error", "❌ Invalid replica count: %q", value)
+		}
+		return t.scaleDeployment(dep, replicas)
+
+	case PromptRollback:
+		dep, ok := target.(models.Deployment)
+		if !ok {
+			return nil
+		}
+		var revision int64
+		if value != "" {
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || parsed <= 0 {
+				return t.setStatus("error", "❌ Invalid revision: %q", value)
+			}
+			revision = parsed
+		}
+		return t.rollbackDeployment(dep, revision)
+
+	case PromptExec:
+		pod, ok := target.(models.Pod)
+		if !ok {
+			return nil
+		}
+		return t.execInPod(pod, value)
+
+	case PromptClone:
+		env, ok := target.(models.Environment)
+		if !ok {
+			return nil
+		}
+		req, err := parseCloneRequest(value)
+		if err != nil {
+			return t.setStatus("error", "❌ %v", err)
+		}
+		return tea.Batch(t.cloneEnvironment(env.Name, req), t.setStatus("success", "✓ Cloning %s as %s...", env.Name, req.Name))
+
+	case PromptPortForward:
+		remotePort, localPort, err := parsePortMapping(value)
+		if err != nil {
+			return t.setStatus("error", "❌ %v", err)
+		}
+		switch r := target.(type) {
+		case models.Pod:
+			return t.startPortForward(models.PortForwardKindPod, r.Namespace, r.Name, remotePort, localPort)
+		case models.Service:
+			return t.startPortForward(models.PortForwardKindService, r.Namespace, r.Name, remotePort, localPort)
+		}
+	}
+	return nil
+}
+
+// parseCloneRequest parses "name [var=value ...]". A variable with an empty
+// value ("var=") is dropped from the clone.
+func parseCloneRequest(value string) (models.CloneEnvironmentRequest, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return models.CloneEnvironmentRequest{}, fmt.Errorf("a name for the new environment is required")
+	}
+
+	req := models.CloneEnvironmentRequest{Name: fields[0]}
+	for _, field := range fields[1:] {
+		key, val, ok := strings.Cut(field, "=")
+		if !ok || key == "" {
+			return models.CloneEnvironmentRequest{}, fmt.Errorf("invalid override %q, expected var=value", field)
+		}
+		if req.Variables == nil {
+			req.Variables = make(map[string]string)
+		}
+		req.Variables[key] = val
+	}
+	return req, nil
+}
+
+// parsePortMapping parses "port" or "local:remote" like kubectl port-forward.
+// A bare port listens on the same local port; a local port of 0 picks a free one.
+func parsePortMapping(value string) (remotePort, localPort int, err error) {
+	local, remote, hasLocal := strings.Cut(value, ":")
+	if !hasLocal {
+		remote = local
+	}
+	remotePort, err = strconv.Atoi(remote)
+	if err != nil || remotePort < 1 || remotePort > 65535 {
+		return 0, 0, fmt.Errorf("invalid remote port: %q", remote)
+	}
+	if !hasLocal {
+		return remotePort, remotePort, nil
+	}
+	localPort, err = strconv.Atoi(local)
+	if err != nil || localPort < 0 || localPort > 65535 {
+		return 0, 0, fmt.Errorf("invalid local port: %q", local)
+	}
+	return remotePort, localPort, nil
+}
+
+// handleDeploymentAction reports the result of a deployment change and starts tracking its rollout
+func (t *Tab) handleDeploymentAction(msg deploymentActionMsg) tea.Cmd {
+	if msg.err != nil {
+		return t.setStatus("error", "❌ %s %s failed: %v", msg.action, msg.deployment, msg.err)
+	}
+
+	switch msg.action {
+	case actionPause:
+		return tea.Batch(t.loadResources, t.setStatus("success", "✓ Paused rollouts of %s", msg.deployment))
+	case actionResume:
+		return tea.Batch(t.loadResources, t.setStatus("success", "✓ Resumed rollouts of %s", msg.deployment))
+	}
+
+	t.rollout = &rolloutWatch{
+		namespace:  msg.namespace,
+		deployment: msg.deployment,
+		action:     msg.action,
+		target:     msg.target,
+		started:    time.Now(),
+	}
+
+	status := fmt.Sprintf("✓ %s %s", msg.action, msg.deployment)
+	if msg.detail != "" {
+		status += " " + msg.detail
+	}
+
+	return tea.Batch(
+		t.setStatus("success", "%s", status),
+		t.loadRolloutProgress(msg.namespace, msg.deployment),
+	)
+}
+
+// updateRollout records rollout progress and decides whether to keep polling
+func (t *Tab) updateRollout(msg rolloutProgressMsg) tea.Cmd {
+	r := t.rollout
+	if r == nil || r.done || r.namespace != msg.namespace || r.deployment != msg.deployment {
+		return nil
+	}
+
+	if !msg.found {
+		r.done = true
+		r.failed = true
+		return t.setStatus("error", "❌ Deployment %s no longer exists", r.deployment)
+	}
+
+	r.upToDate = msg.upToDate
+	r.available = msg.available
+
+	if msg.complete && msg.replicas == r.target {
+		r.done = true
+		return tea.Batch(t.loadResources, t.setStatus("success", "✓ %s rolled out (%d/%d available)", r.deployment, r.available, r.target))
+	}
+
+	if time.Since(r.started) > config.RolloutTimeout {
+		r.done = true
+		r.failed = true
+		return t.setStatus("error", "❌ Timed out waiting for %s to roll out", r.deployment)
+	}
+
+	return t.rolloutTick()
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"imperm-ui/internal/ui"
+	"imperm-ui/pkg/models"
 )
 
 func (t *Tab) View() string {
@@ -36,15 +37,7 @@ func (t *Tab) View() string {
 	selectedRowStyle := ui.TableRowSelectedStyle
 
 	// Get resource name for table title
-	var resourceName string
-	switch t.currentResource {
-	case ResourceEnvironments:
-		resourceName = "Environments"
-	case ResourcePods:
-		resourceName = "Pods"
-	case ResourceDeployments:
-		resourceName = "Deployments"
-	}
+	resourceName := t.resourceTitle()
 
 	// Build table
 	var content strings.Builder
@@ -54,6 +47,12 @@ func (t *Tab) View() string {
 	// Always reserve space for status message (so layout doesn't shift)
 	content.WriteString(ui.RenderStatusMessage(t.statusMessage, t.statusType))
 
+	// Rollout progress for a recently changed deployment
+	if t.rollout != nil {
+		content.WriteString(t.renderRolloutProgress())
+		content.WriteString("\n\n")
+	}
+
 	switch t.currentResource {
 	case ResourceEnvironments:
 		content.WriteString(t.renderEnvironmentsTable(tableHeaderStyle, rowStyle, selectedRowStyle))
@@ -61,6 +60,18 @@ func (t *Tab) View() string {
 		content.WriteString(t.renderPodsTable(tableHeaderStyle, rowStyle, selectedRowStyle))
 	case ResourceDeployments:
 		content.WriteString(t.renderDeploymentsTable(tableHeaderStyle, rowStyle, selectedRowStyle))
+	case ResourceServices:
+		content.WriteString(t.renderServicesTable(tableHeaderStyle, rowStyle, selectedRowStyle))
+	case ResourceConfigMaps:
+		content.WriteString(t.renderConfigMapsTable(tableHeaderStyle, rowStyle, selectedRowStyle))
+	case ResourceSecrets:
+		content.WriteString(t.renderSecretsTable(tableHeaderStyle, rowStyle, selectedRowStyle))
+	case ResourceIngresses:
+		content.WriteString(t.renderIngressesTable(tableHeaderStyle, rowStyle, selectedRowStyle))
+	case ResourceJobs:
+		content.WriteString(t.renderJobsTable(tableHeaderStyle, rowStyle, selectedRowStyle))
+	case ResourceStatefulSets:
+		content.WriteString(t.renderStatefulSetsTable(tableHeaderStyle, rowStyle, selectedRowStyle))
 	}
 
 	// Right panel
@@ -93,14 +104,41 @@ func (t *Tab) View() string {
 
 	mainContent := lipgloss.JoinHorizontal(lipgloss.Top, tablePanel, rightPanelStyled)
 
-	// Help text
-	var helpText string
-	if t.panelFocus == FocusTable {
-		helpText = "[→/l] Right Panel  [e/p/d] Views  [Enter] Drill-down  [↑↓/jk] Navigate  [x] Delete  [r] Refresh  [q] Quit"
+	// Help text (replaced by the prompt while one is open)
+	var help string
+	if t.promptMode != PromptNone {
+		help = t.renderPrompt()
 	} else {
-		helpText = "[←/h] Back  [→←/hl] Cycle Views  [↑↓/jk] Scroll  [1] Details  [2] Logs  [3] Events  [4] Stats  [q] Quit"
+		var helpText string
+		if t.panelFocus == FocusTable {
+			helpText = "[→/l] Right Panel  [e/p/d/s/c/t/i/b/f] Views  [Enter] Drill-down  [↑↓/jk] Navigate  [x] Delete  [r] Refresh  [q] Quit"
+			if t.currentResource == ResourceEnvironments {
+				helpText += "  [C] Clone  [A] Adopt  [D] Reconcile  [u] Unmanaged"
+				if env, ok := t.getSelectedResource().(models.Environment); ok && env.Interrupted != nil {
+					helpText += "  [G] Resume  [B] Roll back"
+				}
+			}
+			if t.currentResource == ResourceDeployments {
+				helpText += "  [S] Scale  [R] Restart  [P] Pause/Resume  [U] Rollback"
+			}
+			if t.currentResource == ResourcePods {
+				helpText += "  [E] Exec  [F] Port-forward"
+			}
+			if t.currentResource == ResourceServices {
+				helpText += "  [F] Port-forward"
+			}
+			if t.currentResource == ResourceSecrets {
+				helpText += "  [V] Reveal/Hide"
+			}
+		} else if t.rightPanelView == RightPanelEvents && t.currentResource == ResourceEnvironments {
+			helpText = "[←/h] Back  [↑↓/jk] Scroll  [w] Warning/Normal  [o] Object Kind  [1-6] Views  [q] Quit"
+		} else if t.rightPanelView == RightPanelForwards {
+			helpText = "[←/h] Back  [↑↓/jk] Select  [x] Stop Forward  [1-6] Views  [q] Quit"
+		} else {
+			helpText = "[←/h] Back  [→←/hl] Cycle Views  [↑↓/jk] Scroll  [1] Details  [2] Logs  [3] Events  [4] Stats  [5] Forwards  [6] YAML  [q] Quit"
+		}
+		help = ui.HelpStyle.Render(helpText)
 	}
-	help := ui.HelpStyle.Render(helpText)
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -108,3 +146,68 @@ func (t *Tab) View() string {
 		help,
 	)
 }
+
+// renderRolloutProgress renders a one-line summary of the tracked rollout
+func (t *Tab) renderRolloutProgress() string {
+	r := t.rollout
+
+	color := ui.ColorRunning
+	icon := "⟳"
+	if r.failed {
+		color = ui.ColorError
+		icon = "✗"
+	} else if r.done {
+		color = ui.ColorSuccess
+		icon = "✓"
+	}
+
+	style := lipgloss.NewStyle().Foreground(color).Bold(true)
+	current, total := r.available, r.target
+	if total == 0 {
+		// Scaling to zero: the bar fills once nothing is left running
+		current, total = 0, 1
+		if r.done && !r.failed {
+			current = 1
+		}
+	}
+	bar := ui.RenderProgressBar(current, total, 20)
+
+	return style.Render(fmt.Sprintf("%s %s %s", icon, r.action, r.deployment)) + "\n" +
+		fmt.Sprintf("%s %d/%d available, %d up-to-date", bar, r.available, r.target, r.upToDate)
+}
+
+// renderPrompt renders the open prompt with its input field
+func (t *Tab) renderPrompt() string {
+	var label string
+	switch t.promptMode {
+	case PromptScale:
+		if dep, ok := t.promptTarget.(models.Deployment); ok {
+			label = fmt.Sprintf("Scale %s to replicas:", dep.Name)
+		}
+	case PromptRollback:
+		if dep, ok := t.promptTarget.(models.Deployment); ok {
+			label = fmt.Sprintf("Roll back %s to revision:", dep.Name)
+		}
+	case PromptExec:
+		if pod, ok := t.promptTarget.(models.Pod); ok {
+			label = fmt.Sprintf("Exec into %s, container:", pod.Name)
+		}
+	case PromptPortForward:
+		switch r := t.promptTarget.(type) {
+		case models.Pod:
+			label = fmt.Sprintf("Forward to %s, port:", r.Name)
+		case models.Service:
+			label = fmt.Sprintf("Forward to service %s, port:", r.Name)
+		}
+	case PromptClone:
+		if env, ok := t.promptTarget.(models.Environment); ok {
+			label = fmt.Sprintf("Clone %s as:", env.Name)
+		}
+	}
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(ui.ColorHighlight).
+		Bold(true)
+
+	return ui.HelpStyle.Render(labelStyle.Render(label) + " " + t.promptInput.View() + "  [Enter] Confirm  [Esc] Cancel")
+}
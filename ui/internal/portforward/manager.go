@@ -0,0 +1,179 @@
+package portforward
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"imperm-ui/pkg/client"
+	"imperm-ui/pkg/models"
+)
+
+// Status is a snapshot of one forward for display
+type Status struct {
+	Session     models.PortForward // Server-side session
+	LocalAddr   string             // Address the local listener accepts connections on
+	ActiveConns int
+	TotalConns  int
+	LastError   string
+}
+
+// forward is a local listener tunnelling each accepted connection through a
+// server-side port-forward session
+type forward struct {
+	status   Status
+	listener net.Listener
+}
+
+// Manager owns the local listeners opened from the UI
+type Manager struct {
+	client   client.Client
+	mu       sync.Mutex
+	forwards []*forward
+}
+
+// NewManager creates a manager that opens sessions through the given client
+func NewManager(c client.Client) *Manager {
+	return &Manager{client: c}
+}
+
+// Start opens a session on the server and a local listener on localPort
+// (0 picks a free port). Connections to the listener reach the target.
+func (m *Manager) Start(req models.PortForwardRequest, localPort int) (Status, error) {
+	session, err := m.client.StartPortForward(req)
+	if err != nil {
+		return Status{}, err
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", localPort))
+	if err != nil {
+		m.client.StopPortForward(session.ID)
+		return Status{}, fmt.Errorf("failed to listen on local port %d: %w", localPort, err)
+	}
+
+	f := &forward{
+		status: Status{
+			Session:   *session,
+			LocalAddr: listener.Addr().String(),
+		},
+		listener: listener,
+	}
+
+	m.mu.Lock()
+	m.forwards = append(m.forwards, f)
+	m.mu.Unlock()
+
+	go m.accept(f)
+	return f.status, nil
+}
+
+// accept serves the local listener until it is closed
+func (m *Manager) accept(f *forward) {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go m.tunnel(f, conn)
+	}
+}
+
+// tunnel copies one local connection to and from the server-side session
+func (m *Manager) tunnel(f *forward, local net.Conn) {
+	defer local.Close()
+
+	remote, err := m.client.DialPortForward(f.status.Session.ID)
+	if err != nil {
+		m.mu.Lock()
+		f.status.LastError = err.Error()
+		m.mu.Unlock()
+		return
+	}
+	defer remote.Close()
+
+	m.mu.Lock()
+	f.status.ActiveConns++
+	f.status.TotalConns++
+	m.mu.Unlock()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(remote, local)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(local, remote)
+		done <- struct{}{}
+	}()
+	// Either side finishing ends the connection; the deferred closes unblock the other copy
+	<-done
+
+	m.mu.Lock()
+	f.status.ActiveConns--
+	m.mu.Unlock()
+}
+
+// Stop closes the local listener and the server-side session
+func (m *Manager) Stop(id string) error {
+	m.mu.Lock()
+	var target *forward
+	for i, f := range m.forwards {
+		if f.status.Session.ID == id {
+			target = f
+			m.forwards = append(m.forwards[:i], m.forwards[i+1:]...)
+			break
+		}
+	}
+	m.mu.Unlock()
+
+	if target == nil {
+		return fmt.Errorf("port forward %s not found", id)
+	}
+	target.listener.Close()
+	return m.client.StopPortForward(id)
+}
+
+// StopAll closes every forward, e.g. when the UI exits
+func (m *Manager) StopAll() {
+	for _, status := range m.List() {
+		m.Stop(status.Session.ID)
+	}
+}
+
+// Refresh updates session states from the server. Sessions the server no
+// longer knows about are marked closed.
+func (m *Manager) Refresh() error {
+	sessions, err := m.client.ListPortForwards()
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]models.PortForward, len(sessions))
+	for _, session := range sessions {
+		byID[session.ID] = session
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, f := range m.forwards {
+		if session, ok := byID[f.status.Session.ID]; ok {
+			f.status.Session = session
+		} else {
+			f.status.Session.Status = models.PortForwardClosed
+		}
+	}
+	return nil
+}
+
+// List returns a snapshot of the forwards in the order they were opened
+func (m *Manager) List() []Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statuses := make([]Status, 0, len(m.forwards))
+	for _, f := range m.forwards {
+		statuses = append(statuses, f.status)
+	}
+	return statuses
+}
@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"strings"
+
 	"imperm-ui/internal/config"
 
 	"github.com/charmbracelet/lipgloss"
@@ -92,3 +94,57 @@ func LogScrollRange(logs []string, availableLines int, scrolling bool, scrollOff
 	endIdx = len(logs)
 	return startIdx, endIdx, scrollOffset
 }
+
+// RenderProgressBar renders a fixed-width text progress bar for current out of total
+func RenderProgressBar(current, total, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	filled := 0
+	if total > 0 {
+		filled = current * width / total
+	}
+	if filled > width {
+		filled = width
+	}
+	if filled < 0 {
+		filled = 0
+	}
+
+	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", width-filled) + "]"
+}
+
+// sparkBlocks are the bar heights a sparkline is drawn with, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// RenderSparkline draws the last width values as a one-line bar chart scaled
+// between their lowest and highest value, so small changes stay visible
+func RenderSparkline(values []float64, width int) string {
+	if width <= 0 || len(values) == 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if hi > lo {
+			level = int((v - lo) / (hi - lo) * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
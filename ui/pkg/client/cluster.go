@@ -0,0 +1,68 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+
+	"imperm-ui/pkg/models"
+)
+
+// clusterSelection is the cluster requests are routed to, shared between the
+// HTTP client and its transport
+type clusterSelection struct {
+	mu   sync.RWMutex
+	name string // Empty for the server's default cluster
+}
+
+func (s *clusterSelection) get() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.name
+}
+
+func (s *clusterSelection) set(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.name = name
+}
+
+// addTo sets the cluster parameter on a query, unless the default cluster is selected
+func (s *clusterSelection) addTo(query url.Values) {
+	if name := s.get(); name != "" {
+		query.Set("cluster", name)
+	}
+}
+
+// clusterTransport adds the selected cluster to every request it sends
+type clusterTransport struct {
+	selection *clusterSelection
+	base      http.RoundTripper
+}
+
+func (t *clusterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.selection.get() == "" {
+		return t.base.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the request they are given
+	routed := req.Clone(req.Context())
+	query := routed.URL.Query()
+	t.selection.addTo(query)
+	routed.URL.RawQuery = query.Encode()
+	return t.base.RoundTrip(routed)
+}
+
+// ListClusters fetches the clusters the middleware manages
+func (c *HTTPClient) ListClusters() ([]models.Cluster, error) {
+	var clusters []models.Cluster
+	if err := c.doJSON(http.MethodGet, "/api/clusters", nil, &clusters); err != nil {
+		return nil, err
+	}
+	return clusters, nil
+}
+
+// SetCluster routes all later requests to the named cluster
+func (c *HTTPClient) SetCluster(name string) {
+	c.cluster.set(name)
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"imperm-ui/pkg/models"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// ExecPod attaches to a command in a pod container through the middleware's
+// WebSocket exec endpoint. It returns when the command exits, the connection
+// drops or ctx is cancelled.
+func (c *HTTPClient) ExecPod(ctx context.Context, req models.ExecRequest, streams ExecStreams) error {
+	execURL, err := c.execURL(req)
+	if err != nil {
+		return err
+	}
+
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, execURL, nil)
+	if err != nil {
+		if resp != nil {
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			return fmt.Errorf("exec failed: %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		}
+		return fmt.Errorf("failed to connect for exec: %w", err)
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	var writeMu sync.Mutex
+	send := func(channel byte, payload []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return conn.WriteMessage(websocket.BinaryMessage, append([]byte{channel}, payload...))
+	}
+
+	// Closing the connection unblocks the read loop below when ctx ends
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	if streams.Stdin != nil {
+		go func() {
+			buf := make([]byte, 4096)
+			for {
+				n, err := streams.Stdin.Read(buf)
+				if n > 0 {
+					if send(models.ExecChannelStdin, buf[:n]) != nil {
+						return
+					}
+				}
+				if err != nil {
+					return
+				}
+			}
+		}()
+	}
+
+	if streams.Resize != nil {
+		go func() {
+			for {
+				select {
+				case size, ok := <-streams.Resize:
+					if !ok {
+						return
+					}
+					payload, _ := json.Marshal(size)
+					if send(models.ExecChannelResize, payload) != nil {
+						return
+					}
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				return nil
+			}
+			return fmt.Errorf("exec connection lost: %w", err)
+		}
+		if len(data) == 0 {
+			continue
+		}
+
+		switch data[0] {
+		case models.ExecChannelStdout:
+			if streams.Stdout != nil {
+				streams.Stdout.Write(data[1:])
+			}
+		case models.ExecChannelStderr:
+			if streams.Stderr != nil {
+				streams.Stderr.Write(data[1:])
+			}
+		case models.ExecChannelStatus:
+			if len(data) > 1 {
+				return errors.New(string(data[1:]))
+			}
+			return nil
+		}
+	}
+}
+
+// execURL builds the WebSocket URL for an exec request
+func (c *HTTPClient) execURL(req models.ExecRequest) (string, error) {
+	query := url.Values{}
+	query.Set("namespace", req.Namespace)
+	query.Set("pod", req.Pod)
+	if req.Container != "" {
+		query.Set("container", req.Container)
+	}
+	for _, arg := range req.Command {
+		query.Add("command", arg)
+	}
+	if !req.TTY {
+		query.Set("tty", "false")
+	}
+	return c.websocketURL("/api/pods/exec", query)
+}
+
+// websocketURL turns a middleware API path into a ws:// or wss:// URL
+func (c *HTTPClient) websocketURL(path string, query url.Values) (string, error) {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid middleware URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + path
+	c.cluster.addTo(query)
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
@@ -5,26 +5,37 @@ import (
 	"encoding/json"
 	"fmt"
 	"imperm-ui/pkg/models"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
 // HTTPClient implements the Client interface for a real middleware API
 type HTTPClient struct {
 	baseURL    string
 	httpClient *http.Client
+	cluster    *clusterSelection
 }
 
 // NewHTTPClient creates a new HTTP client for the middleware API
 func NewHTTPClient(baseURL string) *HTTPClient {
+	cluster := &clusterSelection{}
 	return &HTTPClient{
 		baseURL:    baseURL,
-		httpClient: &http.Client{},
+		httpClient: &http.Client{Transport: &clusterTransport{selection: cluster, base: http.DefaultTransport}},
+		cluster:    cluster,
 	}
 }
 
 // ListEnvironments fetches all environments from the middleware API
-func (c *HTTPClient) ListEnvironments() ([]models.Environment, error) {
-	resp, err := c.httpClient.Get(c.baseURL + "/api/environments")
+func (c *HTTPClient) ListEnvironments(filter models.EnvironmentFilter) ([]models.Environment, error) {
+	path := "/api/environments"
+	if filter.Unmanaged {
+		path += "?unmanaged=true"
+	}
+
+	resp, err := c.httpClient.Get(c.baseURL + path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch environments: %w", err)
 	}
@@ -96,6 +107,82 @@ func (c *HTTPClient) DestroyEnvironment(name string) error {
 	return nil
 }
 
+// CloneEnvironment creates a new environment from the stored options of an existing one
+func (c *HTTPClient) CloneEnvironment(sourceName string, req models.CloneEnvironmentRequest) (*models.DeploymentOptions, error) {
+	var options models.DeploymentOptions
+	if err := c.doJSON(http.MethodPost, "/api/environments/"+url.PathEscape(sourceName)+"/clone", req, &options); err != nil {
+		return nil, err
+	}
+	return &options, nil
+}
+
+// EstimateEnvironment asks whether an environment with the given options would fit in the cluster, and what it would cost
+func (c *HTTPClient) EstimateEnvironment(options *models.DeploymentOptions) (*models.CapacityEstimate, error) {
+	req := map[string]interface{}{"options": options}
+	var estimate models.CapacityEstimate
+	if err := c.doJSON(http.MethodPost, "/api/environments/estimate", req, &estimate); err != nil {
+		return nil, err
+	}
+	return &estimate, nil
+}
+
+// GetEnvironmentEvents fetches an environment's Kubernetes and imperm events, newest first
+func (c *HTTPClient) GetEnvironmentEvents(envName string, filter models.EventFilter) ([]models.TimelineEvent, error) {
+	query := url.Values{}
+	if filter.Type != "" {
+		query.Set("type", filter.Type)
+	}
+	if filter.Kind != "" {
+		query.Set("kind", filter.Kind)
+	}
+
+	path := "/api/environments/" + url.PathEscape(envName) + "/events"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var events []models.TimelineEvent
+	if err := c.doJSON(http.MethodGet, path, nil, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// AdoptEnvironment brings an existing namespace under imperm management,
+// returning the options detected from its workloads
+func (c *HTTPClient) AdoptEnvironment(name string) (*models.DeploymentOptions, error) {
+	var options models.DeploymentOptions
+	if err := c.doJSON(http.MethodPost, "/api/environments/"+url.PathEscape(name)+"/adopt", nil, &options); err != nil {
+		return nil, err
+	}
+	return &options, nil
+}
+
+// ReconcileEnvironment re-applies an environment's Terraform configuration,
+// undoing changes made outside it
+func (c *HTTPClient) ReconcileEnvironment(name string) error {
+	return c.doJSON(http.MethodPost, "/api/environments/"+url.PathEscape(name)+"/reconcile", nil, nil)
+}
+
+// ResumeEnvironment finishes an environment's operation that a server restart interrupted
+func (c *HTTPClient) ResumeEnvironment(name string) error {
+	return c.doJSON(http.MethodPost, "/api/environments/"+url.PathEscape(name)+"/resume", nil, nil)
+}
+
+// RollbackEnvironment undoes an environment's operation that a server restart interrupted
+func (c *HTTPClient) RollbackEnvironment(name string) error {
+	return c.doJSON(http.MethodPost, "/api/environments/"+url.PathEscape(name)+"/rollback", nil, nil)
+}
+
+// ApplyManifest applies multi-document YAML into an environment with server-side apply
+func (c *HTTPClient) ApplyManifest(envName string, req models.ApplyManifestRequest) (*models.ApplyResult, error) {
+	var result models.ApplyResult
+	if err := c.doJSON(http.MethodPost, "/api/environments/"+url.PathEscape(envName)+"/apply", req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // ListPods fetches all pods from the middleware API
 func (c *HTTPClient) ListPods(namespace string) ([]models.Pod, error) {
 	url := fmt.Sprintf("%s/api/k8s/%s/pods", c.baseURL, namespace)
@@ -150,72 +237,22 @@ func (c *HTTPClient) GetPodLogs(namespace, podName string) (string, error) {
 }
 
 // GetPodEvents fetches events for a specific pod
-func (c *HTTPClient) GetPodEvents(namespace, podName string) ([]models.Event, error) {
-	url := fmt.Sprintf("%s/api/k8s/%s/events", c.baseURL, namespace)
-
-	resp, err := c.httpClient.Get(url)
-	if err != nil {
+func (c *HTTPClient) GetPodEvents(namespace, podName string) ([]models.EventTimeEntry, error) {
+	query := url.Values{"namespace": {namespace}, "pod": {podName}}
+	var events []models.EventTimeEntry
+	if err := c.doJSON(http.MethodGet, "/api/pods/events?"+query.Encode(), nil, &events); err != nil {
 		return nil, fmt.Errorf("failed to fetch pod events: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
-	// Upstream returns []EventTimeEntry, convert to []Event
-	var eventEntries []models.EventTimeEntry
-	if err := json.NewDecoder(resp.Body).Decode(&eventEntries); err != nil {
-		return nil, fmt.Errorf("failed to decode events: %w", err)
-	}
-
-	// Convert EventTimeEntry to Event
-	events := make([]models.Event, 0, len(eventEntries))
-	for _, entry := range eventEntries {
-		events = append(events, models.Event{
-			Type:      entry.Type,
-			Reason:    entry.Reason,
-			Message:   entry.Message,
-			Timestamp: entry.LastObservedTime,
-			Count:     entry.Count,
-		})
-	}
-
 	return events, nil
 }
 
 // GetDeploymentEvents fetches events for a specific deployment
-func (c *HTTPClient) GetDeploymentEvents(namespace, deploymentName string) ([]models.Event, error) {
-	url := fmt.Sprintf("%s/api/k8s/%s/events", c.baseURL, namespace)
-
-	resp, err := c.httpClient.Get(url)
-	if err != nil {
+func (c *HTTPClient) GetDeploymentEvents(namespace, deploymentName string) ([]models.EventTimeEntry, error) {
+	query := url.Values{"namespace": {namespace}, "deployment": {deploymentName}}
+	var events []models.EventTimeEntry
+	if err := c.doJSON(http.MethodGet, "/api/deployments/events?"+query.Encode(), nil, &events); err != nil {
 		return nil, fmt.Errorf("failed to fetch deployment events: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
-	// Upstream returns []EventTimeEntry, convert to []Event
-	var eventEntries []models.EventTimeEntry
-	if err := json.NewDecoder(resp.Body).Decode(&eventEntries); err != nil {
-		return nil, fmt.Errorf("failed to decode events: %w", err)
-	}
-
-	// Convert EventTimeEntry to Event
-	events := make([]models.Event, 0, len(eventEntries))
-	for _, entry := range eventEntries {
-		events = append(events, models.Event{
-			Type:      entry.Type,
-			Reason:    entry.Reason,
-			Message:   entry.Message,
-			Timestamp: entry.LastObservedTime,
-			Count:     entry.Count,
-		})
-	}
-
 	return events, nil
 }
 
@@ -300,6 +337,19 @@ func (c *HTTPClient) GetPodMetrics(namespace string) ([]models.PodMetrics, error
 	return metrics, nil
 }
 
+// GetMetricsHistory fetches the sampled usage of a pod, or of a whole namespace when pod is empty
+func (c *HTTPClient) GetMetricsHistory(namespace, pod string) (*models.MetricsHistory, error) {
+	query := url.Values{"namespace": {namespace}}
+	if pod != "" {
+		query.Set("pod", pod)
+	}
+	var history models.MetricsHistory
+	if err := c.doJSON(http.MethodGet, "/api/metrics/history?"+query.Encode(), nil, &history); err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
 // GetEnvironmentHistory fetches the history of environment operations
 func (c *HTTPClient) GetEnvironmentHistory() ([]models.EnvironmentHistory, error) {
 	resp, err := c.httpClient.Get(c.baseURL + "/api/environments/history")
@@ -365,6 +415,120 @@ func (c *HTTPClient) DeleteDeployment(namespace, deploymentName string) error {
 	return nil
 }
 
+// ScaleDeployment changes the replica count of a deployment via the middleware API
+func (c *HTTPClient) ScaleDeployment(namespace, deploymentName string, replicas int) error {
+	return c.sendJSON(http.MethodPatch, "/api/deployments/scale", map[string]interface{}{
+		"namespace":  namespace,
+		"deployment": deploymentName,
+		"replicas":   replicas,
+	})
+}
+
+// RestartDeployment triggers a rolling restart of a deployment
+func (c *HTTPClient) RestartDeployment(namespace, deploymentName string) error {
+	return c.sendJSON(http.MethodPost, "/api/deployments/restart", map[string]interface{}{
+		"namespace":  namespace,
+		"deployment": deploymentName,
+	})
+}
+
+// PauseDeployment pauses rollouts of a deployment
+func (c *HTTPClient) PauseDeployment(namespace, deploymentName string) error {
+	return c.sendJSON(http.MethodPost, "/api/deployments/pause", map[string]interface{}{
+		"namespace":  namespace,
+		"deployment": deploymentName,
+	})
+}
+
+// ResumeDeployment resumes rollouts of a paused deployment
+func (c *HTTPClient) ResumeDeployment(namespace, deploymentName string) error {
+	return c.sendJSON(http.MethodPost, "/api/deployments/resume", map[string]interface{}{
+		"namespace":  namespace,
+		"deployment": deploymentName,
+	})
+}
+
+// GetDeploymentHistory fetches the rollout history of a deployment
+func (c *HTTPClient) GetDeploymentHistory(namespace, deploymentName string) ([]models.DeploymentRevision, error) {
+	url := fmt.Sprintf("%s/api/deployments/history?namespace=%s&deployment=%s", c.baseURL, namespace, deploymentName)
+
+	resp, err := c.httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch deployment history: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var history []models.DeploymentRevision
+	if err := json.NewDecoder(resp.Body).Decode(&history); err != nil {
+		return nil, fmt.Errorf("failed to decode deployment history: %w", err)
+	}
+
+	// Handle null response
+	if history == nil {
+		history = []models.DeploymentRevision{}
+	}
+
+	return history, nil
+}
+
+// RollbackDeployment rolls a deployment back to a revision (0 for the previous one)
+func (c *HTTPClient) RollbackDeployment(namespace, deploymentName string, revision int64) error {
+	return c.sendJSON(http.MethodPost, "/api/deployments/rollback", map[string]interface{}{
+		"namespace":  namespace,
+		"deployment": deploymentName,
+		"revision":   revision,
+	})
+}
+
+// sendJSON sends a JSON payload to the middleware API and expects a 200 response.
+// The server's error text is included in the returned error so failures are actionable.
+func (c *HTTPClient) sendJSON(method, path string, payload interface{}) error {
+	return c.doJSON(method, path, payload, nil)
+}
+
+// doJSON is sendJSON that also decodes the response body into out when it is non-nil
+func (c *HTTPClient) doJSON(method, path string, payload, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal payload: %w", err)
+		}
+		body = bytes.NewBuffer(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode response from %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
 // GetOperationLogs fetches operation logs for an environment
 func (c *HTTPClient) GetOperationLogs(environmentName string) (*models.OperationLogs, error) {
 	url := fmt.Sprintf("%s/api/operations/logs?environment=%s", c.baseURL, environmentName)
@@ -1,8 +1,15 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"imperm-ui/pkg/models"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -10,6 +17,19 @@ import (
 type MockClient struct {
 	environments []models.Environment
 	history      []models.EnvironmentHistory
+	rollouts     map[string][]models.DeploymentRevision // Rollout history keyed by namespace/deployment
+	resources    mockResources
+	applied      map[string]string                      // Applied manifest documents keyed by namespace/kind/name
+	options      map[string]*models.DeploymentOptions   // Options each environment was created with
+	opEvents     map[string][]models.TimelineEvent      // Operation events per environment
+	drift        map[string]models.DriftStatus          // Simulated drift checks per environment
+	interrupted  map[string]models.InterruptedOperation // Simulated operations cut short by a restart
+	cluster      string                                 // Selected cluster, empty for the default
+
+	// Port-forward sessions are reached from connection goroutines, so they get their own lock
+	forwardsMu sync.Mutex
+	forwards   map[string]*mockForward
+	forwardSeq int
 }
 
 // NewMockClient creates a new mock client with sample data
@@ -24,34 +44,70 @@ func NewMockClient() *MockClient {
 				Age:       now.Add(-2 * time.Hour),
 				Pods: []models.Pod{
 					{
-						Name:      "app-deployment-abc123",
-						Namespace: "default",
-						Status:    "Running",
-						Ready:     "1/1",
-						Restarts:  0,
-						Age:       now.Add(-2 * time.Hour),
-						CPU:       "150m",
-						Memory:    "256Mi",
+						Name:          "app-deployment-abc123",
+						Namespace:     "default",
+						Status:        "Running",
+						Phase:         "Running",
+						Ready:         "1/1",
+						Restarts:      0,
+						Age:           now.Add(-2 * time.Hour),
+						CPU:           "150m",
+						Memory:        "256Mi",
+						CPURequest:    "100m",
+						CPULimit:      "500m",
+						MemoryRequest: "256Mi",
+						MemoryLimit:   "512Mi",
+						Node:          "mock-node-1",
+						PodIP:         "10.244.0.12",
+						QOSClass:      "Burstable",
+						Owner:         "ReplicaSet/app-deployment-5002",
+						Containers: []models.PodContainer{
+							{Name: "app", Image: "nginx:1.25", Ready: true, State: "Running"},
+						},
 					},
 					{
-						Name:      "app-deployment-def456",
-						Namespace: "default",
-						Status:    "Running",
-						Ready:     "1/1",
-						Restarts:  2,
-						Age:       now.Add(-1 * time.Hour),
-						CPU:       "75m",
-						Memory:    "128Mi",
+						Name:          "app-deployment-def456",
+						Namespace:     "default",
+						Status:        "Running",
+						Phase:         "Running",
+						Ready:         "1/1",
+						Restarts:      2,
+						Age:           now.Add(-1 * time.Hour),
+						CPU:           "75m",
+						Memory:        "128Mi",
+						CPURequest:    "50m",
+						CPULimit:      "500m",
+						MemoryRequest: "128Mi",
+						MemoryLimit:   "512Mi",
+						Node:          "mock-node-2",
+						PodIP:         "10.244.1.7",
+						QOSClass:      "Burstable",
+						Owner:         "ReplicaSet/app-deployment-5002",
+						Containers: []models.PodContainer{
+							{
+								Name:         "app",
+								Image:        "nginx:1.25",
+								Ready:        true,
+								State:        "Running",
+								RestartCount: 2,
+								LastReason:   "OOMKilled",
+								LastExitCode: intPtr(137),
+								LastFinished: now.Add(-40 * time.Minute),
+							},
+						},
 					},
 				},
 				Deployments: []models.Deployment{
 					{
-						Name:      "app-deployment",
-						Namespace: "default",
-						Ready:     "2/2",
-						UpToDate:  2,
-						Available: 2,
-						Age:       now.Add(-2 * time.Hour),
+						Name:            "app-deployment",
+						Namespace:       "default",
+						Ready:           "2/2",
+						Replicas:        2,
+						UpToDate:        2,
+						Available:       2,
+						Age:             now.Add(-2 * time.Hour),
+						Revision:        2,
+						RolloutComplete: true,
 					},
 				},
 			},
@@ -62,24 +118,71 @@ func NewMockClient() *MockClient {
 				Age:       now.Add(-24 * time.Hour),
 				Pods: []models.Pod{
 					{
-						Name:      "nginx-pod-xyz789",
-						Namespace: "staging",
-						Status:    "Running",
-						Ready:     "1/1",
-						Restarts:  0,
-						Age:       now.Add(-24 * time.Hour),
-						CPU:       "50m",
-						Memory:    "64Mi",
+						Name:          "nginx-pod-xyz789",
+						Namespace:     "staging",
+						Status:        "Running",
+						Phase:         "Running",
+						Ready:         "1/1",
+						Restarts:      0,
+						Age:           now.Add(-24 * time.Hour),
+						CPU:           "50m",
+						Memory:        "64Mi",
+						CPURequest:    "50m",
+						CPULimit:      "500m",
+						MemoryRequest: "64Mi",
+						MemoryLimit:   "512Mi",
+						Node:          "mock-node-1",
+						PodIP:         "10.244.0.31",
+						QOSClass:      "BestEffort",
+						Owner:         "ReplicaSet/nginx-deployment-7f4c",
+						Containers: []models.PodContainer{
+							{Name: "nginx", Image: "nginx:1.25", Ready: true, State: "Running"},
+						},
+					},
+					{
+						Name:          "worker-6c9d-qx2lm",
+						Namespace:     "staging",
+						Status:        "CrashLoopBackOff",
+						Phase:         "Running",
+						Ready:         "0/1",
+						Restarts:      7,
+						Age:           now.Add(-3 * time.Hour),
+						CPU:           "5m",
+						Memory:        "12Mi",
+						CPURequest:    "10m",
+						CPULimit:      "500m",
+						MemoryRequest: "32Mi",
+						MemoryLimit:   "512Mi",
+						Node:          "mock-node-2",
+						PodIP:         "10.244.1.19",
+						QOSClass:      "Guaranteed",
+						Owner:         "ReplicaSet/worker-6c9d",
+						Containers: []models.PodContainer{
+							{
+								Name:         "worker",
+								Image:        "busybox:1.36",
+								State:        "Waiting",
+								Reason:       "CrashLoopBackOff",
+								Message:      "back-off 5m0s restarting failed container=worker",
+								RestartCount: 7,
+								LastReason:   "Error",
+								LastExitCode: intPtr(1),
+								LastFinished: now.Add(-2 * time.Minute),
+							},
+						},
 					},
 				},
 				Deployments: []models.Deployment{
 					{
-						Name:      "nginx-deployment",
-						Namespace: "staging",
-						Ready:     "1/1",
-						UpToDate:  1,
-						Available: 1,
-						Age:       now.Add(-24 * time.Hour),
+						Name:            "nginx-deployment",
+						Namespace:       "staging",
+						Ready:           "1/1",
+						Replicas:        1,
+						UpToDate:        1,
+						Available:       1,
+						Age:             now.Add(-24 * time.Hour),
+						Revision:        1,
+						RolloutComplete: true,
 					},
 				},
 			},
@@ -98,13 +201,71 @@ func NewMockClient() *MockClient {
 				WithOptions: true,
 			},
 		},
+		options: map[string]*models.DeploymentOptions{
+			"staging-env-1": {
+				Name: "staging-env-1",
+				Variables: map[string]string{
+					"image_tag":     `"1.4.2"`,
+					"replica_count": "2",
+
+					models.VarQuotaCPURequests:    "1",
+					models.VarQuotaMemoryRequests: "1Gi",
+					models.VarQuotaCPULimits:      "2",
+					models.VarQuotaMemoryLimits:   "2Gi",
+					models.VarQuotaPods:           "10",
+				},
+			},
+		},
+		resources:   newMockResources(now),
+		drift:       mockDrift(now),
+		interrupted: mockInterrupted(now),
 	}
 }
 
-func (m *MockClient) ListEnvironments() ([]models.Environment, error) {
+func (m *MockClient) ListEnvironments(filter models.EnvironmentFilter) ([]models.Environment, error) {
+	for i := range m.environments {
+		m.environments[i].Managed = true
+		m.environments[i].Drift = m.driftOf(m.environments[i].Name)
+		m.environments[i].Interrupted = m.interruptedOf(m.environments[i].Name)
+		m.environments[i].Health = mockHealth(m.environments[i])
+		m.environments[i].Quota = mockQuotaUsage(m.environments[i], m.options[m.environments[i].Name])
+		m.environments[i].Cluster = m.currentCluster()
+	}
+	if filter.Unmanaged {
+		envs := append([]models.Environment{}, m.environments...)
+		for _, env := range m.unmanagedEnvironments() {
+			env.Cluster = m.currentCluster()
+			envs = append(envs, env)
+		}
+		return envs, nil
+	}
 	return m.environments, nil
 }
 
+// mockHealth gives a rough health roll-up of the simulated workloads
+func mockHealth(env models.Environment) models.EnvironmentHealth {
+	if env.Status == "Creating" {
+		return models.EnvironmentHealth{Status: models.HealthProvisioning, Reasons: []string{"waiting for workloads"}}
+	}
+
+	health := models.EnvironmentHealth{Status: models.HealthHealthy}
+	for _, pod := range env.Pods {
+		if pod.Status != "Running" && pod.Status != "Succeeded" {
+			health.Status = models.HealthFailing
+			health.Reasons = append(health.Reasons, fmt.Sprintf("pod %s: %s", pod.Name, pod.Status))
+		}
+	}
+	for _, dep := range env.Deployments {
+		if dep.Available < dep.Replicas {
+			if health.Status == models.HealthHealthy {
+				health.Status = models.HealthDegraded
+			}
+			health.Reasons = append(health.Reasons, fmt.Sprintf("deployment %s has %d/%d available replicas", dep.Name, dep.Available, dep.Replicas))
+		}
+	}
+	return health
+}
+
 func (m *MockClient) CreateEnvironment(name string, options *models.DeploymentOptions) error {
 	// Simulate environment creation
 	now := time.Now()
@@ -126,15 +287,48 @@ func (m *MockClient) CreateEnvironment(name string, options *models.DeploymentOp
 		WithOptions: hasOptions,
 	}
 	m.history = append(m.history, historyEntry)
+	m.recordEvent(name, "Normal", "CreateStarted", "create started")
+
+	if options != nil {
+		if m.options == nil {
+			m.options = make(map[string]*models.DeploymentOptions)
+		}
+		m.options[name] = options.Clone(name, nil)
+	}
 
 	return nil
 }
 
+func (m *MockClient) CloneEnvironment(sourceName string, req models.CloneEnvironmentRequest) (*models.DeploymentOptions, error) {
+	found := false
+	for _, env := range m.environments {
+		if env.Name == sourceName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("environment %s not found", sourceName)
+	}
+
+	stored, ok := m.options[sourceName]
+	if !ok {
+		stored = &models.DeploymentOptions{Name: sourceName}
+	}
+	options := stored.Clone(req.Name, req.Variables)
+	m.recordEvent(req.Name, "Normal", "Cloned", fmt.Sprintf("cloned from %s", sourceName))
+	if err := m.CreateEnvironment(req.Name, options); err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
 func (m *MockClient) DestroyEnvironment(name string) error {
 	// Simulate environment destruction
 	for i, env := range m.environments {
 		if env.Name == name {
 			m.environments = append(m.environments[:i], m.environments[i+1:]...)
+			m.recordEvent(name, "Normal", "DestroyCompleted", "destroy completed")
 			return nil
 		}
 	}
@@ -170,64 +364,78 @@ func (m *MockClient) GetPodLogs(namespace, podName string) (string, error) {
 	return logs, nil
 }
 
-func (m *MockClient) GetPodEvents(namespace, podName string) ([]models.Event, error) {
+func (m *MockClient) GetPodEvents(namespace, podName string) ([]models.EventTimeEntry, error) {
 	now := time.Now()
-	events := []models.Event{
+	events := []models.EventTimeEntry{
 		{
-			Type:      "Normal",
-			Reason:    "Pulled",
-			Message:   "Successfully pulled image \"nginx:latest\"",
-			Timestamp: now.Add(-2 * time.Minute),
-			Count:     1,
+			Type:              "Warning",
+			Reason:            "BackOff",
+			From:              "kubelet",
+			Message:           "Back-off restarting failed container",
+			Count:             3,
+			FirstObservedTime: now.Add(-1 * time.Hour),
+			LastObservedTime:  now.Add(-1 * time.Minute),
 		},
 		{
-			Type:      "Normal",
-			Reason:    "Created",
-			Message:   "Created container nginx",
-			Timestamp: now.Add(-5 * time.Minute),
-			Count:     1,
+			Type:              "Normal",
+			Reason:            "Pulled",
+			From:              "kubelet",
+			Message:           "Successfully pulled image \"nginx:latest\"",
+			Count:             1,
+			FirstObservedTime: now.Add(-2 * time.Minute),
+			LastObservedTime:  now.Add(-2 * time.Minute),
 		},
 		{
-			Type:      "Normal",
-			Reason:    "Started",
-			Message:   "Started container nginx",
-			Timestamp: now.Add(-5 * time.Minute),
-			Count:     1,
+			Type:              "Normal",
+			Reason:            "Created",
+			From:              "kubelet",
+			Message:           "Created container nginx",
+			Count:             1,
+			FirstObservedTime: now.Add(-5 * time.Minute),
+			LastObservedTime:  now.Add(-5 * time.Minute),
 		},
 		{
-			Type:      "Warning",
-			Reason:    "BackOff",
-			Message:   "Back-off restarting failed container",
-			Timestamp: now.Add(-1 * time.Hour),
-			Count:     3,
+			Type:              "Normal",
+			Reason:            "Started",
+			From:              "kubelet",
+			Message:           "Started container nginx",
+			Count:             1,
+			FirstObservedTime: now.Add(-5 * time.Minute),
+			LastObservedTime:  now.Add(-5 * time.Minute),
 		},
 		{
-			Type:      "Normal",
-			Reason:    "Scheduled",
-			Message:   fmt.Sprintf("Successfully assigned %s/%s to node-1", namespace, podName),
-			Timestamp: now.Add(-2 * time.Hour),
-			Count:     1,
+			Type:              "Normal",
+			Reason:            "Scheduled",
+			From:              "default-scheduler",
+			Message:           fmt.Sprintf("Successfully assigned %s/%s to node-1", namespace, podName),
+			Count:             1,
+			FirstObservedTime: now.Add(-2 * time.Hour),
+			LastObservedTime:  now.Add(-2 * time.Hour),
 		},
 	}
 	return events, nil
 }
 
-func (m *MockClient) GetDeploymentEvents(namespace, deploymentName string) ([]models.Event, error) {
+func (m *MockClient) GetDeploymentEvents(namespace, deploymentName string) ([]models.EventTimeEntry, error) {
 	now := time.Now()
-	events := []models.Event{
+	events := []models.EventTimeEntry{
 		{
-			Type:      "Normal",
-			Reason:    "ScalingReplicaSet",
-			Message:   fmt.Sprintf("Scaled up replica set %s to 2", deploymentName),
-			Timestamp: now.Add(-2 * time.Hour),
-			Count:     1,
+			Type:              "Normal",
+			Reason:            "ScalingReplicaSet",
+			From:              "deployment-controller",
+			Message:           fmt.Sprintf("Scaled up replica set %s to 2", deploymentName),
+			Count:             1,
+			FirstObservedTime: now.Add(-2 * time.Hour),
+			LastObservedTime:  now.Add(-2 * time.Hour),
 		},
 		{
-			Type:      "Normal",
-			Reason:    "ScalingReplicaSet",
-			Message:   fmt.Sprintf("Scaled down replica set %s to 1", deploymentName),
-			Timestamp: now.Add(-3 * time.Hour),
-			Count:     1,
+			Type:              "Normal",
+			Reason:            "ScalingReplicaSet",
+			From:              "deployment-controller",
+			Message:           fmt.Sprintf("Scaled down replica set %s to 1", deploymentName),
+			Count:             1,
+			FirstObservedTime: now.Add(-3 * time.Hour),
+			LastObservedTime:  now.Add(-3 * time.Hour),
 		},
 	}
 	return events, nil
@@ -254,7 +462,7 @@ func (m *MockClient) GetResourceStats(resourceType, namespace string) (*models.R
 		}
 		stats.TotalCount = len(pods)
 		for _, pod := range pods {
-			switch pod.Status {
+			switch pod.Phase {
 			case "Running":
 				stats.RunningPods++
 			case "Pending":
@@ -276,6 +484,9 @@ func (m *MockClient) GetResourceStats(resourceType, namespace string) (*models.R
 			stats.TotalReplicas += dep.UpToDate
 			stats.AvailableReplicas += dep.Available
 		}
+
+	case "services", "configmaps", "secrets", "ingresses", "jobs", "statefulsets":
+		stats.TotalCount = m.countResources(resourceType, namespace)
 	}
 
 	return stats, nil
@@ -329,25 +540,161 @@ func (m *MockClient) DeleteDeployment(namespace, deploymentName string) error {
 	return fmt.Errorf("deployment %s not found in namespace %s", deploymentName, namespace)
 }
 
-func (m *MockClient) GetPodMetrics(namespace string) ([]models.PodMetrics, error) {
-	// Return mock metrics for pods in the namespace
-	var metrics []models.PodMetrics
-	for _, env := range m.environments {
-		if namespace == "" || env.Namespace == namespace {
-			for _, pod := range env.Pods {
-				metrics = append(metrics, models.PodMetrics{
-					Name:                 pod.Name,
-					CPULimit:             "500m",
-					CPUUsed:              pod.CPU,
-					CPUUsedPercentage:    30.0, // Mock percentage
-					MemoryLimit:          "512Mi",
-					MemoryUsed:           pod.Memory,
-					MemoryUsedPercentage: 50.0, // Mock percentage
-				})
+func (m *MockClient) ScaleDeployment(namespace, deploymentName string, replicas int) error {
+	if replicas < 0 {
+		return fmt.Errorf("replicas must not be negative")
+	}
+
+	// Find the deployment and pretend the rollout finished instantly
+	for i := range m.environments {
+		env := &m.environments[i]
+		if env.Namespace == namespace {
+			for j := range env.Deployments {
+				dep := &env.Deployments[j]
+				if dep.Name == deploymentName {
+					dep.Replicas = replicas
+					dep.UpToDate = replicas
+					dep.Available = replicas
+					dep.Ready = fmt.Sprintf("%d/%d", replicas, replicas)
+					dep.RolloutComplete = true
+					return nil
+				}
+			}
+		}
+	}
+	return fmt.Errorf("deployment %s not found in namespace %s", deploymentName, namespace)
+}
+
+// findDeployment returns a pointer to a mock deployment so it can be modified in place
+func (m *MockClient) findDeployment(namespace, deploymentName string) (*models.Deployment, error) {
+	for i := range m.environments {
+		env := &m.environments[i]
+		if env.Namespace == namespace {
+			for j := range env.Deployments {
+				if env.Deployments[j].Name == deploymentName {
+					return &env.Deployments[j], nil
+				}
 			}
 		}
 	}
-	return metrics, nil
+	return nil, fmt.Errorf("deployment %s not found in namespace %s", deploymentName, namespace)
+}
+
+// deploymentRevisions returns the mock rollout history, seeding it from the current revision
+func (m *MockClient) deploymentRevisions(dep *models.Deployment) []models.DeploymentRevision {
+	if m.rollouts == nil {
+		m.rollouts = make(map[string][]models.DeploymentRevision)
+	}
+
+	key := dep.Namespace + "/" + dep.Name
+	if _, ok := m.rollouts[key]; !ok {
+		var revisions []models.DeploymentRevision
+		for rev := int64(1); rev <= dep.Revision; rev++ {
+			revisions = append(revisions, models.DeploymentRevision{
+				Revision:   rev,
+				ReplicaSet: fmt.Sprintf("%s-%d", dep.Name, 5000+rev),
+				Images:     []string{fmt.Sprintf("nginx:1.%d", 24+rev)},
+				Created:    dep.Age.Add(time.Duration(rev-1) * 10 * time.Minute),
+			})
+		}
+		m.rollouts[key] = revisions
+	}
+
+	return m.rollouts[key]
+}
+
+// addRevision records a new mock revision with the given images and makes it current
+func (m *MockClient) addRevision(dep *models.Deployment, images []string, changeCause string) {
+	revisions := m.deploymentRevisions(dep)
+	dep.Revision++
+	m.rollouts[dep.Namespace+"/"+dep.Name] = append(revisions, models.DeploymentRevision{
+		Revision:    dep.Revision,
+		ReplicaSet:  fmt.Sprintf("%s-%d", dep.Name, 5000+dep.Revision),
+		Images:      images,
+		ChangeCause: changeCause,
+		Created:     time.Now(),
+	})
+}
+
+func (m *MockClient) RestartDeployment(namespace, deploymentName string) error {
+	dep, err := m.findDeployment(namespace, deploymentName)
+	if err != nil {
+		return err
+	}
+	if dep.Paused {
+		return fmt.Errorf("cannot restart paused deployment %s (resume it first)", deploymentName)
+	}
+
+	// A restart creates a new revision running the same images
+	revisions := m.deploymentRevisions(dep)
+	var images []string
+	if len(revisions) > 0 {
+		images = revisions[len(revisions)-1].Images
+	}
+	m.addRevision(dep, images, "rollout restart")
+	return nil
+}
+
+func (m *MockClient) PauseDeployment(namespace, deploymentName string) error {
+	dep, err := m.findDeployment(namespace, deploymentName)
+	if err != nil {
+		return err
+	}
+	dep.Paused = true
+	return nil
+}
+
+func (m *MockClient) ResumeDeployment(namespace, deploymentName string) error {
+	dep, err := m.findDeployment(namespace, deploymentName)
+	if err != nil {
+		return err
+	}
+	dep.Paused = false
+	return nil
+}
+
+func (m *MockClient) GetDeploymentHistory(namespace, deploymentName string) ([]models.DeploymentRevision, error) {
+	dep, err := m.findDeployment(namespace, deploymentName)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := m.deploymentRevisions(dep)
+	history := make([]models.DeploymentRevision, len(revisions))
+	for i, rev := range revisions {
+		rev.Current = rev.Revision == dep.Revision
+		if rev.Current {
+			rev.Replicas = dep.Replicas
+		}
+		history[i] = rev
+	}
+	return history, nil
+}
+
+func (m *MockClient) RollbackDeployment(namespace, deploymentName string, revision int64) error {
+	dep, err := m.findDeployment(namespace, deploymentName)
+	if err != nil {
+		return err
+	}
+	if dep.Paused {
+		return fmt.Errorf("cannot roll back paused deployment %s (resume it first)", deploymentName)
+	}
+
+	if revision == 0 {
+		revision = dep.Revision - 1
+	}
+	if revision == dep.Revision {
+		return fmt.Errorf("deployment %s is already at revision %d", deploymentName, revision)
+	}
+
+	// Like the real controller, rolling back re-applies the old template as a new revision
+	for _, rev := range m.deploymentRevisions(dep) {
+		if rev.Revision == revision {
+			m.addRevision(dep, rev.Images, fmt.Sprintf("rollback to revision %d", revision))
+			return nil
+		}
+	}
+	return fmt.Errorf("revision %d not found for deployment %s", revision, deploymentName)
 }
 
 func (m *MockClient) GetOperationLogs(environmentName string) (*models.OperationLogs, error) {
@@ -359,3 +706,233 @@ func (m *MockClient) GetOperationLogs(environmentName string) (*models.Operation
 		Logs:        []string{},
 	}, nil
 }
+
+func (m *MockClient) ExecPod(ctx context.Context, req models.ExecRequest, streams ExecStreams) error {
+	found := false
+	for _, env := range m.environments {
+		for _, pod := range env.Pods {
+			if pod.Namespace == req.Namespace && pod.Name == req.Pod {
+				found = true
+			}
+		}
+	}
+	if !found {
+		return fmt.Errorf("pod %s not found in namespace %s", req.Pod, req.Namespace)
+	}
+
+	if streams.Stdin == nil {
+		if streams.Stdout != nil {
+			fmt.Fprintf(streams.Stdout, "mock exec: %s\n", strings.Join(req.Command, " "))
+		}
+		return nil
+	}
+	return runMockShell(ctx, req.Pod, streams)
+}
+
+// runMockShell emulates an interactive shell so exec sessions can be tried
+// without a cluster. It echoes input and understands a handful of commands.
+func runMockShell(ctx context.Context, podName string, streams ExecStreams) error {
+	out := streams.Stdout
+	if out == nil {
+		out = io.Discard
+	}
+	prompt := fmt.Sprintf("root@%s:/# ", podName)
+
+	input := make(chan byte)
+	go func() {
+		defer close(input)
+		buf := make([]byte, 256)
+		for {
+			n, err := streams.Stdin.Read(buf)
+			for _, b := range buf[:n] {
+				input <- b
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	fmt.Fprintf(out, "Connected to mock pod %s. Type 'exit' to leave.\r\n%s", podName, prompt)
+	var line []byte
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case b, ok := <-input:
+			if !ok {
+				return nil
+			}
+			switch b {
+			case '\r', '\n':
+				fmt.Fprint(out, "\r\n")
+				command := strings.TrimSpace(string(line))
+				line = line[:0]
+				if command == "exit" {
+					return nil
+				}
+				if output := mockShellCommand(command, podName); output != "" {
+					fmt.Fprintf(out, "%s\r\n", output)
+				}
+				fmt.Fprint(out, prompt)
+			case 0x04: // Ctrl-D
+				if len(line) == 0 {
+					fmt.Fprint(out, "exit\r\n")
+					return nil
+				}
+			case 0x03: // Ctrl-C
+				line = line[:0]
+				fmt.Fprintf(out, "^C\r\n%s", prompt)
+			case 0x7f, 0x08: // Backspace
+				if len(line) > 0 {
+					line = line[:len(line)-1]
+					fmt.Fprint(out, "\b \b")
+				}
+			default:
+				if b >= 0x20 {
+					line = append(line, b)
+					out.Write([]byte{b})
+				}
+			}
+		}
+	}
+}
+
+// mockShellCommand returns the output of a command in the mock shell
+func mockShellCommand(command, podName string) string {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return ""
+	}
+	switch fields[0] {
+	case "hostname":
+		return podName
+	case "pwd":
+		return "/"
+	case "whoami":
+		return "root"
+	case "ls":
+		return "bin  dev  etc  home  lib  proc  root  sys  tmp  usr  var"
+	case "echo":
+		return strings.Join(fields[1:], " ")
+	default:
+		return fmt.Sprintf("sh: %s: not found", fields[0])
+	}
+}
+
+// mockForward is a port-forward session backed by a local HTTP server that
+// stands in for the pod
+type mockForward struct {
+	info     models.PortForward
+	listener net.Listener
+}
+
+func (m *MockClient) StartPortForward(req models.PortForwardRequest) (*models.PortForward, error) {
+	if req.Kind == "" {
+		req.Kind = models.PortForwardKindPod
+	}
+	if req.Kind == models.PortForwardKindService {
+		if _, err := m.GetService(req.Namespace, req.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	var target *models.Pod
+	for _, env := range m.environments {
+		for i, pod := range env.Pods {
+			if pod.Namespace != req.Namespace {
+				continue
+			}
+			// Services resolve to the first pod in their namespace
+			if req.Kind == models.PortForwardKindService || pod.Name == req.Name {
+				target = &env.Pods[i]
+				break
+			}
+		}
+		if target != nil {
+			break
+		}
+	}
+	if target == nil {
+		return nil, fmt.Errorf("%s %s not found in namespace %s", req.Kind, req.Name, req.Namespace)
+	}
+	if req.Port <= 0 {
+		req.Port = 8080
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start mock forward target: %w", err)
+	}
+	body := fmt.Sprintf("Hello from mock %s %s/%s (pod %s, port %d)\n", req.Kind, req.Namespace, req.Name, target.Name, req.Port)
+	go http.Serve(listener, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	}))
+
+	m.forwardsMu.Lock()
+	defer m.forwardsMu.Unlock()
+	if m.forwards == nil {
+		m.forwards = make(map[string]*mockForward)
+	}
+	m.forwardSeq++
+	forward := &mockForward{
+		info: models.PortForward{
+			ID:         fmt.Sprintf("pf-%d", m.forwardSeq),
+			Namespace:  req.Namespace,
+			Kind:       req.Kind,
+			Name:       req.Name,
+			Pod:        target.Name,
+			Port:       req.Port,
+			TargetPort: req.Port,
+			Status:     models.PortForwardActive,
+			Started:    time.Now(),
+		},
+		listener: listener,
+	}
+	m.forwards[forward.info.ID] = forward
+
+	info := forward.info
+	return &info, nil
+}
+
+func (m *MockClient) ListPortForwards() ([]models.PortForward, error) {
+	m.forwardsMu.Lock()
+	defer m.forwardsMu.Unlock()
+
+	forwards := []models.PortForward{}
+	for _, forward := range m.forwards {
+		forwards = append(forwards, forward.info)
+	}
+	sort.Slice(forwards, func(i, j int) bool {
+		return forwards[i].Started.Before(forwards[j].Started)
+	})
+	return forwards, nil
+}
+
+func (m *MockClient) StopPortForward(id string) error {
+	m.forwardsMu.Lock()
+	defer m.forwardsMu.Unlock()
+
+	forward, ok := m.forwards[id]
+	if !ok {
+		return fmt.Errorf("port forward %s not found", id)
+	}
+	forward.listener.Close()
+	delete(m.forwards, id)
+	return nil
+}
+
+func (m *MockClient) DialPortForward(id string) (io.ReadWriteCloser, error) {
+	m.forwardsMu.Lock()
+	forward, ok := m.forwards[id]
+	m.forwardsMu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("port forward %s not found", id)
+	}
+	return net.Dial("tcp", forward.listener.Addr().String())
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"fmt"
+	"imperm-ui/pkg/models"
+	"strconv"
+)
+
+// What every mock node offers, and what its system pods already request
+const (
+	mockNodes             = 3
+	mockNodeCPUMillis     = 4000
+	mockNodeMemoryBytes   = 16 * 1024 * 1024 * 1024
+	mockNodePods          = 110
+	mockSystemCPUMillis   = 850
+	mockSystemMemoryBytes = 1024 * 1024 * 1024
+	mockSystemPods        = 8
+)
+
+// Per replica requests and limits of the namespace module's loggers
+const (
+	mockReplicaCPURequestMillis   = 50
+	mockReplicaMemoryRequestBytes = 32 * 1024 * 1024
+	mockReplicaCPULimitMillis     = 100
+	mockReplicaMemoryLimitBytes   = 64 * 1024 * 1024
+)
+
+// mockLoggers are the replica count variables of the namespace module
+var mockLoggers = []string{"constant_logger", "error_logger", "fast_logger", "json_logger"}
+
+// EstimateEnvironment checks the requested loggers against what the mock
+// nodes have left in total and prices them at the server's default rates
+func (m *MockClient) EstimateEnvironment(options *models.DeploymentOptions) (*models.CapacityEstimate, error) {
+	estimate := &models.CapacityEstimate{Reasons: []string{}}
+
+	for _, node := range mockClusterCapacity(m.environments).Nodes {
+		estimate.Nodes++
+		estimate.AvailableCPUMillis += node.AllocatableCPUMillis - node.RequestedCPUMillis
+		estimate.AvailableMemoryBytes += node.AllocatableMemoryBytes - node.RequestedMemoryBytes
+		estimate.AvailablePods += node.AllocatablePods - node.RequestedPods
+	}
+
+	if options != nil {
+		for _, logger := range mockLoggers {
+			value := options.Variable(logger)
+			if value == "" {
+				continue
+			}
+			replicas, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || replicas < 0 {
+				return nil, fmt.Errorf("%s must be a replica count, got %q", logger, value)
+			}
+			estimate.RequestedCPUMillis += replicas * mockReplicaCPURequestMillis
+			estimate.RequestedMemoryBytes += replicas * mockReplicaMemoryRequestBytes
+			estimate.RequestedPods += replicas
+			estimate.LimitCPUMillis += replicas * mockReplicaCPULimitMillis
+			estimate.LimitMemoryBytes += replicas * mockReplicaMemoryLimitBytes
+		}
+	}
+
+	if estimate.RequestedCPUMillis > estimate.AvailableCPUMillis {
+		estimate.Reasons = append(estimate.Reasons, fmt.Sprintf("cpu: requested %dm, available %dm",
+			estimate.RequestedCPUMillis, estimate.AvailableCPUMillis))
+	}
+	if estimate.RequestedMemoryBytes > estimate.AvailableMemoryBytes {
+		estimate.Reasons = append(estimate.Reasons, fmt.Sprintf("memory: requested %dMi, available %dMi",
+			estimate.RequestedMemoryBytes/(1024*1024), estimate.AvailableMemoryBytes/(1024*1024)))
+	}
+	if estimate.RequestedPods > estimate.AvailablePods {
+		estimate.Reasons = append(estimate.Reasons, fmt.Sprintf("pods: requested %d, available %d",
+			estimate.RequestedPods, estimate.AvailablePods))
+	}
+	estimate.Fits = len(estimate.Reasons) == 0
+
+	monthly := float64(estimate.RequestedCPUMillis)/1000*25 + float64(estimate.RequestedMemoryBytes)/(1024*1024*1024)*3.5
+	estimate.Cost = models.CostEstimate{Currency: "USD", PerCPUMonth: 25, PerGiBMonth: 3.5, Monthly: monthly, Hourly: monthly / 730}
+	return estimate, nil
+}
+
+// mockClusterCapacity spreads the mock environments' pods over a few
+// identically sized nodes
+func mockClusterCapacity(environments []models.Environment) *models.ClusterCapacity {
+	capacity := &models.ClusterCapacity{}
+	index := make(map[string]int)
+	for i := 1; i <= mockNodes; i++ {
+		name := fmt.Sprintf("mock-node-%d", i)
+		index[name] = len(capacity.Nodes)
+		capacity.Nodes = append(capacity.Nodes, models.NodeCapacity{
+			Name:                   name,
+			AllocatableCPUMillis:   mockNodeCPUMillis,
+			AllocatableMemoryBytes: mockNodeMemoryBytes,
+			AllocatablePods:        mockNodePods,
+			RequestedCPUMillis:     mockSystemCPUMillis,
+			RequestedMemoryBytes:   mockSystemMemoryBytes,
+			RequestedPods:          mockSystemPods,
+		})
+	}
+
+	for _, env := range environments {
+		for _, pod := range env.Pods {
+			i, ok := index[pod.Node]
+			if !ok {
+				continue
+			}
+			node := &capacity.Nodes[i]
+			node.RequestedCPUMillis += mockMillis(pod.CPURequest)
+			node.RequestedMemoryBytes += mockBytes(pod.MemoryRequest)
+			node.RequestedPods++
+		}
+	}
+	return capacity
+}
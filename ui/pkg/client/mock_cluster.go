@@ -0,0 +1,25 @@
+package client
+
+import "imperm-ui/pkg/models"
+
+// mockClusters are the clusters the mock pretends to manage; they all show the same simulated data
+var mockClusters = []models.Cluster{
+	{Name: "dev", Context: "kind-dev", Default: true},
+	{Name: "staging", Context: "staging-admin"},
+}
+
+func (m *MockClient) ListClusters() ([]models.Cluster, error) {
+	return mockClusters, nil
+}
+
+func (m *MockClient) SetCluster(name string) {
+	m.cluster = name
+}
+
+// currentCluster names the selected cluster, resolving the default
+func (m *MockClient) currentCluster() string {
+	if m.cluster != "" {
+		return m.cluster
+	}
+	return mockClusters[0].Name
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"imperm-ui/pkg/models"
+)
+
+// mockUnmanagedEnvironments are simulated namespaces imperm didn't create,
+// listed only when unmanaged namespaces are asked for
+func mockUnmanagedEnvironments() []models.Environment {
+	now := time.Now()
+	return []models.Environment{
+		{
+			Name:      "monitoring",
+			Namespace: "monitoring",
+			Status:    "Active",
+			Age:       now.Add(-30 * 24 * time.Hour),
+			Health:    models.EnvironmentHealth{Status: models.HealthHealthy},
+		},
+		{
+			Name:      "legacy-billing",
+			Namespace: "legacy-billing",
+			Status:    "Active",
+			Age:       now.Add(-90 * 24 * time.Hour),
+			Health:    models.EnvironmentHealth{Status: models.HealthHealthy},
+		},
+	}
+}
+
+// unmanagedEnvironments lists the simulated unmanaged namespaces not adopted yet
+func (m *MockClient) unmanagedEnvironments() []models.Environment {
+	var unmanaged []models.Environment
+	for _, env := range mockUnmanagedEnvironments() {
+		if !m.hasEnvironment(env.Name) {
+			unmanaged = append(unmanaged, env)
+		}
+	}
+	return unmanaged
+}
+
+func (m *MockClient) hasEnvironment(name string) bool {
+	for _, env := range m.environments {
+		if env.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AdoptEnvironment simulates adopting one of the unmanaged namespaces
+func (m *MockClient) AdoptEnvironment(name string) (*models.DeploymentOptions, error) {
+	if m.hasEnvironment(name) {
+		return nil, fmt.Errorf("namespace %s is already managed by imperm", name)
+	}
+	for _, env := range m.unmanagedEnvironments() {
+		if env.Name != name {
+			continue
+		}
+		options := &models.DeploymentOptions{Name: name, Variables: map[string]string{}}
+		m.environments = append(m.environments, env)
+		m.options[name] = options
+		m.recordEvent(name, "Normal", "AdoptCompleted", "adopt completed")
+		return options, nil
+	}
+	return nil, fmt.Errorf("failed to find namespace %s", name)
+}
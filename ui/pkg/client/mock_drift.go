@@ -0,0 +1,39 @@
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"imperm-ui/pkg/models"
+)
+
+// mockDrift simulates a logger scaled with kubectl behind Terraform's back
+func mockDrift(now time.Time) map[string]models.DriftStatus {
+	return map[string]models.DriftStatus{
+		"staging-env-1": {
+			Environment: "staging-env-1",
+			Drifted:     true,
+			Resources:   []string{"module.environment.kubernetes_deployment.constant_logger[0]"},
+			CheckedAt:   now.Add(-3 * time.Minute),
+		},
+	}
+}
+
+// driftOf returns the simulated drift check of an environment, nil when never checked
+func (m *MockClient) driftOf(name string) *models.DriftStatus {
+	status, ok := m.drift[name]
+	if !ok {
+		return nil
+	}
+	return &status
+}
+
+// ReconcileEnvironment simulates re-applying an environment, clearing its drift
+func (m *MockClient) ReconcileEnvironment(name string) error {
+	if !m.hasEnvironment(name) {
+		return fmt.Errorf("environment %s not found", name)
+	}
+	m.drift[name] = models.DriftStatus{Environment: name, Resources: []string{}, CheckedAt: time.Now()}
+	m.recordEvent(name, "Normal", "ReconcileCompleted", "reconcile completed")
+	return nil
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"imperm-ui/pkg/models"
+)
+
+// mockInterrupted simulates a reconcile cut short by a server restart
+func mockInterrupted(now time.Time) map[string]models.InterruptedOperation {
+	return map[string]models.InterruptedOperation{
+		"dev-env-1": {
+			Environment:  "dev-env-1",
+			Operation:    "reconcile",
+			Phase:        "apply",
+			StartedAt:    now.Add(-12 * time.Minute),
+			DetectedAt:   now.Add(-10 * time.Minute),
+			StaleLock:    true,
+			PartialState: true,
+		},
+	}
+}
+
+// interruptedOf returns the simulated interrupted operation of an environment, nil when there is none
+func (m *MockClient) interruptedOf(name string) *models.InterruptedOperation {
+	op, ok := m.interrupted[name]
+	if !ok {
+		return nil
+	}
+	return &op
+}
+
+// ResumeEnvironment simulates finishing an interrupted operation
+func (m *MockClient) ResumeEnvironment(name string) error {
+	if _, ok := m.interrupted[name]; !ok {
+		return fmt.Errorf("environment %s has no interrupted operation", name)
+	}
+	delete(m.interrupted, name)
+	m.recordEvent(name, "Normal", "ResumeCompleted", "resume completed")
+	return nil
+}
+
+// RollbackEnvironment simulates undoing an interrupted operation by destroying the environment
+func (m *MockClient) RollbackEnvironment(name string) error {
+	if _, ok := m.interrupted[name]; !ok {
+		return fmt.Errorf("environment %s has no interrupted operation", name)
+	}
+	if err := m.DestroyEnvironment(name); err != nil {
+		return err
+	}
+	delete(m.interrupted, name)
+	m.recordEvent(name, "Normal", "RollbackCompleted", "rollback completed")
+	return nil
+}
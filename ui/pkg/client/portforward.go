@@ -0,0 +1,111 @@
+package client
+
+import (
+	"fmt"
+	"imperm-ui/pkg/models"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// StartPortForward asks the middleware to open a port-forward session
+func (c *HTTPClient) StartPortForward(req models.PortForwardRequest) (*models.PortForward, error) {
+	var forward models.PortForward
+	if err := c.doJSON(http.MethodPost, "/api/portforwards", req, &forward); err != nil {
+		return nil, err
+	}
+	return &forward, nil
+}
+
+// ListPortForwards lists the port-forward sessions open on the middleware
+func (c *HTTPClient) ListPortForwards() ([]models.PortForward, error) {
+	var forwards []models.PortForward
+	if err := c.doJSON(http.MethodGet, "/api/portforwards", nil, &forwards); err != nil {
+		return nil, err
+	}
+
+	// Handle null response
+	if forwards == nil {
+		forwards = []models.PortForward{}
+	}
+
+	return forwards, nil
+}
+
+// StopPortForward closes a port-forward session on the middleware
+func (c *HTTPClient) StopPortForward(id string) error {
+	return c.doJSON(http.MethodDelete, "/api/portforwards?id="+url.QueryEscape(id), nil, nil)
+}
+
+// DialPortForward opens one connection through a port-forward session. The
+// stream is carried over a WebSocket so it passes through the middleware.
+func (c *HTTPClient) DialPortForward(id string) (io.ReadWriteCloser, error) {
+	connectURL, err := c.websocketURL("/api/portforwards/connect", url.Values{"id": {id}})
+	if err != nil {
+		return nil, err
+	}
+
+	conn, resp, err := websocket.DefaultDialer.Dial(connectURL, nil)
+	if err != nil {
+		if resp != nil {
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			return nil, fmt.Errorf("port forward connect failed: %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		}
+		return nil, fmt.Errorf("failed to connect port forward: %w", err)
+	}
+
+	return &websocketStream{conn: conn}, nil
+}
+
+// websocketStream adapts a WebSocket of binary frames to an io.ReadWriteCloser
+type websocketStream struct {
+	conn    *websocket.Conn
+	reader  io.Reader // Remainder of the frame being read
+	writeMu sync.Mutex
+}
+
+func (s *websocketStream) Read(p []byte) (int, error) {
+	for {
+		if s.reader == nil {
+			_, reader, err := s.conn.NextReader()
+			if err != nil {
+				if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+					return 0, io.EOF
+				}
+				return 0, err
+			}
+			s.reader = reader
+		}
+
+		n, err := s.reader.Read(p)
+		if err == io.EOF {
+			s.reader = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (s *websocketStream) Write(p []byte) (int, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if err := s.conn.WriteMessage(websocket.BinaryMessage, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s *websocketStream) Close() error {
+	s.writeMu.Lock()
+	s.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	s.writeMu.Unlock()
+	return s.conn.Close()
+}
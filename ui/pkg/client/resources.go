@@ -0,0 +1,180 @@
+package client
+
+import (
+	"imperm-ui/pkg/models"
+	"net/http"
+	"net/url"
+)
+
+// ListServices fetches services in a namespace (all namespaces if empty)
+func (c *HTTPClient) ListServices(namespace string) ([]models.Service, error) {
+	var items []models.Service
+	if err := c.doJSON(http.MethodGet, "/api/services?"+namespaceQuery(namespace), nil, &items); err != nil {
+		return nil, err
+	}
+
+	// Handle null response
+	if items == nil {
+		items = []models.Service{}
+	}
+
+	return items, nil
+}
+
+// GetService fetches a single service
+func (c *HTTPClient) GetService(namespace, name string) (*models.Service, error) {
+	var item models.Service
+	if err := c.doJSON(http.MethodGet, "/api/services/get?"+objectQuery(namespace, name), nil, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// ListConfigMaps fetches config maps in a namespace (all namespaces if empty)
+func (c *HTTPClient) ListConfigMaps(namespace string) ([]models.ConfigMap, error) {
+	var items []models.ConfigMap
+	if err := c.doJSON(http.MethodGet, "/api/configmaps?"+namespaceQuery(namespace), nil, &items); err != nil {
+		return nil, err
+	}
+
+	// Handle null response
+	if items == nil {
+		items = []models.ConfigMap{}
+	}
+
+	return items, nil
+}
+
+// GetConfigMap fetches a single config map
+func (c *HTTPClient) GetConfigMap(namespace, name string) (*models.ConfigMap, error) {
+	var item models.ConfigMap
+	if err := c.doJSON(http.MethodGet, "/api/configmaps/get?"+objectQuery(namespace, name), nil, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// ListSecrets fetches secrets in a namespace (all namespaces if empty)
+func (c *HTTPClient) ListSecrets(namespace string) ([]models.Secret, error) {
+	var items []models.Secret
+	if err := c.doJSON(http.MethodGet, "/api/secrets?"+namespaceQuery(namespace), nil, &items); err != nil {
+		return nil, err
+	}
+
+	// Handle null response
+	if items == nil {
+		items = []models.Secret{}
+	}
+
+	return items, nil
+}
+
+// GetSecret fetches a secret. Values are masked unless reveal is set.
+func (c *HTTPClient) GetSecret(namespace, name string, reveal bool) (*models.Secret, error) {
+	query := objectQuery(namespace, name)
+	if reveal {
+		query += "&reveal=true"
+	}
+	var secret models.Secret
+	if err := c.doJSON(http.MethodGet, "/api/secrets/get?"+query, nil, &secret); err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// ListIngresses fetches ingresses in a namespace (all namespaces if empty)
+func (c *HTTPClient) ListIngresses(namespace string) ([]models.Ingress, error) {
+	var items []models.Ingress
+	if err := c.doJSON(http.MethodGet, "/api/ingresses?"+namespaceQuery(namespace), nil, &items); err != nil {
+		return nil, err
+	}
+
+	// Handle null response
+	if items == nil {
+		items = []models.Ingress{}
+	}
+
+	return items, nil
+}
+
+// GetIngress fetches a single ingress
+func (c *HTTPClient) GetIngress(namespace, name string) (*models.Ingress, error) {
+	var item models.Ingress
+	if err := c.doJSON(http.MethodGet, "/api/ingresses/get?"+objectQuery(namespace, name), nil, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// ListJobs fetches jobs in a namespace (all namespaces if empty)
+func (c *HTTPClient) ListJobs(namespace string) ([]models.Job, error) {
+	var items []models.Job
+	if err := c.doJSON(http.MethodGet, "/api/jobs?"+namespaceQuery(namespace), nil, &items); err != nil {
+		return nil, err
+	}
+
+	// Handle null response
+	if items == nil {
+		items = []models.Job{}
+	}
+
+	return items, nil
+}
+
+// GetJob fetches a single job
+func (c *HTTPClient) GetJob(namespace, name string) (*models.Job, error) {
+	var item models.Job
+	if err := c.doJSON(http.MethodGet, "/api/jobs/get?"+objectQuery(namespace, name), nil, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// ListStatefulSets fetches stateful sets in a namespace (all namespaces if empty)
+func (c *HTTPClient) ListStatefulSets(namespace string) ([]models.StatefulSet, error) {
+	var items []models.StatefulSet
+	if err := c.doJSON(http.MethodGet, "/api/statefulsets?"+namespaceQuery(namespace), nil, &items); err != nil {
+		return nil, err
+	}
+
+	// Handle null response
+	if items == nil {
+		items = []models.StatefulSet{}
+	}
+
+	return items, nil
+}
+
+// GetStatefulSet fetches a single stateful set
+func (c *HTTPClient) GetStatefulSet(namespace, name string) (*models.StatefulSet, error) {
+	var item models.StatefulSet
+	if err := c.doJSON(http.MethodGet, "/api/statefulsets/get?"+objectQuery(namespace, name), nil, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// namespaceQuery encodes the namespace filter for list endpoints
+func namespaceQuery(namespace string) string {
+	return url.Values{"namespace": {namespace}}.Encode()
+}
+
+// objectQuery encodes the namespace and name of a single object
+func objectQuery(namespace, name string) string {
+	return url.Values{"namespace": {namespace}, "name": {name}}.Encode()
+}
+
+// GetResourceManifest fetches the live YAML and describe summary of a resource.
+// Secret values are masked unless reveal is set.
+func (c *HTTPClient) GetResourceManifest(kind, namespace, name string, reveal bool) (*models.ResourceManifest, error) {
+	path := "/api/resources/" + url.PathEscape(kind) + "/" + url.PathEscape(namespace) + "/" + url.PathEscape(name)
+	if reveal {
+		path += "?reveal=true"
+	}
+
+	var manifest models.ResourceManifest
+	if err := c.doJSON(http.MethodGet, path, nil, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
@@ -0,0 +1,43 @@
+package models
+
+// Actions reported for each object of an apply
+const (
+	ApplyCreated    = "created"
+	ApplyConfigured = "configured"
+	ApplyUnchanged  = "unchanged"
+	ApplyFailed     = "failed"
+)
+
+// ApplyManifestRequest carries multi-document YAML to apply into an environment
+type ApplyManifestRequest struct {
+	Manifest string `json:"manifest"`
+	DryRun   bool   `json:"dryRun"` // Validate on the server and return diffs without persisting
+}
+
+// ApplyResult reports what an apply changed, or would change for a dry run
+type ApplyResult struct {
+	Environment string          `json:"environment"`
+	Namespace   string          `json:"namespace"`
+	DryRun      bool            `json:"dryRun"`
+	Objects     []AppliedObject `json:"objects"`
+}
+
+// AppliedObject is the outcome for one document of the manifest
+type AppliedObject struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Action     string `json:"action"` // created, configured, unchanged or failed
+	Diff       string `json:"diff"`   // Unified diff of the live object against the applied one
+	Error      string `json:"error"`
+}
+
+// Failed reports whether any object of the apply failed
+func (r *ApplyResult) Failed() bool {
+	for _, obj := range r.Objects {
+		if obj.Action == ApplyFailed {
+			return true
+		}
+	}
+	return false
+}
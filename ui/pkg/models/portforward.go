@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Port forward target kinds
+const (
+	PortForwardKindPod     = "pod"
+	PortForwardKindService = "service"
+)
+
+// Port forward session states
+const (
+	PortForwardActive = "Active"
+	PortForwardClosed = "Closed"
+	PortForwardFailed = "Failed"
+)
+
+// PortForwardRequest asks the server to open a port-forward session
+type PortForwardRequest struct {
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"` // "pod" or "service"
+	Name      string `json:"name"`
+	Port      int    `json:"port"` // Pod port, or service port for services (0 = first service port)
+}
+
+// PortForward is a port-forward session held open by the server
+type PortForward struct {
+	ID         string    `json:"id"`
+	Namespace  string    `json:"namespace"`
+	Kind       string    `json:"kind"`
+	Name       string    `json:"name"`
+	Pod        string    `json:"pod"`        // Pod the traffic is forwarded to
+	Port       int       `json:"port"`       // Requested port (the service port for services)
+	TargetPort int       `json:"targetPort"` // Container port on the pod
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	Started    time.Time `json:"started"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// Types of structured operation events, as in terraform's machine-readable UI
+const (
+	EventPlannedChange = "planned_change" // A resource the operation is going to change
+	EventApplyStart    = "apply_start"
+	EventApplyComplete = "apply_complete"
+	EventApplyErrored  = "apply_errored"
+	EventDiagnostic    = "diagnostic"
+	EventChangeSummary = "change_summary"
+)
+
+// States of a resource during an operation
+const (
+	ResourcePlanned  = "planned"
+	ResourceApplying = "applying"
+	ResourceComplete = "complete"
+	ResourceErrored  = "errored"
+)
+
+// OperationEvent is one structured event of a Terraform operation
+type OperationEvent struct {
+	Time       time.Time      `json:"time"`
+	Type       string         `json:"type"`
+	Message    string         `json:"message"`
+	Address    string         `json:"address,omitempty"` // Resource the event is about
+	Action     string         `json:"action,omitempty"`  // create, update, delete, replace, ...
+	Elapsed    int            `json:"elapsed_seconds,omitempty"`
+	Diagnostic *Diagnostic    `json:"diagnostic,omitempty"`
+	Summary    *ChangeSummary `json:"summary,omitempty"`
+}
+
+// Diagnostic is a warning or error reported by Terraform
+type Diagnostic struct {
+	Severity string       `json:"severity"` // error or warning
+	Summary  string       `json:"summary"`
+	Detail   string       `json:"detail,omitempty"`
+	Address  string       `json:"address,omitempty"`
+	Range    *SourceRange `json:"range,omitempty"` // Configuration the diagnostic points at
+}
+
+// SourceRange is a span of a configuration file
+type SourceRange struct {
+	Filename    string `json:"filename"`
+	StartLine   int    `json:"start_line"`
+	StartColumn int    `json:"start_column"`
+	EndLine     int    `json:"end_line"`
+	EndColumn   int    `json:"end_column"`
+}
+
+// ChangeSummary counts the changes of a plan, apply or destroy
+type ChangeSummary struct {
+	Operation string `json:"operation"` // plan, apply or destroy
+	Add       int    `json:"add"`
+	Change    int    `json:"change"`
+	Remove    int    `json:"remove"`
+	Import    int    `json:"import"`
+}
+
+// ResourceProgress is where one resource stands in an operation
+type ResourceProgress struct {
+	Address string `json:"address"`
+	Action  string `json:"action"`
+	Status  string `json:"status"` // planned, applying, complete or errored
+	Elapsed int    `json:"elapsed_seconds"`
+}
+
+// OperationProgress rolls up the structured events of an operation
+type OperationProgress struct {
+	Total       int                `json:"total"` // Resources the operation changes
+	Done        int                `json:"done"`
+	Errored     int                `json:"errored"`
+	Resources   []ResourceProgress `json:"resources"` // In the order they were first seen
+	Diagnostics []Diagnostic       `json:"diagnostics"`
+	Summary     *ChangeSummary     `json:"summary,omitempty"` // Outcome, once the operation finished
+}
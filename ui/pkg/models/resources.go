@@ -0,0 +1,94 @@
+package models
+
+import "time"
+
+// SecretMask replaces secret values that haven't been explicitly revealed
+const SecretMask = "********"
+
+// Service represents a Kubernetes service
+type Service struct {
+	Name       string            `json:"name"`
+	Namespace  string            `json:"namespace"`
+	Type       string            `json:"type"` // ClusterIP, NodePort, LoadBalancer, ExternalName
+	ClusterIP  string            `json:"clusterIP"`
+	ExternalIP string            `json:"externalIP"`
+	Ports      []ServicePort     `json:"ports"`
+	Selector   map[string]string `json:"selector"`
+	Age        time.Time         `json:"age"`
+}
+
+// ServicePort is one port exposed by a service
+type ServicePort struct {
+	Name       string `json:"name"`
+	Protocol   string `json:"protocol"`
+	Port       int    `json:"port"`
+	TargetPort string `json:"targetPort"` // Number or named container port
+	NodePort   int    `json:"nodePort"`
+}
+
+// ConfigMap represents a Kubernetes config map
+type ConfigMap struct {
+	Name       string            `json:"name"`
+	Namespace  string            `json:"namespace"`
+	Data       map[string]string `json:"data"`
+	BinaryKeys []string          `json:"binaryKeys"` // Keys of binaryData, whose values aren't shown
+	Age        time.Time         `json:"age"`
+}
+
+// Secret represents a Kubernetes secret. Values are SecretMask unless Revealed.
+type Secret struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Type      string            `json:"type"`
+	Data      map[string]string `json:"data"`
+	Revealed  bool              `json:"revealed"`
+	Age       time.Time         `json:"age"`
+}
+
+// Ingress represents a Kubernetes ingress
+type Ingress struct {
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace"`
+	Class     string        `json:"class"`
+	Hosts     []string      `json:"hosts"`
+	Address   string        `json:"address"`
+	TLS       bool          `json:"tls"`
+	Rules     []IngressRule `json:"rules"`
+	Age       time.Time     `json:"age"`
+}
+
+// IngressRule routes a host and path to a backend service
+type IngressRule struct {
+	Host    string `json:"host"`
+	Path    string `json:"path"`
+	Service string `json:"service"`
+	Port    string `json:"port"`
+}
+
+// Job represents a Kubernetes job
+type Job struct {
+	Name        string     `json:"name"`
+	Namespace   string     `json:"namespace"`
+	Status      string     `json:"status"` // Running, Complete, Failed, Suspended
+	Completions string     `json:"completions"`
+	Active      int        `json:"active"`
+	Succeeded   int        `json:"succeeded"`
+	Failed      int        `json:"failed"`
+	StartTime   *time.Time `json:"startTime"`
+	EndTime     *time.Time `json:"endTime"`
+	Age         time.Time  `json:"age"`
+}
+
+// StatefulSet represents a Kubernetes stateful set
+type StatefulSet struct {
+	Name            string    `json:"name"`
+	Namespace       string    `json:"namespace"`
+	Ready           string    `json:"ready"`
+	Replicas        int       `json:"replicas"`
+	ReadyReplicas   int       `json:"readyReplicas"`
+	UpdatedReplicas int       `json:"updatedReplicas"`
+	ServiceName     string    `json:"serviceName"`
+	CurrentRevision string    `json:"currentRevision"`
+	UpdateRevision  string    `json:"updateRevision"`
+	Age             time.Time `json:"age"`
+}